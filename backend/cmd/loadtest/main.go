@@ -0,0 +1,40 @@
+// Command loadtest runs the game loop headless with synthetic players and reports its cost.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"goblons/internal/game"
+)
+
+func main() {
+	config := game.LoadTestConfig{World: game.DefaultWorldConfig()}
+	config.World.Bots.Count = 0
+
+	flag.IntVar(&config.Players, "players", 32, "number of synthetic players")
+	flag.IntVar(&config.World.Bots.Count, "bots", config.World.Bots.Count, "number of AI bots")
+	flag.IntVar(&config.Ticks, "ticks", 900, "number of ticks to run")
+	flag.IntVar(&config.Items, "items", game.MaxItems, "items in the world at the start")
+	flag.Float64Var(&config.AutofireFraction, "autofire", 0.5, "share of players with autofire on (0-1)")
+	flag.Int64Var(&config.World.Seed, "seed", 1, "random seed (0 = time-based)")
+	flag.IntVar(&config.World.TickRate, "tickrate", config.World.TickRate, "simulation steps per second")
+	verbose := flag.Bool("v", false, "keep game log output")
+	flag.Parse()
+
+	if !*verbose {
+		log.SetOutput(io.Discard)
+	}
+
+	result := game.RunLoadTest(config)
+
+	fmt.Printf("%d players, %d bots, %d ticks in %v\n", config.Players, config.World.Bots.Count, result.Ticks, result.Elapsed)
+	fmt.Printf("per tick:   %v (budget %v)\n", result.PerTick, time.Second/time.Duration(max(config.World.TickRate, 1)))
+	fmt.Printf("allocs:     %d (%d per tick)\n", result.Allocs, result.Allocs/uint64(max(result.Ticks, 1)))
+	fmt.Printf("alloc size: %.1f MiB\n", float64(result.AllocBytes)/(1<<20))
+	fmt.Printf("bullets:    %d peak\n", result.PeakBullets)
+	fmt.Printf("snapshots:  %d queued\n", result.Snapshots)
+}
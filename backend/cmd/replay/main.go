@@ -0,0 +1,62 @@
+// Command replay prints the contents of a snapshot recording made with -record.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"goblons/internal/game"
+)
+
+func main() {
+	verbose := flag.Bool("v", false, "print every player in each frame")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: replay [-v] <recording>")
+		os.Exit(2)
+	}
+
+	file, err := os.Open(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var start int64
+	frames := 0
+
+	for {
+		frame, err := game.ReadRecordedFrame(reader)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			log.Fatalf("frame %d: %v", frames, err)
+		}
+
+		if frames == 0 {
+			start = frame.Time.UnixMilli()
+		}
+		frames++
+
+		snapshot := frame.Snapshot
+		fmt.Printf("t=%8.3fs players=%d items=%d bullets=%d\n",
+			float64(frame.Time.UnixMilli()-start)/1000.0, len(snapshot.Players), len(snapshot.Items), len(snapshot.Bullets))
+
+		if *verbose {
+			for _, player := range snapshot.Players {
+				fmt.Printf("    #%d %-16s state=%d pos=(%.0f, %.0f) hp=%.0f/%.0f score=%d\n",
+					player.ID, player.Name, player.State, player.X, player.Y, player.Health, player.MaxHealth, player.Score)
+			}
+		}
+	}
+
+	fmt.Printf("%d frames\n", frames)
+}
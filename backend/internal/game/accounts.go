@@ -0,0 +1,281 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// Account token limits; tokens are generated by the client and kept private
+const (
+	MinAccountTokenLength = 16
+	MaxAccountTokenLength = 64
+)
+
+// PresetColors are the hull colors every player may use, account or not
+var PresetColors = []string{"#FF6B6B", "#4ECDC4", "#45B7D1", "#96CEB4", "#FFEAA7", "#DDA0DD", "#98D8C8", "#F7DC6F"}
+
+// CosmeticFlags are the flags a ship can fly once its account unlocks them
+var CosmeticFlags = []string{"jollyRoger", "skull", "crown", "anchor"}
+
+// Account holds the cosmetics a returning player has unlocked, keyed by a private token
+type Account struct {
+	Token  string   `json:"token"`
+	Colors []string `json:"colors,omitempty"` // Unlocked hull colors beyond PresetColors
+	Flags  []string `json:"flags,omitempty"`  // Unlocked flags
+
+	Loadout *LoadoutPreset `json:"loadout,omitempty"` // Preferred build, replayed every life
+}
+
+// HasColor reports whether a sanitized color is a preset or unlocked for the account
+func (account *Account) HasColor(color string) bool {
+	if slices.Contains(PresetColors, color) {
+		return true
+	}
+	return account != nil && slices.Contains(account.Colors, color)
+}
+
+// HasFlag reports whether the account has unlocked a flag
+func (account *Account) HasFlag(flag string) bool {
+	return account != nil && slices.Contains(account.Flags, flag)
+}
+
+// AccountStore loads and saves accounts. Implementations must be safe for
+// concurrent use, since every room shares one store.
+type AccountStore interface {
+	LoadAccount(token string) (*Account, error) // Returns nil, nil for an unknown token
+	SaveAccount(account *Account) error
+}
+
+// validAccountToken accepts tokens of a sane length made of URL-safe characters
+func validAccountToken(token string) bool {
+	if len(token) < MinAccountTokenLength || len(token) > MaxAccountTokenLength {
+		return false
+	}
+	for _, r := range token {
+		isAlphanumeric := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+		if !isAlphanumeric && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// LoadAccount returns the account for a token, creating it on first use.
+// Returns nil when accounts are off or the token is missing or invalid.
+func LoadAccount(store AccountStore, token string) *Account {
+	if store == nil || !validAccountToken(token) {
+		return nil
+	}
+
+	account, err := store.LoadAccount(token)
+	if err != nil {
+		slog.Error("Could not load account", "err", err)
+		return nil
+	}
+	if account == nil {
+		account = &Account{Token: token}
+	}
+	return account
+}
+
+// MemoryAccountStore keeps accounts in memory; unlocks are lost on restart
+type MemoryAccountStore struct {
+	mu       sync.Mutex
+	accounts map[string]Account
+}
+
+// NewMemoryAccountStore creates an empty in-memory store
+func NewMemoryAccountStore() *MemoryAccountStore {
+	return &MemoryAccountStore{accounts: make(map[string]Account)}
+}
+
+func (store *MemoryAccountStore) LoadAccount(token string) (*Account, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	account, exists := store.accounts[token]
+	if !exists {
+		return nil, nil
+	}
+	account.Colors = slices.Clone(account.Colors)
+	account.Flags = slices.Clone(account.Flags)
+	account.Loadout = account.Loadout.clone()
+	return &account, nil
+}
+
+func (store *MemoryAccountStore) SaveAccount(account *Account) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	saved := *account
+	saved.Colors = slices.Clone(account.Colors)
+	saved.Flags = slices.Clone(account.Flags)
+	saved.Loadout = account.Loadout.clone()
+	store.accounts[account.Token] = saved
+	return nil
+}
+
+// FileAccountStore is a MemoryAccountStore that persists every save to a JSON file
+type FileAccountStore struct {
+	*MemoryAccountStore
+	path string
+}
+
+// NewFileAccountStore loads accounts from path, starting empty if the file doesn't exist yet
+func NewFileAccountStore(path string) (*FileAccountStore, error) {
+	store := &FileAccountStore{MemoryAccountStore: NewMemoryAccountStore(), path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts []Account
+	if err := json.Unmarshal(data, &accounts); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	for _, account := range accounts {
+		store.accounts[account.Token] = account
+	}
+	return store, nil
+}
+
+func (store *FileAccountStore) SaveAccount(account *Account) error {
+	store.MemoryAccountStore.SaveAccount(account)
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	accounts := make([]Account, 0, len(store.accounts))
+	for _, saved := range store.accounts {
+		accounts = append(accounts, saved)
+	}
+	data, err := json.MarshalIndent(accounts, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a crash never leaves a truncated store
+	tmp, err := os.CreateTemp(filepath.Dir(store.path), filepath.Base(store.path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), store.path)
+}
+
+// CanUseColor reports whether the client may sail under a sanitized color.
+// Outside ranked cosmetics any color is allowed.
+func (client *Client) CanUseColor(color string, ranked bool) bool {
+	return !ranked || client.Account.HasColor(color)
+}
+
+// ChooseColor sails under a sanitized color if the client may use it, and
+// otherwise keeps the current color and tells the player why
+func (client *Client) ChooseColor(color string, ranked bool) {
+	if client.CanUseColor(color, ranked) {
+		client.Player.Color = color
+		return
+	}
+	client.sendGameEvent(GameEventMsg{EventType: "colorRejected"})
+}
+
+// clone returns a deep copy, so an account can be changed without w.mu
+func (account *Account) clone() *Account {
+	if account == nil {
+		return nil
+	}
+	copy := *account
+	copy.Colors = slices.Clone(account.Colors)
+	copy.Flags = slices.Clone(account.Flags)
+	copy.Loadout = account.Loadout.clone()
+	return &copy
+}
+
+// updateAccount applies change to the stored copy of an account (falling back
+// to the given one) so unlocks made over another connection aren't lost, then
+// saves it. Stores may write to disk, so the caller must hold w.accountsMu and
+// not w.mu.
+func (w *World) updateAccount(fallback *Account, change func(*Account) error) (*Account, error) {
+	account, err := w.config.Accounts.LoadAccount(fallback.Token)
+	if err != nil {
+		return nil, fmt.Errorf("loading account: %w", err)
+	}
+	if account == nil {
+		account = fallback
+	}
+	if err := change(account); err != nil {
+		return nil, err
+	}
+	if err := w.config.Accounts.SaveAccount(account); err != nil {
+		return nil, fmt.Errorf("saving account: %w", err)
+	}
+	return account, nil
+}
+
+// unlockCosmetic adds a color or flag to a connected player's account and saves
+// it. It takes w.mu itself, and only around reading and updating the client.
+func (w *World) unlockCosmetic(playerID uint32, color, flag string) error {
+	sanitized := SanitizePlayerColor(color)
+	switch {
+	case color != "" && sanitized == "":
+		return fmt.Errorf("invalid color %q", color)
+	case color == "" && flag != "" && !slices.Contains(CosmeticFlags, flag):
+		return fmt.Errorf("unknown flag %q", flag)
+	case color == "" && flag == "":
+		return fmt.Errorf("nothing to unlock")
+	}
+
+	w.mu.RLock()
+	client, exists := w.clients[playerID]
+	var stored *Account
+	if exists {
+		stored = client.Account.clone()
+	}
+	w.mu.RUnlock()
+	if !exists {
+		return fmt.Errorf("no connected player %d", playerID)
+	}
+	if stored == nil || w.config.Accounts == nil {
+		return fmt.Errorf("player %d has no account", playerID)
+	}
+
+	w.accountsMu.Lock()
+	account, err := w.updateAccount(stored, func(account *Account) error {
+		if sanitized != "" {
+			if !account.HasColor(sanitized) {
+				account.Colors = append(account.Colors, sanitized)
+			}
+		} else if !account.HasFlag(flag) {
+			account.Flags = append(account.Flags, flag)
+		}
+		return nil
+	})
+	w.accountsMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	client.Account = account
+	client.sendUnlocks(w.config.RankedCosmetics)
+	return nil
+}
@@ -0,0 +1,27 @@
+package game
+
+import "testing"
+
+func TestRankedColorRejectionIsReported(t *testing.T) {
+	w := newTestWorld(t, func(config *WorldConfig) {
+		config.RankedCosmetics = true
+	})
+	client := addTestClient(t, w, 1000, 1000)
+	queuedMessages(client)
+
+	w.applyProfile(client, InputMsg{PlayerColor: PresetColors[1]})
+	if client.Player.Color != PresetColors[1] {
+		t.Fatalf("preset color refused, color = %s", client.Player.Color)
+	}
+	if _, rejected := findEvent(gameEvents(t, client), "colorRejected"); rejected {
+		t.Error("preset color reported as rejected")
+	}
+
+	w.applyProfile(client, InputMsg{PlayerColor: "#123456"})
+	if client.Player.Color != PresetColors[1] {
+		t.Errorf("locked color applied, color = %s", client.Player.Color)
+	}
+	if _, rejected := findEvent(gameEvents(t, client), "colorRejected"); !rejected {
+		t.Error("player was not told the color was rejected")
+	}
+}
@@ -0,0 +1,231 @@
+package game
+
+import (
+	"cmp"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// AdminCommandType names a runtime admin command
+type AdminCommandType string
+
+const (
+	AdminSpawnItem  AdminCommandType = "spawnItem"  // Drop items at a position (default: next to the admin)
+	AdminSetLevel   AdminCommandType = "setLevel"   // Set a player's level
+	AdminKick       AdminCommandType = "kick"       // Disconnect a player
+	AdminToggleBots AdminCommandType = "toggleBots" // Remove every bot or respawn the configured bots
+	AdminWatchBot   AdminCommandType = "watchBot"   // Stream a bot's AI state to the admin (playerId 0 stops)
+	AdminSetRewards AdminCommandType = "setRewards" // Change the XP and coin bonus multipliers
+	AdminUnlock     AdminCommandType = "unlock"     // Unlock a hull color or flag for a player's account
+)
+
+// AdminCommand is a privileged request. Only clients that connected with the
+// server's admin token may run one; everyone else is rejected.
+type AdminCommand struct {
+	Command  AdminCommandType `msgpack:"command"`
+	PlayerID uint32           `msgpack:"playerId,omitempty"` // Target of setLevel, kick and watchBot; spawnItem reserves the items for this player
+	Level    int              `msgpack:"level,omitempty"`
+	ItemType string           `msgpack:"itemType,omitempty"`
+	Count    int              `msgpack:"count,omitempty"` // Items to spawn (default 1)
+	X        *float64         `msgpack:"x,omitempty"`
+	Y        *float64         `msgpack:"y,omitempty"`
+	Enabled  bool             `msgpack:"enabled,omitempty"` // Whether toggleBots turns bots on
+
+	XPMultiplier   *float64 `msgpack:"xpMultiplier,omitempty"`   // New setRewards XP multiplier (unset = unchanged)
+	CoinMultiplier *float64 `msgpack:"coinMultiplier,omitempty"` // New setRewards coin multiplier (unset = unchanged)
+
+	Color string `msgpack:"color,omitempty"` // Hull color to unlock
+	Flag  string `msgpack:"flag,omitempty"`  // Flag to unlock
+}
+
+// PlayerInfo describes a connected player for moderation tools
+type PlayerInfo struct {
+	ID    uint32 `json:"id"`
+	Name  string `json:"name"`
+	Score int    `json:"score"`
+	IP    string `json:"ip"`
+}
+
+// ListPlayers returns every connected (non-bot) player, ordered by ID
+func (w *World) ListPlayers() []PlayerInfo {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	players := make([]PlayerInfo, 0, len(w.clients))
+	for _, client := range w.clients {
+		players = append(players, PlayerInfo{
+			ID:    client.ID,
+			Name:  client.Player.Name,
+			Score: client.Player.Score,
+			IP:    client.IP,
+		})
+	}
+	slices.SortFunc(players, func(a, b PlayerInfo) int { return cmp.Compare(a.ID, b.ID) })
+	return players
+}
+
+// Kick disconnects a connected player with the kicked error. Closing the send
+// channel makes the write goroutine send the close frame, which in turn ends
+// the read goroutine. Returns false if no such client is connected.
+func (w *World) Kick(id uint32) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	client, exists := w.clients[id]
+	if !exists {
+		return false
+	}
+	w.disconnectClient(client, ErrorKicked)
+	return true
+}
+
+// maxRewardMultiplier caps the bonus event multipliers an admin can set
+const maxRewardMultiplier = 10.0
+
+// maxAdminItemSpawn caps how many items one spawnItem command can create
+const maxAdminItemSpawn = 50
+
+// adminItemValues is the coin and XP value of each item type an admin can spawn
+var adminItemValues = map[string]int{
+	ItemTypeGrayCircle:   10,
+	ItemTypeYellowCircle: 10,
+	ItemTypeOrangeCircle: 20,
+	ItemTypeBlueDiamond:  30,
+	ItemTypeGoldStar:     100,
+	ItemTypeShield:       5,
+	ItemTypeSpeedBoost:   5,
+	ItemTypeRapidReload:  5,
+}
+
+// handleAdminCommand runs an admin command and reports the outcome to the sender
+func (w *World) handleAdminCommand(sender *Client, command *AdminCommand) {
+	if command == nil {
+		return
+	}
+	if !sender.IsAdmin {
+		slog.Warn("Rejected admin command from non-admin client", "client", sender.ID, "command", command.Command)
+		sender.sendAdminResult(AdminResultMsg{Command: command.Command, Message: "Not authorized"})
+		return
+	}
+
+	var err error
+	if command.Command == AdminUnlock {
+		// Saving the account may touch the disk, so it runs outside the world lock
+		err = w.unlockCosmetic(command.PlayerID, command.Color, command.Flag)
+	} else {
+		err = w.runAdminCommandLocked(sender, command)
+	}
+
+	result := AdminResultMsg{Command: command.Command, OK: err == nil, Message: "Done"}
+	if err != nil {
+		result.Message = err.Error()
+	}
+	slog.Info("Admin ran command", "client", sender.ID, "command", command.Command, "ok", result.OK, "result", result.Message)
+	sender.sendAdminResult(result)
+}
+
+// runAdminCommandLocked runs the command under w.mu, released by defer so a
+// recovered panic in the read goroutine cannot leave the world locked
+func (w *World) runAdminCommandLocked(sender *Client, command *AdminCommand) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.runAdminCommand(sender, command)
+}
+
+// runAdminCommand applies an authorized admin command; caller must hold w.mu
+func (w *World) runAdminCommand(sender *Client, command *AdminCommand) error {
+	switch command.Command {
+	case AdminSpawnItem:
+		value, known := adminItemValues[command.ItemType]
+		if !known {
+			return fmt.Errorf("unknown item type %q", command.ItemType)
+		}
+		x, y := sender.Player.X, sender.Player.Y
+		if command.X != nil && command.Y != nil {
+			x, y = *command.X, *command.Y
+		}
+		x = clampfloat64(x, 0, WorldWidth)
+		y = clampfloat64(y, 0, WorldHeight)
+
+		count := min(max(command.Count, 1), maxAdminItemSpawn)
+		now := time.Now()
+		for i := 0; i < count; i++ {
+			w.mechanics.SpawnLoot(x+w.rng.Float64()*60-30, y+w.rng.Float64()*60-30, command.ItemType, value, value, command.PlayerID, now)
+		}
+		return nil
+
+	case AdminSetLevel:
+		player, exists := w.players[command.PlayerID]
+		if !exists {
+			return fmt.Errorf("no player %d", command.PlayerID)
+		}
+		level := min(max(command.Level, 1), w.config.MaxLevel)
+		player.AvailableUpgrades = max(player.AvailableUpgrades+level-player.Level, 0)
+		player.Level = level
+		player.Experience = GetExperienceRequiredForLevel(level)
+		return nil
+
+	case AdminKick:
+		if command.PlayerID == sender.ID {
+			return fmt.Errorf("cannot kick yourself")
+		}
+		target, exists := w.clients[command.PlayerID]
+		if !exists {
+			return fmt.Errorf("no connected player %d", command.PlayerID)
+		}
+		w.disconnectClient(target, ErrorKicked)
+		return nil
+
+	case AdminToggleBots:
+		if command.Enabled {
+			if w.fightingBots() > 0 {
+				return fmt.Errorf("bots are already on")
+			}
+			w.botsPaused = false
+			w.spawnBots()
+			return nil
+		}
+		w.botsPaused = true
+		for id, bot := range w.bots {
+			if bot.Dummy {
+				continue
+			}
+			delete(w.players, id)
+			delete(w.bots, id)
+		}
+		return nil
+
+	case AdminWatchBot:
+		if command.PlayerID == 0 {
+			sender.WatchBotID = 0
+			return nil
+		}
+		if _, exists := w.bots[command.PlayerID]; !exists {
+			return fmt.Errorf("no bot %d", command.PlayerID)
+		}
+		sender.WatchBotID = command.PlayerID
+		return nil
+
+	case AdminSetRewards:
+		for _, multiplier := range []*float64{command.XPMultiplier, command.CoinMultiplier} {
+			if multiplier != nil && (*multiplier < 0 || *multiplier > maxRewardMultiplier || !isFinite(*multiplier)) {
+				return fmt.Errorf("multiplier must be between 0 and %g", maxRewardMultiplier)
+			}
+		}
+		if command.XPMultiplier != nil {
+			w.config.XPMultiplier = *command.XPMultiplier
+		}
+		if command.CoinMultiplier != nil {
+			w.config.CoinMultiplier = *command.CoinMultiplier
+		}
+		for _, client := range w.clients {
+			client.sendMapInfo(w.config, w.currents)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown command %q", command.Command)
+	}
+}
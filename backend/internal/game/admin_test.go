@@ -0,0 +1,121 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// adminResults empties the client's send buffer and returns the admin replies in it
+func adminResults(client *Client) []AdminResultMsg {
+	var results []AdminResultMsg
+	for _, data := range queuedMessages(client) {
+		var result AdminResultMsg
+		if decodeTestMsg(data, &result) && result.Type == MsgTypeAdminResult {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+func TestAdminCommandsFromNonAdminsAreRejected(t *testing.T) {
+	w := newTestWorld(t, func(config *WorldConfig) { config.AdminToken = "secret" })
+	intruder := addTestClient(t, w, 1000, 1000)
+	target := addTestClient(t, w, 2000, 2000)
+	queuedMessages(intruder)
+
+	commands := []AdminCommand{
+		{Command: AdminSetLevel, PlayerID: target.ID, Level: 20},
+		{Command: AdminKick, PlayerID: target.ID},
+		{Command: AdminSpawnItem, ItemType: ItemTypeGoldStar, Count: 10},
+	}
+	for _, command := range commands {
+		w.HandleInput(intruder.ID, InputMsg{Type: "admin", Admin: &command})
+	}
+
+	results := adminResults(intruder)
+	if len(results) != len(commands) {
+		t.Fatalf("got %d admin replies, want %d", len(results), len(commands))
+	}
+	for _, result := range results {
+		if result.OK || result.Message != "Not authorized" {
+			t.Errorf("reply to %q = %+v, want a rejection", result.Command, result)
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if target.Player.Level != 1 {
+		t.Errorf("target level = %d, want 1", target.Player.Level)
+	}
+	if _, connected := w.clients[target.ID]; !connected {
+		t.Error("target was kicked by a non-admin")
+	}
+	if len(w.items) != 0 {
+		t.Errorf("%d items spawned by a non-admin", len(w.items))
+	}
+}
+
+func TestAdminCommandsRunForAdmins(t *testing.T) {
+	w := newTestWorld(t, func(config *WorldConfig) { config.AdminToken = "secret" })
+	admin := addTestClient(t, w, 1000, 1000)
+	admin.IsAdmin = true
+	target := addTestClient(t, w, 2000, 2000)
+	queuedMessages(admin)
+
+	w.HandleInput(admin.ID, InputMsg{Type: "admin", Admin: &AdminCommand{Command: AdminSetLevel, PlayerID: target.ID, Level: 5}})
+
+	results := adminResults(admin)
+	if len(results) != 1 || !results[0].OK {
+		t.Fatalf("admin replies = %+v, want one success", results)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if target.Player.Level != 5 {
+		t.Errorf("target level = %d, want 5", target.Player.Level)
+	}
+}
+
+func TestWatchingABotStreamsItsCurrentTarget(t *testing.T) {
+	w := newTestWorld(t, nil)
+	admin := addTestClient(t, w, 4000, 4000)
+	admin.IsAdmin = true
+	snoop := addTestClient(t, w, 4000, 500)
+	target := addTestClient(t, w, 2000, 2000).Player
+
+	w.mu.Lock()
+	now := time.Now()
+	w.spawnBot(now)
+	w.spawnBot(now)
+	watched, other := w.bots[target.ID+1], w.bots[target.ID+2]
+	watched.Player.X, watched.Player.Y = 1900, 2000
+	watched.GuardCenter = Position{X: 1900, Y: 2000}
+	snoop.WatchBotID = other.ID // Set behind the admin check; must still stream nothing
+	w.mu.Unlock()
+
+	w.HandleInput(admin.ID, InputMsg{Type: "admin", Admin: &AdminCommand{Command: AdminWatchBot, PlayerID: watched.ID}})
+	queuedMessages(admin)
+	queuedMessages(snoop)
+	w.update()
+
+	var debug []BotDebugMsg
+	for _, data := range queuedMessages(admin) {
+		var msg BotDebugMsg
+		if decodeTestMsg(data, &msg) && msg.Type == MsgTypeBotDebug {
+			debug = append(debug, msg)
+		}
+	}
+	if len(debug) != 1 {
+		t.Fatalf("admin got %d bot debug messages, want 1", len(debug))
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if debug[0].BotID != watched.ID || debug[0].TargetPlayerID != watched.TargetPlayerID || watched.TargetPlayerID != target.ID {
+		t.Errorf("bot debug = %+v, want bot %d targeting %d", debug[0], watched.ID, target.ID)
+	}
+	for _, data := range queuedMessages(snoop) {
+		var msg BotDebugMsg
+		if decodeTestMsg(data, &msg) && msg.Type == MsgTypeBotDebug {
+			t.Fatal("a non-admin received bot debug state")
+		}
+	}
+}
@@ -2,8 +2,10 @@ package game
 
 import (
 	"fmt"
+	"log/slog"
 	"math"
 	"math/rand"
+	"slices"
 	"time"
 )
 
@@ -17,6 +19,7 @@ const (
 	botSideCannonsCount  int     = 2
 	botTopTurretCount    int     = 1
 	botDecisionInterval          = 250 * time.Millisecond
+	botRespawnDelay              = 5 * time.Second
 	botCannonDamageLevel         = 5
 	botCannonRangeLevel          = 5
 	botReloadSpeedLevel          = 5
@@ -24,8 +27,90 @@ const (
 	botTurnSpeedLevel            = 0
 	botHealthLevel               = 5
 	botRegenLevel                = 5
+	botSpreadTargets             = true // Prefer targets not already claimed by a lower-ID bot
+
+	botEdgeMargin      float64 = 300.0 // Distance from the world edge where bots start steering away
+	botEdgeAvoidWeight float64 = 2.0   // Strength of the edge-avoidance pull at the very edge
 )
 
+// BotDifficulty names a preset for how hard bots play
+type BotDifficulty string
+
+const (
+	BotDifficultyPassive    BotDifficulty = "passive"
+	BotDifficultyNormal     BotDifficulty = "normal"
+	BotDifficultyAggressive BotDifficulty = "aggressive"
+)
+
+// BotConfig controls the bot population and how bots engage players
+type BotConfig struct {
+	Count             int                 // Number of bots spawned when the world starts
+	Difficulty        BotDifficulty       // Preset the remaining values were derived from
+	StatLevels        map[UpgradeType]int // Stat upgrade levels forced onto every bot
+	DecisionInterval  time.Duration       // Time between target re-evaluations
+	AggroRadius       float64             // Distance from the guard center a bot will chase
+	TargetDistance    float64             // Distance at which a bot notices a player
+	PreferredDistance float64             // Orbit distance a bot tries to hold from its target
+	RespawnDelay      time.Duration       // How long a sunk bot waits before respawning
+
+	// When > 0, bots only fill the world up to this many ships (humans plus
+	// bots, never more than Count): sunk bots are retired instead of
+	// respawning as humans join, and new ones are added back as humans leave
+	FillTo int
+
+	// Stationary target dummies for testing weapons; they never move, shoot
+	// or retire, and always respawn where they stood
+	Dummies int
+}
+
+// NewBotConfig returns the bot settings for a difficulty tier; unknown tiers
+// fall back to normal
+func NewBotConfig(difficulty BotDifficulty) BotConfig {
+	config := BotConfig{
+		Count:             botCount,
+		Difficulty:        BotDifficultyNormal,
+		DecisionInterval:  botDecisionInterval,
+		AggroRadius:       botAggroRadius,
+		TargetDistance:    botTargetDistance,
+		PreferredDistance: botPreferredDistance,
+		RespawnDelay:      botRespawnDelay,
+		StatLevels: map[UpgradeType]int{
+			StatUpgradeCannonDamage: botCannonDamageLevel,
+			StatUpgradeCannonRange:  botCannonRangeLevel,
+			StatUpgradeReloadSpeed:  botReloadSpeedLevel,
+			StatUpgradeMoveSpeed:    botMoveSpeedLevel,
+			StatUpgradeTurnSpeed:    botTurnSpeedLevel,
+			StatUpgradeHullStrength: botHealthLevel,
+			StatUpgradeAutoRepairs:  botRegenLevel,
+		},
+	}
+
+	switch difficulty {
+	case BotDifficultyPassive:
+		// Keep their distance, give up quickly and react slowly
+		config.Difficulty = BotDifficultyPassive
+		config.DecisionInterval = 2 * botDecisionInterval
+		config.AggroRadius = botAggroRadius * 0.6
+		config.TargetDistance = botTargetDistance * 0.7
+		config.PreferredDistance = botPreferredDistance * 1.75
+		for upgradeType, level := range config.StatLevels {
+			config.StatLevels[upgradeType] = level / 2
+		}
+	case BotDifficultyAggressive:
+		// Close in tight, chase far and re-target quickly
+		config.Difficulty = BotDifficultyAggressive
+		config.DecisionInterval = botDecisionInterval / 2
+		config.AggroRadius = botAggroRadius * 1.5
+		config.TargetDistance = botTargetDistance * 1.4
+		config.PreferredDistance = botPreferredDistance * 0.6
+		for upgradeType, level := range config.StatLevels {
+			config.StatLevels[upgradeType] = level + 3
+		}
+	}
+
+	return config
+}
+
 const (
 	botAreaMinX float64 = 0
 	botAreaMaxX float64 = WorldWidth
@@ -35,120 +120,242 @@ const (
 
 var botColors = []string{"#5B73FF", "#FF6F61", "#48C9B0"}
 
-const (
-	minSpawnDistanceFromPlayers = 300.0 // Minimum distance bots should spawn from players
-	maxSpawnAttempts            = 50    // Maximum attempts to find a safe spawn position
-)
+// botLoadout is a bot archetype: the modules it carries and how its stats and
+// engagement distance differ from the difficulty baseline
+type botLoadout struct {
+	Name                   string
+	Side                   func() *ShipModule
+	Top                    func() *ShipModule
+	Front                  func() *ShipModule
+	StatBias               map[UpgradeType]int // Added to the configured stat levels
+	PreferredDistanceScale float64             // Multiplies the configured orbit distance
+	Charge                 bool                // Drive straight at the target instead of orbiting
+}
+
+var botLoadouts = []botLoadout{
+	{
+		Name: "Guardian",
+		Side: func() *ShipModule { return NewBasicSideCannons(botSideCannonsCount) },
+		Top:  func() *ShipModule { return NewBasicTurrets(botTopTurretCount) },
+
+		PreferredDistanceScale: 1.0,
+	},
+	{
+		Name: "Brawler",
+		Side: func() *ShipModule { return NewScatterSideCannons(botSideCannonsCount) },
+		Top:  func() *ShipModule { return NewBasicTurrets(botTopTurretCount) },
+		StatBias: map[UpgradeType]int{
+			StatUpgradeHullStrength: 2,
+			StatUpgradeCannonRange:  -3,
+		},
+		PreferredDistanceScale: 0.6,
+	},
+	{
+		Name: "Skirmisher",
+		Side: func() *ShipModule { return NewBasicSideCannons(botSideCannonsCount) },
+		Top:  func() *ShipModule { return NewMachineGunTurret(botTopTurretCount) },
+		StatBias: map[UpgradeType]int{
+			StatUpgradeMoveSpeed:    2,
+			StatUpgradeTurnSpeed:    2,
+			StatUpgradeHullStrength: -2,
+		},
+		PreferredDistanceScale: 1.2,
+	},
+	{
+		Name: "Sniper",
+		Top:  func() *ShipModule { return NewBigTurrets(botTopTurretCount) },
+		StatBias: map[UpgradeType]int{
+			StatUpgradeCannonRange:  3,
+			StatUpgradeCannonDamage: 2,
+			StatUpgradeReloadSpeed:  -2,
+		},
+		PreferredDistanceScale: 2.5,
+	},
+	{
+		Name:  "Rammer",
+		Side:  func() *ShipModule { return NewBasicSideCannons(botSideCannonsCount) },
+		Front: NewRamUpgrade,
+		StatBias: map[UpgradeType]int{
+			StatUpgradeBodyDamage:   5,
+			StatUpgradeHullStrength: 3,
+			StatUpgradeMoveSpeed:    3,
+		},
+		PreferredDistanceScale: 0,
+		Charge:                 true,
+	},
+}
+
+// randomBotLoadout picks a loadout for a (re)spawning bot
+func randomBotLoadout(rng *rand.Rand) *botLoadout {
+	return &botLoadouts[rng.Intn(len(botLoadouts))]
+}
+
+// spawnEdgeMargin keeps spawn candidates away from the world edge
+const spawnEdgeMargin = 100.0
 
-// findSafeSpawnPosition finds a spawn position that's away from other players
-func (w *World) findSafeSpawnPosition() (Position, bool) {
-	for attempt := 0; attempt < maxSpawnAttempts; attempt++ {
-		spawnPos := Position{
-			X: float64(rand.Intn(int(WorldWidth-200)) + 100),
-			Y: float64(rand.Intn(int(WorldHeight-200)) + 100),
+// chooseSafeSpawn samples SpawnCandidates random points and returns the one
+// farthest from the nearest live enemy of player. Points outside the zone are
+// never chosen; caller must hold w.mu
+func (w *World) chooseSafeSpawn(player *Player) Position {
+	best := Position{X: WorldWidth / 2, Y: WorldHeight / 2}
+	if w.zone != nil {
+		best = Position{X: w.zone.X, Y: w.zone.Y}
+	}
+	bestDistance := -1.0
+	for range max(w.config.SpawnCandidates, 1) {
+		candidate := w.spawnCandidate()
+		if w.outsideZone(candidate.X, candidate.Y) {
+			continue
 		}
 
-		// Check distance from all existing players
-		tooClose := false
-		for _, player := range w.players {
-			if player == nil || player.IsBot {
-				continue
-			}
-			dx := spawnPos.X - player.X
-			dy := spawnPos.Y - player.Y
-			distance := math.Sqrt(dx*dx + dy*dy)
-			if distance < minSpawnDistanceFromPlayers {
-				tooClose = true
-				break
-			}
+		distance := w.nearestEnemyDistance(player, candidate)
+		if distance > bestDistance {
+			best = candidate
+			bestDistance = distance
 		}
+	}
+	return best
+}
 
-		if !tooClose {
-			return spawnPos, true
+// spawnCandidate returns a random point away from the world edge, drawn from
+// inside the zone while one is active; caller must hold w.mu
+func (w *World) spawnCandidate() Position {
+	if w.zone == nil {
+		return Position{
+			X: spawnEdgeMargin + w.rng.Float64()*(WorldWidth-2*spawnEdgeMargin),
+			Y: spawnEdgeMargin + w.rng.Float64()*(WorldHeight-2*spawnEdgeMargin),
 		}
 	}
 
-	// If we couldn't find a safe position after max attempts, return a random one anyway
+	angle := w.rng.Float64() * 2 * math.Pi
+	distance := math.Sqrt(w.rng.Float64()) * w.zone.Radius // Uniform over the disc
 	return Position{
-		X: float64(rand.Intn(int(WorldWidth-200)) + 100),
-		Y: float64(rand.Intn(int(WorldHeight-200)) + 100),
-	}, false
+		X: clampfloat64(w.zone.X+math.Cos(angle)*distance, spawnEdgeMargin, WorldWidth-spawnEdgeMargin),
+		Y: clampfloat64(w.zone.Y+math.Sin(angle)*distance, spawnEdgeMargin, WorldHeight-spawnEdgeMargin),
+	}
+}
+
+// nearestEnemyDistance returns the distance from position to the closest live
+// ship that isn't player or a teammate (+Inf when there is none)
+func (w *World) nearestEnemyDistance(player *Player, position Position) float64 {
+	nearest := math.Inf(1)
+	for _, other := range w.players {
+		if other == player || other.State != StateAlive || isTeammate(player, other) {
+			continue
+		}
+		nearest = math.Min(nearest, math.Hypot(other.X-position.X, other.Y-position.Y))
+	}
+	return nearest
 }
 
 func (w *World) spawnInitialBots() {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
+	w.spawnBots()
+	w.spawnDummies(time.Now())
+}
+
+// spawnBots adds the configured number of bots to the world; caller must hold w.mu
+func (w *World) spawnBots() {
 	now := time.Now()
 
-	for i := 0; i < botCount; i++ {
-		id := w.nextPlayerID
-		w.nextPlayerID++
-
-		player := NewPlayer(id)
-		player.IsBot = true
-		player.Name = fmt.Sprintf("Guardian %d", i+1)
-		player.Color = botColors[i%len(botColors)]
-		player.Score = 2000
-		player.Coins = 2000
-		player.Experience = 2000
-		player.Level = 25
-		player.AvailableUpgrades = 0
-
-		// Find a safe spawn position away from players
-		spawnPos, _ := w.findSafeSpawnPosition()
-
-		player.X = spawnPos.X
-		player.Y = spawnPos.Y
-		player.Angle = 0
-		player.AutofireEnabled = true
-		player.LastCollisionDamage = now
+	for range w.config.Bots.Count {
+		w.spawnBot(now)
+	}
+}
 
-		w.applyBotLoadout(player)
+// spawnBot adds one bot to the world; caller must hold w.mu
+func (w *World) spawnBot(now time.Time) {
+	i := w.botsSpawned
+	w.botsSpawned++
 
-		orbitDir := 1
-		if i%2 == 1 {
-			orbitDir = -1
-		}
+	id := w.nextPlayerID
+	w.nextPlayerID++
 
-		bot := &Bot{
-			ID:                id,
-			Player:            player,
-			GuardCenter:       spawnPos,
-			GuardRadius:       botGuardRadius,
-			TargetDistance:    botTargetDistance,
-			AggroRadius:       botAggroRadius,
-			PreferredDistance: botPreferredDistance,
-			OrbitDirection:    orbitDir,
-			DesiredAngle:      0,
-		}
+	// Cycle through loadouts so every archetype shows up
+	loadout := &botLoadouts[i%len(botLoadouts)]
+
+	player := NewPlayer(id)
+	player.IsBot = true
+	player.Name = fmt.Sprintf("Guardian %d", i+1)
+	player.Color = botColors[i%len(botColors)]
+	player.Score = 2000
+	player.Coins = 2000
+	player.Experience = 2000
+	player.Level = 25
+	player.AvailableUpgrades = 0
+
+	// Spawn as far from other ships as we can find
+	spawnPos := w.chooseSafeSpawn(player)
+
+	player.X = spawnPos.X
+	player.Y = spawnPos.Y
+	player.Angle = 0
+	player.AutofireEnabled = true
+	player.LastCollisionDamage = now
+
+	orbitDir := 1
+	if i%2 == 1 {
+		orbitDir = -1
+	}
+
+	bot := &Bot{
+		ID:             id,
+		Player:         player,
+		GuardCenter:    spawnPos,
+		GuardRadius:    botGuardRadius,
+		TargetDistance: w.config.Bots.TargetDistance,
+		AggroRadius:    w.config.Bots.AggroRadius,
+		OrbitDirection: orbitDir,
+		DesiredAngle:   0,
+	}
+	w.applyBotLoadout(bot, loadout)
 
-		w.players[id] = player
-		w.bots[id] = bot
+	w.players[id] = player
+	w.assignTeam(player)
+	w.bots[id] = bot
+}
+
+// desiredBotCount is how many bots the world should have for the humans
+// currently connected (see BotConfig.FillTo)
+func (w *World) desiredBotCount() int {
+	if w.config.Bots.FillTo <= 0 {
+		return w.config.Bots.Count
 	}
+	return max(0, min(w.config.Bots.Count, w.config.Bots.FillTo-len(w.clients)))
+}
+
+// retireBot removes a bot from the world for good; caller must hold w.mu
+func (w *World) retireBot(id uint32) {
+	slog.Info("Bot retired", "player", id, "bots", len(w.bots)-1)
+	delete(w.players, id)
+	delete(w.bots, id)
 }
 
-func (w *World) applyBotLoadout(player *Player) {
+// applyBotLoadout equips the bot's ship with the loadout's modules and stats
+func (w *World) applyBotLoadout(bot *Bot, loadout *botLoadout) {
+	player := bot.Player
 	baseLength := float64(PlayerSize*1.2) * 0.5
 	baseWidth := float64(PlayerSize * 0.8)
 
+	statLevels := make(map[UpgradeType]int, len(w.config.Bots.StatLevels))
+	for upgradeType, level := range w.config.Bots.StatLevels {
+		statLevels[upgradeType] = level
+	}
+	for upgradeType, bias := range loadout.StatBias {
+		statLevels[upgradeType] = max(0, statLevels[upgradeType]+bias)
+	}
+
 	player.InitializeStatUpgrades()
-	ForceStatUpgrades(player, map[UpgradeType]int{
-		StatUpgradeCannonDamage: botCannonDamageLevel,
-		StatUpgradeCannonRange:  botCannonRangeLevel,
-		StatUpgradeReloadSpeed:  botReloadSpeedLevel,
-		StatUpgradeMoveSpeed:    botMoveSpeedLevel,
-		StatUpgradeTurnSpeed:    botTurnSpeedLevel,
-		StatUpgradeHullStrength: botHealthLevel,
-		StatUpgradeAutoRepairs:  botRegenLevel,
-	})
+	ForceStatUpgrades(player, statLevels)
 	player.Modifiers.MoveSpeedMultiplier = 0.8 // Slightly slower base speed for bots
 	player.Health = player.MaxHealth
 
 	config := ShipConfiguration{
-		SideUpgrade:  NewBasicSideCannons(botSideCannonsCount),
-		TopUpgrade:   NewBasicTurrets(botTopTurretCount),
-		FrontUpgrade: nil,
+		SideUpgrade:  newBotModule(loadout.Side),
+		TopUpgrade:   newBotModule(loadout.Top),
+		FrontUpgrade: newBotModule(loadout.Front),
 		RearUpgrade:  nil,
 		ShipLength:   baseLength,
 		ShipWidth:    baseWidth,
@@ -159,6 +366,17 @@ func (w *World) applyBotLoadout(player *Player) {
 	config.UpdateUpgradePositions()
 
 	player.ShipConfig = config
+
+	bot.PreferredDistance = w.config.Bots.PreferredDistance * loadout.PreferredDistanceScale
+	bot.Charge = loadout.Charge
+}
+
+// newBotModule builds a loadout slot, leaving it empty when the loadout has none
+func newBotModule(build func() *ShipModule) *ShipModule {
+	if build == nil {
+		return nil
+	}
+	return build()
 }
 
 func ForceStatUpgrades(player *Player, upgrades map[UpgradeType]int) {
@@ -172,23 +390,41 @@ func ForceStatUpgrades(player *Player, upgrades map[UpgradeType]int) {
 }
 
 func (w *World) updateBots() {
+	// Respawn, retire and top up bots before moving them
+	w.handleBotRespawns()
 	if len(w.bots) == 0 {
 		return
 	}
 
-	now := time.Now()
-	for _, bot := range w.bots {
-		w.updateBot(bot, now)
+	// Process bots in ID order so target contention resolves the same way every tick
+	botIDs := make([]uint32, 0, len(w.bots))
+	for id := range w.bots {
+		botIDs = append(botIDs, id)
 	}
+	slices.Sort(botIDs)
 
-	w.handleBotRespawns()
+	now := time.Now()
+	claimedTargets := make(map[uint32]uint32, len(w.bots)) // target ID -> claiming bot ID
+	for _, id := range botIDs {
+		bot := w.bots[id]
+		w.updateBot(bot, now, claimedTargets)
+		if bot.TargetPlayerID != 0 {
+			if _, claimed := claimedTargets[bot.TargetPlayerID]; !claimed {
+				claimedTargets[bot.TargetPlayerID] = bot.ID
+			}
+		}
+	}
 }
 
-func (w *World) updateBot(bot *Bot, now time.Time) {
+func (w *World) updateBot(bot *Bot, now time.Time, claimedTargets map[uint32]uint32) {
 	player := bot.Player
 	if player == nil || player.State != StateAlive {
 		return
 	}
+	if bot.Dummy {
+		w.updateDummy(bot, now)
+		return
+	}
 
 	bot.Input = InputMsg{}
 	bot.Input.Up = true
@@ -208,11 +444,11 @@ func (w *World) updateBot(bot *Bot, now time.Time) {
 
 	if (bot.TargetPlayerID == 0 && (bot.NextDecision.IsZero() || now.After(bot.NextDecision))) || (bot.TargetPlayerID != 0 && now.After(bot.NextDecision)) {
 		previous := bot.TargetPlayerID
-		bot.TargetPlayerID = w.findBotTarget(bot)
+		bot.TargetPlayerID = w.findBotTarget(bot, claimedTargets)
 		if bot.TargetPlayerID != 0 && bot.TargetPlayerID != previous {
 			bot.DesiredAngle = player.Angle
 		}
-		bot.NextDecision = now.Add(botDecisionInterval)
+		bot.NextDecision = now.Add(w.config.Bots.DecisionInterval)
 	}
 
 	var desiredAngle float64
@@ -226,7 +462,7 @@ func (w *World) updateBot(bot *Bot, now time.Time) {
 		angleToTarget := float64(math.Atan2(float64(target.Y-player.Y), float64(target.X-player.X)))
 		distance := float64(math.Hypot(float64(target.X-player.X), float64(target.Y-player.Y)))
 
-		if distance > bot.PreferredDistance+botDistanceSlack {
+		if bot.Charge || distance > bot.PreferredDistance+botDistanceSlack {
 			desiredAngle = angleToTarget
 		} else if distance < bot.PreferredDistance-botDistanceSlack {
 			desiredAngle = angleToTarget + float64(bot.OrbitDirection)*float64(math.Pi*0.75)
@@ -237,7 +473,7 @@ func (w *World) updateBot(bot *Bot, now time.Time) {
 
 		if !bot.inAllowedZone(target.X, target.Y) {
 			bot.TargetPlayerID = 0
-			bot.NextDecision = now.Add(botDecisionInterval)
+			bot.NextDecision = now.Add(w.config.Bots.DecisionInterval)
 		}
 	} else {
 		dx := bot.GuardCenter.X - player.X
@@ -258,6 +494,24 @@ func (w *World) updateBot(bot *Bot, now time.Time) {
 		hasDesiredAngle = true
 	}
 
+	// Near the world edge, bend the heading back toward open water
+	if hasDesiredAngle {
+		desiredAngle = avoidWorldEdge(player.X, player.Y, desiredAngle)
+	}
+
+	// Outside the battle-royale zone, getting back in beats everything else
+	if w.outsideZone(player.X, player.Y) {
+		desiredAngle = math.Atan2(w.zone.Y-player.Y, w.zone.X-player.X)
+		hasDesiredAngle = true
+	}
+
+	// Aim upstream so the current carries the bot along the heading it wants
+	if hasDesiredAngle {
+		currentX, currentY := w.currentAt(player.X, player.Y)
+		maxSpeed := BaseShipMaxSpeed * player.Modifiers.MoveSpeedMultiplier
+		desiredAngle = compensateForCurrent(desiredAngle, maxSpeed, currentX, currentY)
+	}
+
 	if !hasDesiredAngle {
 		desiredAngle = player.Angle
 	}
@@ -288,9 +542,38 @@ func (w *World) updateBot(bot *Bot, now time.Time) {
 	w.updatePlayer(player, &bot.Input)
 }
 
-func (w *World) findBotTarget(bot *Bot) uint32 {
-	var bestID uint32
+// avoidWorldEdge blends an inward push into a heading when the position is within
+// botEdgeMargin of the world edge, growing stronger the closer the edge is
+func avoidWorldEdge(x, y, angle float64) float64 {
+	edgePush := func(position, size float64) float64 {
+		if position < botEdgeMargin {
+			return (botEdgeMargin - position) / botEdgeMargin
+		}
+		if position > size-botEdgeMargin {
+			return -(position - (size - botEdgeMargin)) / botEdgeMargin
+		}
+		return 0
+	}
+
+	pushX := edgePush(x, WorldWidth)
+	pushY := edgePush(y, WorldHeight)
+	if pushX == 0 && pushY == 0 {
+		return angle
+	}
+
+	headingX := math.Cos(angle) + pushX*botEdgeAvoidWeight
+	headingY := math.Sin(angle) + pushY*botEdgeAvoidWeight
+	return math.Atan2(headingY, headingX)
+}
+
+// findBotTarget picks the nearest eligible player, breaking distance ties by ID.
+// With botSpreadTargets, players already claimed by another bot this tick are
+// only chosen when no unclaimed player is in range. The bounty target always
+// wins when it is in range.
+func (w *World) findBotTarget(bot *Bot, claimedTargets map[uint32]uint32) uint32 {
+	var bestID, bestUnclaimedID, bountyID uint32
 	bestDistance := float64(math.MaxFloat64)
+	bestUnclaimedDistance := float64(math.MaxFloat64)
 
 	for id, candidate := range w.players {
 		if candidate == nil || candidate.IsBot || candidate.State != StateAlive {
@@ -301,15 +584,63 @@ func (w *World) findBotTarget(bot *Bot) uint32 {
 		}
 
 		distance := float64(math.Hypot(float64(candidate.X-bot.Player.X), float64(candidate.Y-bot.Player.Y)))
-		if distance < bestDistance && distance <= bot.TargetDistance {
+		if distance > bot.TargetDistance {
+			continue
+		}
+		if candidate.IsBounty {
+			bountyID = id
+		}
+
+		if distance < bestDistance || (distance == bestDistance && id < bestID) {
 			bestDistance = distance
 			bestID = id
 		}
+
+		if claimer, claimed := claimedTargets[id]; claimed && claimer != bot.ID {
+			continue
+		}
+		if distance < bestUnclaimedDistance || (distance == bestUnclaimedDistance && id < bestUnclaimedID) {
+			bestUnclaimedDistance = distance
+			bestUnclaimedID = id
+		}
 	}
 
+	// The bounty target in range beats every other candidate, claimed or not
+	if bountyID != 0 {
+		return bountyID
+	}
+	if botSpreadTargets && bestUnclaimedID != 0 {
+		return bestUnclaimedID
+	}
 	return bestID
 }
 
+// debugState snapshots the bot's decision-making fields for an admin watcher
+func (bot *Bot) debugState() BotDebugMsg {
+	return BotDebugMsg{
+		BotID:          bot.ID,
+		TargetPlayerID: bot.TargetPlayerID,
+		DesiredAngle:   bot.DesiredAngle,
+		TurnIntent:     bot.TurnIntent,
+		GuardCenter:    bot.GuardCenter,
+		GuardRadius:    bot.GuardRadius,
+		Charge:         bot.Charge,
+	}
+}
+
+// sendBotDebug streams each watched bot's state to the admins watching it
+// (w.mu must be held)
+func (w *World) sendBotDebug() {
+	for _, client := range w.clients {
+		if client.WatchBotID == 0 || !client.IsAdmin {
+			continue
+		}
+		if bot, exists := w.bots[client.WatchBotID]; exists {
+			client.sendBotDebug(bot.debugState())
+		}
+	}
+}
+
 func (bot *Bot) inAllowedZone(x, y float64) bool {
 	if x < botAreaMinX || x > botAreaMaxX || y < botAreaMinY || y > botAreaMaxY {
 		return false
@@ -326,10 +657,10 @@ func (w *World) respawnBot(bot *Bot, now time.Time) {
 		return
 	}
 
-	w.applyBotLoadout(player)
+	w.applyBotLoadout(bot, randomBotLoadout(w.rng))
 
-	// Find a safe respawn position away from players
-	spawnPos, _ := w.findSafeSpawnPosition()
+	// Respawn as far from other ships as we can find
+	spawnPos := w.chooseSafeSpawn(player)
 
 	player.State = StateAlive
 	player.X = spawnPos.X
@@ -340,11 +671,16 @@ func (w *World) respawnBot(bot *Bot, now time.Time) {
 	player.AutofireEnabled = true
 	player.RespawnTime = time.Time{}
 	player.LastCollisionDamage = now
+	player.MovementTracked = false
+	player.clearBurning()
+	player.clearBuffs()
+	player.Combat = CombatStats{}
+	player.RecentDamagers = nil
 
 	// Update guard center to new spawn location
 	bot.GuardCenter = spawnPos
 	bot.TargetPlayerID = 0
-	bot.NextDecision = now.Add(botDecisionInterval)
+	bot.NextDecision = now.Add(w.config.Bots.DecisionInterval)
 }
 
 func normalizeAngle(angle float64) float64 {
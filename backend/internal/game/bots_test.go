@@ -0,0 +1,272 @@
+package game
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSafeSpawnStaysInsideZone(t *testing.T) {
+	w := newTestWorld(t, func(config *WorldConfig) {
+		config.SpawnCandidates = 8
+	})
+	player := NewPlayer(1)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.zone = &Zone{X: 1200, Y: 3000, Radius: 150}
+	for range 200 {
+		spawn := w.chooseSafeSpawn(player)
+		if w.outsideZone(spawn.X, spawn.Y) {
+			t.Fatalf("spawn (%v, %v) is outside the zone", spawn.X, spawn.Y)
+		}
+	}
+}
+
+func TestBotsShareContestedTargetsTheSameWayEveryTick(t *testing.T) {
+	// assignments places two bots beside two players, the first player nearer
+	// both, and returns each bot's target over several ticks
+	assignments := func() [][2]uint32 {
+		w := newTestWorld(t, nil)
+		near := addTestClient(t, w, 2000, 2000).Player
+		far := addTestClient(t, w, 2300, 2000).Player
+
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		now := time.Now()
+		w.spawnBot(now)
+		w.spawnBot(now)
+		var bots []*Bot
+		for _, id := range []uint32{far.ID + 1, far.ID + 2} {
+			bot := w.bots[id]
+			bot.Player.X, bot.Player.Y = 1900, 2000+float64(len(bots))*10
+			bot.GuardCenter = Position{X: bot.Player.X, Y: bot.Player.Y}
+			bots = append(bots, bot)
+		}
+
+		var ticks [][2]uint32
+		for range 5 {
+			w.updateBots()
+			ticks = append(ticks, [2]uint32{bots[0].TargetPlayerID, bots[1].TargetPlayerID})
+			bots[0].NextDecision, bots[1].NextDecision = time.Time{}, time.Time{}
+		}
+		if ticks[0] != [2]uint32{near.ID, far.ID} {
+			t.Errorf("bots targeted %v, want the lower ID bot on %d and the other on %d", ticks[0], near.ID, far.ID)
+		}
+		return ticks
+	}
+
+	first := assignments()
+	for tick, targets := range first {
+		if targets != first[0] {
+			t.Errorf("tick %d targets %v, want the stable %v", tick, targets, first[0])
+		}
+	}
+	if again := assignments(); !slices.Equal(again, first) {
+		t.Errorf("a second run assigned %v, want %v", again, first)
+	}
+}
+
+func TestAggressiveBotsOrbitTighterThanPassiveOnes(t *testing.T) {
+	// orbit sails a bot around a stationary player for a while and returns
+	// its average distance from the player once it has settled into an orbit
+	orbit := func(difficulty BotDifficulty) float64 {
+		w := newTestWorld(t, func(config *WorldConfig) {
+			config.Bots = NewBotConfig(difficulty)
+			config.Bots.Count = 0
+		})
+		client := addTestClient(t, w, 2500, 2500)
+		target := client.Player
+		stop := make(chan struct{})
+		defer close(stop)
+		go drainClient(client, stop)
+
+		w.mu.Lock()
+		w.spawnBot(time.Now())
+		bot := w.bots[target.ID+1]
+		bot.Player.X, bot.Player.Y = target.X+300, target.Y
+		bot.GuardCenter = Position{X: bot.Player.X, Y: bot.Player.Y}
+		w.mu.Unlock()
+
+		const ticks = 20 * DefaultTickRate
+		total := 0.0
+		for tick := range ticks {
+			w.update()
+			w.mu.Lock()
+			target.Health = target.MaxHealth
+			if tick >= ticks/2 {
+				total += math.Hypot(bot.Player.X-target.X, bot.Player.Y-target.Y)
+			}
+			w.mu.Unlock()
+		}
+		return total / (ticks / 2)
+	}
+
+	passive := orbit(BotDifficultyPassive)
+	normal := orbit(BotDifficultyNormal)
+	aggressive := orbit(BotDifficultyAggressive)
+	if !(aggressive < normal && normal < passive) {
+		t.Errorf("bots kept %.0f (aggressive), %.0f (normal) and %.0f (passive) away; want each tier closer than the one below",
+			aggressive, normal, passive)
+	}
+}
+
+func TestEveryBotLoadoutSpawnsAFiringShip(t *testing.T) {
+	w := newTestWorld(t, nil)
+	client := addTestClient(t, w, 2500, 2500)
+	target := client.Player
+	stop := make(chan struct{})
+	defer close(stop)
+	go drainClient(client, stop)
+
+	w.mu.Lock()
+	var bots []*Bot
+	for i := range botLoadouts {
+		w.spawnBot(time.Now())
+		bot := w.bots[target.ID+uint32(i)+1]
+		angle := 2 * math.Pi * float64(i) / float64(len(botLoadouts))
+		bot.Player.X, bot.Player.Y = target.X+300*math.Cos(angle), target.Y+300*math.Sin(angle)
+		bot.GuardCenter = Position{X: bot.Player.X, Y: bot.Player.Y}
+		bots = append(bots, bot)
+	}
+	w.mu.Unlock()
+
+	for range 3 * DefaultTickRate {
+		w.update()
+		w.mu.Lock()
+		target.Health = target.MaxHealth
+		w.mu.Unlock()
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for i, bot := range bots {
+		loadout := botLoadouts[i]
+		ship := bot.Player.ShipConfig
+		if ship.ShipLength <= 0 || ship.ShipWidth <= 0 {
+			t.Errorf("%s has a %vx%v hull", loadout.Name, ship.ShipLength, ship.ShipWidth)
+		}
+		if bot.Player.Combat.ShotsFired == 0 {
+			t.Errorf("%s never fired at a target in range", loadout.Name)
+		}
+		if bot.Charge != loadout.Charge {
+			t.Errorf("%s charge = %v, want %v", loadout.Name, bot.Charge, loadout.Charge)
+		}
+		if loadout.Charge && (ship.FrontUpgrade == nil || ship.FrontUpgrade.Name != NewRamUpgrade().Name) {
+			t.Errorf("%s charges without a ram", loadout.Name)
+		}
+	}
+}
+
+func TestBotFacingTheWorldEdgeTurnsBackInward(t *testing.T) {
+	// heading places a bot at x facing west toward a guard post far west of it
+	// and returns the cosine of its heading after a second of ticks
+	heading := func(x float64) float64 {
+		w := newTestWorld(t, nil)
+		w.mu.Lock()
+		w.spawnBot(time.Now())
+		var bot *Bot
+		for _, b := range w.bots {
+			bot = b
+		}
+		bot.Player.X, bot.Player.Y, bot.Player.Angle = x, 2000, math.Pi
+		bot.GuardCenter, bot.GuardRadius = Position{X: x - 1000, Y: 2000}, 50
+		w.mu.Unlock()
+
+		for range 30 {
+			w.update()
+		}
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return math.Cos(bot.Player.Angle)
+	}
+
+	if open := heading(2000); open > -0.9 {
+		t.Fatalf("bot in open water turned away from its guard post (heading cos %v)", open)
+	}
+	if edge := heading(60); edge <= 0 {
+		t.Errorf("bot at the west edge still heads outward (heading cos %v), want it turned inward", edge)
+	}
+}
+
+func TestSunkBotWaitsOutItsRespawnDelay(t *testing.T) {
+	const delay = time.Minute
+	w := newTestWorld(t, func(config *WorldConfig) {
+		config.Bots.Count = 1
+		config.Bots.RespawnDelay = delay
+	})
+	w.update()
+
+	w.mu.Lock()
+	if len(w.bots) != 1 {
+		w.mu.Unlock()
+		t.Fatalf("world has %d bots, want 1", len(w.bots))
+	}
+	var bot *Bot
+	for _, b := range w.bots {
+		bot = b
+	}
+	now := time.Now()
+	w.mechanics.ApplyDamage(bot.Player, bot.Player.Health+1000, nil, KillCauseBullet, now)
+	if wait := bot.Player.RespawnTime.Sub(now); wait != delay {
+		t.Errorf("sunk bot respawns in %v, want %v", wait, delay)
+	}
+	w.mu.Unlock()
+
+	w.update()
+	w.mu.Lock()
+	if bot.Player.State != StateDead {
+		t.Error("bot respawned before its delay ran out")
+	}
+	bot.Player.RespawnTime = time.Now().Add(-time.Second)
+	w.mu.Unlock()
+
+	w.update()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if bot.Player.State != StateAlive {
+		t.Errorf("bot is %v after its delay ran out, want alive", bot.Player.State)
+	}
+}
+
+func TestBotsStepAsideForHumansAndComeBackWhenTheyLeave(t *testing.T) {
+	w := newTestWorld(t, func(config *WorldConfig) {
+		config.Bots.Count = 3
+		config.Bots.FillTo = 3
+		config.Bots.RespawnDelay = 0
+	})
+	bots := func() int {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return len(w.bots)
+	}
+	w.update()
+	if got := bots(); got != 3 {
+		t.Fatalf("empty world has %d bots, want 3", got)
+	}
+
+	var humans []*Client
+	for i := range 2 {
+		humans = append(humans, addTestClient(t, w, 1000+500*float64(i), 1000))
+	}
+
+	// Bots only retire once sunk, so sink them all
+	w.mu.Lock()
+	for _, bot := range w.bots {
+		w.mechanics.ApplyDamage(bot.Player, bot.Player.Health+1000, nil, KillCauseBullet, time.Now())
+	}
+	w.mu.Unlock()
+	w.update()
+	if got := bots(); got != 1 {
+		t.Errorf("world with 2 humans kept %d bots, want 1", got)
+	}
+
+	for _, human := range humans {
+		w.RemoveClient(human.ID)
+	}
+	w.update()
+	if got := bots(); got != 3 {
+		t.Errorf("world emptied of humans has %d bots, want 3", got)
+	}
+}
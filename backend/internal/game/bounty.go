@@ -0,0 +1,65 @@
+package game
+
+import (
+	"log/slog"
+	"time"
+)
+
+// updateBounty marks the highest-scoring living human ship as the bounty target,
+// rechecked every BountyUpdateInterval, and tells every client when the
+// bounty moves to a new ship (w.mu must be held)
+func (w *World) updateBounty(now time.Time) {
+	if now.Before(w.nextBountyUpdate) {
+		return
+	}
+	w.nextBountyUpdate = now.Add(BountyUpdateInterval)
+
+	var top *Player
+	for _, player := range w.players {
+		// Bots start with a high score, so they would always hold the bounty
+		if player.IsBot || player.State != StateAlive || player.Score < BountyMinScore {
+			continue
+		}
+		if top == nil || player.Score > top.Score || (player.Score == top.Score && player.ID < top.ID) {
+			top = player
+		}
+	}
+
+	var topID uint32
+	if top != nil {
+		topID = top.ID
+	}
+	if topID == w.bountyID {
+		return
+	}
+
+	if previous, exists := w.players[w.bountyID]; exists {
+		previous.IsBounty = false
+	}
+	w.bountyID = topID
+	if top == nil {
+		return
+	}
+
+	top.IsBounty = true
+	slog.Info("New bounty target", "player", top.ID, "name", top.Name, "score", top.Score)
+	for _, client := range w.clients {
+		client.sendGameEvent(GameEventMsg{
+			EventType:  "bounty",
+			VictimID:   top.ID,
+			VictimName: top.Name,
+			Time:       now.UnixMilli(),
+		})
+	}
+}
+
+// clearBounty drops the bounty from a sunk ship and picks the next target on
+// the following tick (w.mu must be held)
+func (w *World) clearBounty(victim *Player) {
+	if !victim.IsBounty {
+		return
+	}
+	victim.IsBounty = false
+	w.bountyID = 0
+	w.nextBountyUpdate = time.Time{}
+}
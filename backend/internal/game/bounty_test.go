@@ -0,0 +1,31 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBountySkipsBots(t *testing.T) {
+	w := newTestWorld(t, func(config *WorldConfig) {
+		config.Bots.Count = 1
+	})
+	human := addTestClient(t, w, 500, 500).Player
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.spawnBots()
+	var bot *Player
+	for _, b := range w.bots {
+		bot = b.Player
+	}
+	bot.Score = BountyMinScore * 10
+	human.Score = BountyMinScore
+
+	w.updateBounty(time.Now())
+	if bot.IsBounty {
+		t.Error("bot was made the bounty target")
+	}
+	if !human.IsBounty {
+		t.Error("top human ship is not the bounty target")
+	}
+}
@@ -0,0 +1,83 @@
+package game
+
+import (
+	"slices"
+	"time"
+)
+
+// BuffType identifies a temporary power-up effect
+type BuffType string
+
+const (
+	BuffShield      BuffType = "shield"      // Reduces incoming damage
+	BuffSpeedBoost  BuffType = "speedBoost"  // Increases movement speed
+	BuffRapidReload BuffType = "rapidReload" // Shortens reload times
+)
+
+// ActiveBuff is a power-up effect currently applied to a player
+type ActiveBuff struct {
+	Type      BuffType  `msgpack:"type"`
+	ExpiresAt time.Time `msgpack:"expiresAt"`
+}
+
+// itemBuffs maps power-up item types to the buff they grant
+var itemBuffs = map[string]BuffType{
+	ItemTypeShield:      BuffShield,
+	ItemTypeSpeedBoost:  BuffSpeedBoost,
+	ItemTypeRapidReload: BuffRapidReload,
+}
+
+// addBuff grants a buff, refreshing its expiry if the player already has it.
+// The slice is replaced rather than edited so earlier snapshots keep their copy.
+func (player *Player) addBuff(buffType BuffType, now time.Time) {
+	buffs := make([]ActiveBuff, 0, len(player.ActiveBuffs)+1)
+	for _, buff := range player.ActiveBuffs {
+		if buff.Type != buffType {
+			buffs = append(buffs, buff)
+		}
+	}
+	player.ActiveBuffs = append(buffs, ActiveBuff{Type: buffType, ExpiresAt: now.Add(BuffDuration)})
+	player.updateModifiers()
+}
+
+// hasBuff reports whether the player currently has the given buff
+func (player *Player) hasBuff(buffType BuffType) bool {
+	return slices.ContainsFunc(player.ActiveBuffs, func(buff ActiveBuff) bool {
+		return buff.Type == buffType
+	})
+}
+
+// applyBuffModifiers layers active buffs on top of the stat-derived modifiers
+func (player *Player) applyBuffModifiers() {
+	if player.hasBuff(BuffSpeedBoost) {
+		player.Modifiers.MoveSpeedMultiplier *= SpeedBoostMultiplier
+	}
+	if player.hasBuff(BuffRapidReload) {
+		player.Modifiers.ReloadSpeedMultiplier *= RapidReloadMultiplier
+	}
+}
+
+// clearBuffs removes every buff, e.g. on respawn
+func (player *Player) clearBuffs() {
+	player.ActiveBuffs = nil
+}
+
+// expireBuffs drops buffs whose time has run out and recalculates modifiers
+func (w *World) expireBuffs(now time.Time) {
+	for _, player := range w.players {
+		if len(player.ActiveBuffs) == 0 {
+			continue
+		}
+
+		expired := func(buff ActiveBuff) bool { return !now.Before(buff.ExpiresAt) }
+		if !slices.ContainsFunc(player.ActiveBuffs, expired) {
+			continue
+		}
+
+		player.ActiveBuffs = slices.DeleteFunc(slices.Clone(player.ActiveBuffs), expired)
+		if len(player.ActiveBuffs) == 0 {
+			player.ActiveBuffs = nil
+		}
+		player.updateModifiers()
+	}
+}
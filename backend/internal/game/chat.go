@@ -0,0 +1,116 @@
+package game
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ChatFilter rewrites a sanitized chat message before it is broadcast
+type ChatFilter func(message string) string
+
+// chatFilters run in order on every chat message; append to extend moderation
+var chatFilters = []ChatFilter{maskBlockedWords}
+
+// blockedChatPattern matches whole words that are masked out of chat
+var blockedChatPattern = regexp.MustCompile(`(?i)\b(fuck\w*|shit\w*|bitch\w*|cunt\w*|asshole\w*)\b`)
+
+// maskBlockedWords replaces blocked words with asterisks of the same length
+func maskBlockedWords(message string) string {
+	return blockedChatPattern.ReplaceAllStringFunc(message, func(word string) string {
+		return strings.Repeat("*", utf8.RuneCountInString(word))
+	})
+}
+
+// SanitizeChatMessage strips control characters, collapses whitespace and caps the length
+func SanitizeChatMessage(input string) string {
+	var builder strings.Builder
+	builder.Grow(len(input))
+
+	count := 0
+	lastWasSpace := true // Also drops leading whitespace
+
+	for _, r := range input {
+		if count >= MaxChatMessageLength {
+			break
+		}
+
+		switch {
+		case unicode.IsSpace(r):
+			if !lastWasSpace {
+				builder.WriteRune(' ')
+				count++
+				lastWasSpace = true
+			}
+		case unicode.IsControl(r) || !unicode.IsPrint(r):
+			continue
+		default:
+			builder.WriteRune(r)
+			count++
+			lastWasSpace = false
+		}
+	}
+
+	return strings.TrimSpace(builder.String())
+}
+
+// moderateChatMessage sanitizes a message and runs it through the chat filters
+func moderateChatMessage(input string) string {
+	message := SanitizeChatMessage(input)
+	for _, filter := range chatFilters {
+		if message == "" {
+			break
+		}
+		message = filter(message)
+	}
+	return message
+}
+
+// allowChat applies the per-client rate limit (client.mu must be held)
+func (client *Client) allowChat(now time.Time) bool {
+	if now.Sub(client.chatWindowStart) >= ChatRateWindow {
+		client.chatWindowStart = now
+		client.chatCount = 0
+	}
+	if client.chatCount >= ChatRateLimit {
+		return false
+	}
+	client.chatCount++
+	return true
+}
+
+// canReceiveChat reports whether a chat from sender should reach recipient;
+// players on a team only talk to their own team
+func canReceiveChat(sender, recipient *Player) bool {
+	return sender.Team == "" || sender.Team == recipient.Team
+}
+
+// handleChat moderates a chat message from a client and broadcasts it
+// (sender.mu must be held)
+func (w *World) handleChat(sender *Client, input string, now time.Time) {
+	if !sender.allowChat(now) {
+		return
+	}
+
+	message := moderateChatMessage(input)
+	if message == "" {
+		return
+	}
+
+	msg := ChatMsg{
+		PlayerID: sender.ID,
+		Name:     sender.Player.Name,
+		Message:  message,
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	for _, client := range w.clients {
+		if canReceiveChat(sender.Player, client.Player) {
+			client.sendChat(msg)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package game
+
+import (
+	"strings"
+	"testing"
+)
+
+// chats empties the client's send buffer and returns the chat messages in it
+func chats(client *Client) []ChatMsg {
+	var received []ChatMsg
+	for _, data := range queuedMessages(client) {
+		var chat ChatMsg
+		if decodeTestMsg(data, &chat) && chat.Type == MsgTypeChat {
+			received = append(received, chat)
+		}
+	}
+	return received
+}
+
+func TestChatIsCappedModeratedAndRateLimited(t *testing.T) {
+	w := newTestWorld(t, nil)
+	sender := addTestClient(t, w, 1000, 1000)
+	listener := addTestClient(t, w, 3000, 3000)
+	queuedMessages(listener)
+
+	w.HandleInput(sender.ID, InputMsg{Type: "chat", ChatMessage: "  \x07" + strings.Repeat("a", MaxChatMessageLength+50)})
+	got := chats(listener)
+	if len(got) != 1 || got[0].Message != strings.Repeat("a", MaxChatMessageLength) || got[0].PlayerID != sender.ID {
+		t.Fatalf("long message arrived as %+v, want %d plain characters from %d", got, MaxChatMessageLength, sender.ID)
+	}
+
+	w.HandleInput(sender.ID, InputMsg{Type: "chat", ChatMessage: "well shit"})
+	if got := chats(listener); len(got) != 1 || got[0].Message != "well ****" {
+		t.Errorf("filtered message arrived as %+v, want it masked", got)
+	}
+
+	// The first two messages used up most of the window's allowance
+	for range ChatRateLimit {
+		w.HandleInput(sender.ID, InputMsg{Type: "chat", ChatMessage: "spam"})
+	}
+	if got := chats(listener); len(got) != ChatRateLimit-2 {
+		t.Errorf("%d messages got through the rate limit, want %d", len(got), ChatRateLimit-2)
+	}
+}
+
+func TestTeamChatStaysOnTheTeam(t *testing.T) {
+	w := newTestWorld(t, func(config *WorldConfig) {
+		config.Mode = ModeTeams
+	})
+	sender := addTestClient(t, w, 1000, 1000)
+	enemy := addTestClient(t, w, 2000, 2000)
+	teammate := addTestClient(t, w, 3000, 3000)
+	if sender.Player.Team != teammate.Player.Team || sender.Player.Team == enemy.Player.Team {
+		t.Fatalf("teams: %q, %q and %q", sender.Player.Team, teammate.Player.Team, enemy.Player.Team)
+	}
+
+	w.HandleInput(sender.ID, InputMsg{Type: "chat", ChatMessage: "flank left"})
+	if got := chats(teammate); len(got) != 1 {
+		t.Errorf("teammate received %+v, want the message", got)
+	}
+	if got := chats(enemy); len(got) != 0 {
+		t.Errorf("enemy received team chat %+v", got)
+	}
+}
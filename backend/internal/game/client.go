@@ -2,7 +2,12 @@ package game
 
 import (
 	"github.com/vmihailenco/msgpack/v5"
-	"log"
+	"log/slog"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"time"
 )
 
 // sendAvailableUpgrades sends available upgrades to a specific client
@@ -10,7 +15,7 @@ func (client *Client) sendAvailableUpgrades() {
 	upgrades := make(map[string][]UpgradeInfo)
 
 	// Get available upgrades for each type and convert to simplified format
-	upgradeTypes := []moduleType{UpgradeTypeSide, UpgradeTypeTop, UpgradeTypeFront, UpgradeTypeRear}
+	upgradeTypes := []moduleType{UpgradeTypeSide, UpgradeTypeTop, UpgradeTypeExtraTop, UpgradeTypeFront, UpgradeTypeRear}
 
 	for _, upgradeType := range upgradeTypes {
 		availableUpgrades := client.Player.ShipConfig.GetAvailableModules(upgradeType)
@@ -35,7 +40,7 @@ func (client *Client) sendAvailableUpgrades() {
 
 	data, err := msgpack.Marshal(upgradesMsg)
 	if err != nil {
-		log.Printf("Error marshaling available upgrades message: %v", err)
+		slog.Error("Error marshaling available upgrades message", "err", err)
 		return
 	}
 
@@ -43,7 +48,58 @@ func (client *Client) sendAvailableUpgrades() {
 	case client.Send <- data:
 	default:
 		// Channel full, skip
-		log.Printf("Could not send available upgrades to client %d", client.ID)
+		slog.Debug("Could not send available upgrades, send buffer full", "client", client.ID)
+	}
+}
+
+// queueSnapshot queues a snapshot without blocking. A client that keeps its buffer
+// full for maxDropped snapshots in a row has its connection closed; the read
+// loop then fails and removes it from the world.
+func (client *Client) queueSnapshot(data []byte, maxDropped int32) bool {
+	// Hold the read lock so closeSend cannot close the channel mid-send
+	client.sendMu.RLock()
+	defer client.sendMu.RUnlock()
+	if client.sendClosed {
+		return false
+	}
+
+	select {
+	case client.Send <- data:
+		atomic.StoreInt32(&client.droppedFrames, 0)
+		return true
+	default:
+	}
+
+	if atomic.AddInt32(&client.droppedFrames, 1) == maxDropped && client.Conn != nil {
+		slog.Warn("Client dropped too many snapshots in a row, disconnecting", "client", client.ID, "dropped", maxDropped)
+		client.Conn.Close()
+	}
+	return false
+}
+
+// RecoverPanic logs a panic raised while serving the client and closes its
+// connection, so the read loop fails and the client is removed without taking
+// down the process. It must be deferred directly to catch anything.
+func (client *Client) RecoverPanic(goroutine string) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	slog.Error("Recovered from panic, dropping client",
+		"client", client.ID, "goroutine", goroutine, "panic", r, "stack", string(debug.Stack()))
+	if client.Conn != nil {
+		client.Conn.Close()
+	}
+}
+
+// closeSend closes the send channel once no snapshot goroutine is sending on it
+func (client *Client) closeSend() {
+	client.sendMu.Lock()
+	defer client.sendMu.Unlock()
+	if !client.sendClosed {
+		client.sendClosed = true
+		close(client.Send)
 	}
 }
 
@@ -52,45 +108,222 @@ func (client *Client) sendGameEvent(event GameEventMsg) {
 
 	data, err := msgpack.Marshal(event)
 	if err != nil {
-		log.Printf("Error marshaling game event message: %v", err)
+		slog.Error("Error marshaling game event message", "err", err)
 		return
 	}
 
 	select {
 	case client.Send <- data:
 	default:
-		log.Printf("Could not send game event to client %d", client.ID)
+		slog.Debug("Could not send game event, send buffer full", "client", client.ID)
 	}
 }
 
 func (client *Client) sendResetShipConfig() {
 	resetMsg := ResetShipConfigMsg{
 		Type:       MsgTypeResetShipConfig,
-		ShipConfig: client.Player.ShipConfig.ToMinimalShipConfig(),
+		ShipConfig: client.Player.ShipConfig.ToMinimalShipConfig(reloadClock{player: client.Player, now: time.Now()}),
 	}
 
 	data, err := msgpack.Marshal(resetMsg)
 	if err != nil {
-		log.Printf("Error marshaling reset ship config message: %v", err)
+		slog.Error("Error marshaling reset ship config message", "err", err)
+		return
+	}
+
+	select {
+	case client.Send <- data:
+	default:
+		slog.Debug("Could not send reset ship config, send buffer full", "client", client.ID)
+	}
+}
+
+func (client *Client) sendEmote(emote EmoteMsg) {
+	emote.Type = MsgTypeEmote
+
+	data, err := msgpack.Marshal(emote)
+	if err != nil {
+		slog.Error("Error marshaling emote message", "err", err)
+		return
+	}
+
+	select {
+	case client.Send <- data:
+	default:
+		slog.Debug("Could not send emote, send buffer full", "client", client.ID)
+	}
+}
+
+func (client *Client) sendChat(chat ChatMsg) {
+	chat.Type = MsgTypeChat
+
+	data, err := msgpack.Marshal(chat)
+	if err != nil {
+		slog.Error("Error marshaling chat message", "err", err)
+		return
+	}
+
+	select {
+	case client.Send <- data:
+	default:
+		slog.Debug("Could not send chat, send buffer full", "client", client.ID)
+	}
+}
+
+func (client *Client) sendUnlocks(ranked bool) {
+	unlocksMsg := UnlocksMsg{
+		Type:   MsgTypeUnlocks,
+		Colors: slices.Clone(PresetColors),
+		Flags:  []string{},
+		Ranked: ranked,
+	}
+	if client.Account != nil {
+		unlocksMsg.Colors = append(unlocksMsg.Colors, client.Account.Colors...)
+		unlocksMsg.Flags = append(unlocksMsg.Flags, client.Account.Flags...)
+	}
+
+	data, err := msgpack.Marshal(unlocksMsg)
+	if err != nil {
+		slog.Error("Error marshaling unlocks message", "err", err)
+		return
+	}
+
+	select {
+	case client.Send <- data:
+	default:
+		slog.Debug("Could not send unlocks, send buffer full", "client", client.ID)
+	}
+}
+
+func (client *Client) sendRoundEnd(roundEnd RoundEndMsg) {
+	data, err := msgpack.Marshal(roundEnd)
+	if err != nil {
+		slog.Error("Error marshaling round end message", "err", err)
 		return
 	}
 
 	select {
 	case client.Send <- data:
 	default:
-		log.Printf("Could not send reset ship config to client %d", client.ID)
+		slog.Debug("Could not send round end, send buffer full", "client", client.ID)
+	}
+}
+
+func (client *Client) sendDuel(duel DuelMsg) {
+	duel.Type = MsgTypeDuel
+
+	data, err := msgpack.Marshal(duel)
+	if err != nil {
+		slog.Error("Error marshaling duel message", "err", err)
+		return
+	}
+
+	select {
+	case client.Send <- data:
+	default:
+		slog.Debug("Could not send duel, send buffer full", "client", client.ID)
+	}
+}
+
+func (client *Client) sendCorrection(correction CorrectionMsg) {
+	correction.Type = MsgTypeCorrection
+
+	data, err := msgpack.Marshal(correction)
+	if err != nil {
+		slog.Error("Error marshaling correction message", "err", err)
+		return
+	}
+
+	select {
+	case client.Send <- data:
+	default:
+		slog.Debug("Could not send correction, send buffer full", "client", client.ID)
+	}
+}
+
+func (client *Client) sendAdminResult(result AdminResultMsg) {
+	result.Type = MsgTypeAdminResult
+
+	data, err := msgpack.Marshal(result)
+	if err != nil {
+		slog.Error("Error marshaling admin result message", "err", err)
+		return
+	}
+
+	select {
+	case client.Send <- data:
+	default:
+		slog.Debug("Could not send admin result, send buffer full", "client", client.ID)
+	}
+}
+
+func (client *Client) sendBotDebug(debug BotDebugMsg) {
+	debug.Type = MsgTypeBotDebug
+
+	data, err := msgpack.Marshal(debug)
+	if err != nil {
+		slog.Error("Error marshaling bot debug message", "err", err)
+		return
+	}
+
+	select {
+	case client.Send <- data:
+	default:
+		slog.Debug("Could not send bot debug, send buffer full", "client", client.ID)
+	}
+}
+
+func (client *Client) sendMapInfo(config WorldConfig, currents []Current) {
+	mapInfoMsg := MapInfoMsg{
+		Type:        MsgTypeMapInfo,
+		WorldWidth:  WorldWidth,
+		WorldHeight: WorldHeight,
+
+		XPMultiplier:   config.XPMultiplier,
+		CoinMultiplier: config.CoinMultiplier,
+
+		MinTurnFactor:     config.MinTurnFactor,
+		TurnSpeedExponent: config.TurnSpeedExponent,
+
+		Currents: currents,
+
+		SinkDuration: SinkDuration.Milliseconds(),
+	}
+
+	data, err := msgpack.Marshal(mapInfoMsg)
+	if err != nil {
+		slog.Error("Error marshaling map info message", "err", err)
+		return
+	}
+
+	select {
+	case client.Send <- data:
+	default:
+		slog.Debug("Could not send map info, send buffer full", "client", client.ID)
+	}
+}
+
+// ProtocolCompatible reports whether a client declaring the given protocol
+// version (the ?protocol= query parameter) can talk to this server. Clients
+// that don't declare one, such as scripts and load testers, are let in.
+func ProtocolCompatible(declared string) bool {
+	if declared == "" {
+		return true
 	}
+	version, err := strconv.Atoi(declared)
+	return err == nil && version == ProtocolVersion
 }
 
 func (client *Client) sendWelcomeMessage() {
 	welcomeMsg := WelcomeMsg{
 		Type:     MsgTypeWelcome,
 		PlayerId: client.ID,
+		Protocol: ProtocolVersion,
 	}
 
 	data, err := msgpack.Marshal(welcomeMsg)
 	if err != nil {
-		log.Printf("Error marshaling welcome message: %v", err)
+		slog.Error("Error marshaling welcome message", "err", err)
 		return
 	}
 
@@ -98,6 +331,6 @@ func (client *Client) sendWelcomeMessage() {
 	case client.Send <- data:
 	default:
 		// Channel full, skip
-		log.Printf("Could not send welcome message to client %d", client.ID)
+		slog.Debug("Could not send welcome message, send buffer full", "client", client.ID)
 	}
 }
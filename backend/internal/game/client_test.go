@@ -0,0 +1,121 @@
+package game
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// newTestConn returns the server side of a live websocket connection
+func newTestConn(t *testing.T) *websocket.Conn {
+	t.Helper()
+	conns := make(chan *websocket.Conn, 1)
+	upgrader := websocket.Upgrader{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+		conns <- conn
+	}))
+	t.Cleanup(ts.Close)
+
+	dialed, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { dialed.Close() })
+	conn := <-conns
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestSlowClientIsDisconnectedAfterTheTimeout(t *testing.T) {
+	config := DefaultWorldConfig()
+	if got := config.maxDroppedFrames(); got != 90 {
+		t.Errorf("30 TPS allows %d dropped snapshots, want 90", got)
+	}
+	config.TickRate = 60
+	if got := config.maxDroppedFrames(); got != 180 {
+		t.Errorf("60 TPS allows %d dropped snapshots, want 180", got)
+	}
+
+	const limit = 5
+	client := NewClient(1, newTestConn(t))
+	for client.queueSnapshot([]byte{0}, limit) {
+	}
+	for range limit - 2 {
+		client.queueSnapshot([]byte{0}, limit)
+	}
+	if err := client.Conn.WriteMessage(websocket.BinaryMessage, []byte{0}); err != nil {
+		t.Fatalf("connection closed before the limit: %v", err)
+	}
+
+	// Emptying the buffer forgives the dropped snapshots
+	<-client.Send
+	client.queueSnapshot([]byte{0}, limit)
+	for range limit - 1 {
+		client.queueSnapshot([]byte{0}, limit)
+	}
+	if err := client.Conn.WriteMessage(websocket.BinaryMessage, []byte{0}); err != nil {
+		t.Fatalf("connection closed before the limit after a reset: %v", err)
+	}
+	client.queueSnapshot([]byte{0}, limit)
+	if err := client.Conn.WriteMessage(websocket.BinaryMessage, []byte{0}); err == nil {
+		t.Error("slow client still connected after the limit")
+	}
+}
+
+func TestPanickingClientIsDroppedWhileOthersKeepPlaying(t *testing.T) {
+	filters := chatFilters
+	t.Cleanup(func() { chatFilters = filters })
+	chatFilters = append(slices.Clone(filters), func(message string) string {
+		if message == "boom" {
+			panic("corrupt chat state")
+		}
+		return message
+	})
+
+	w := newTestWorld(t, nil)
+	faulty := NewClient(0, newTestConn(t))
+	if !w.AddClient(faulty) {
+		t.Fatal("AddClient refused the client")
+	}
+	healthy := addTestClient(t, w, 2000, 2000)
+	queuedMessages(healthy)
+
+	// Serve the faulty client's input the way its read goroutine does
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer w.RemoveClient(faulty.ID)
+		defer faulty.RecoverPanic("read")
+		w.HandleInput(faulty.ID, InputMsg{Type: "chat", ChatMessage: "boom"})
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("panicking input never finished")
+	}
+
+	if _, connected := w.GetClient(faulty.ID); connected {
+		t.Error("panicking client is still in the world")
+	}
+	if err := faulty.Conn.WriteMessage(websocket.BinaryMessage, []byte{0}); err == nil {
+		t.Error("panicking client's connection is still open")
+	}
+
+	// Nothing was left locked: the healthy client still chats and gets snapshots
+	w.HandleInput(healthy.ID, InputMsg{Type: "chat", ChatMessage: "still here"})
+	if got := chats(healthy); len(got) != 1 || got[0].Message != "still here" {
+		t.Errorf("healthy client's chat = %+v, want its own message", got)
+	}
+	w.update()
+	nextSnapshot(t, healthy)
+}
@@ -1,7 +1,8 @@
 package game
 
 import (
-	"log"
+	"log/slog"
+	"math"
 	"time"
 )
 
@@ -12,20 +13,69 @@ const (
 	KillCauseBullet    KillCause = "bullet"
 	KillCauseCollision KillCause = "collision"
 	KillCauseRam       KillCause = "ram"
+	KillCauseBorder    KillCause = "border"
+	KillCauseZone      KillCause = "zone"
 )
 
+// CombatStats tallies one life of a ship's fighting, for the death summary
+// and the combat log
+type CombatStats struct {
+	ShotsFired  int     `msgpack:"shotsFired"`  // Bullets fired
+	Hits        int     `msgpack:"hits"`        // Bullets that struck a ship (a piercing bullet counts once)
+	DamageDealt float64 `msgpack:"damageDealt"` // Health removed from other ships
+	DamageTaken float64 `msgpack:"damageTaken"` // Health lost, from any source
+	Accuracy    float64 `msgpack:"accuracy"`    // Hits per shot, filled in by summary
+}
+
+// summary returns the stats with Accuracy filled in
+func (stats CombatStats) summary() CombatStats {
+	stats.Accuracy = 0
+	if stats.ShotsFired > 0 {
+		stats.Accuracy = float64(stats.Hits) / float64(stats.ShotsFired)
+	}
+	return stats
+}
+
 // ApplyDamage subtracts health from the target and handles death side-effects.
 func (gm *GameMechanics) ApplyDamage(target *Player, damage float64, attacker *Player, cause KillCause, now time.Time) bool {
 	if target == nil || target.State != StateAlive || damage <= 0 {
 		return false
 	}
 
+	// Freshly respawned players can neither take nor deal damage
+	if target.isSpawnProtected(now) {
+		return false
+	}
+	if attacker != nil && attacker != target && attacker.isSpawnProtected(now) {
+		return false
+	}
+
+	if target.hasBuff(BuffShield) {
+		damage *= ShieldDamageMultiplier
+	}
+
 	if damage == 0 {
-		log.Printf("Warning: Attempted to apply zero damage to Player %d", target.ID)
+		slog.Warn("Attempted to apply zero damage", "player", target.ID)
 		damage = 1.0 // Ensure at least 1.0 damage is applied
 	}
 
+	// Overkill isn't counted; only the health actually removed
+	dealt := min(damage, target.Health)
+	target.Combat.DamageTaken += dealt
 	target.Health -= damage
+	if attacker != nil && attacker != target {
+		attacker.Combat.DamageDealt += dealt
+		target.recordDamage(attacker.ID, damage, now)
+	}
+	if damage >= MinDamageEventAmount {
+		gm.world.damageEvents = append(gm.world.damageEvents, DamageEvent{
+			TargetID: target.ID,
+			Amount:   damage,
+			Cause:    cause,
+			X:        target.X,
+			Y:        target.Y,
+		})
+	}
 	if target.Health > 0 {
 		return false
 	}
@@ -44,52 +94,252 @@ func (gm *GameMechanics) handlePlayerDeath(victim *Player, killer *Player, cause
 	if !victim.SpawnTime.IsZero() {
 		victim.SurvivalTime = now.Sub(victim.SpawnTime).Seconds()
 	}
+	if victim.IsBot {
+		victim.RespawnTime = now.Add(gm.world.config.Bots.RespawnDelay)
+	}
 
 	if killer != nil {
-		xpReward, coinReward := gm.calculateKillOutcome(victim)
+		xpReward, coinReward := gm.calculateKillOutcome(killer, victim, now)
 
 		// Track who killed the victim
 		victim.KilledBy = killer.ID
 		victim.KilledByName = killer.Name
 
 		// Apply rewards to killer
-		killer.AddExperience(xpReward)
-		killer.Score += xpReward
-		killer.Coins += coinReward
-
-		log.Printf("Player %d (%s) was killed by %s from Player %d (%s)",
-			victim.ID, victim.Name, cause.describe(), killer.ID, killer.Name)
-		log.Printf("Player %d gained %d XP and %d coins for killing Player %d (victim now has %d XP and %d coins)",
-			killer.ID, xpReward, coinReward, victim.ID, victim.Experience, victim.Coins)
-
-		if killer.ID != victim.ID && !killer.IsBot {
-			if client, exists := gm.world.GetClient(killer.ID); exists {
-				client.sendGameEvent(GameEventMsg{
-					EventType:  "playerSunk",
-					KillerID:   killer.ID,
-					KillerName: killer.Name,
-					VictimID:   victim.ID,
-					VictimName: victim.Name,
-				})
-			}
+		gm.world.notifyLevelUp(killer, killer.AddExperience(xpReward, gm.world.config.MaxLevel))
+		killer.addRewards(xpReward, coinReward, gm.world.config)
+		// Sinking a practice dummy doesn't count toward rounds or the kill feed
+		countsAsKill := killer.ID != victim.ID && !gm.world.isDummy(victim)
+		if countsAsKill {
+			killer.Kills++
+		}
+
+		slog.Info("Player killed",
+			"player", victim.ID, "name", victim.Name, "cause", cause.describe(), "killer", killer.ID, "killerName", killer.Name,
+			"xpReward", xpReward, "coinReward", coinReward, "victimXP", victim.Experience, "victimCoins", victim.Coins)
+
+		if countsAsKill {
+			gm.world.broadcastKill(GameEventMsg{
+				EventType:  "playerSunk",
+				KillerID:   killer.ID,
+				KillerName: killer.Name,
+				VictimID:   victim.ID,
+				VictimName: victim.Name,
+				Time:       now.UnixMilli(),
+			})
 		}
 	} else {
 		// No killer (e.g., suicide or environment)
 		victim.KilledBy = 0
 		victim.KilledByName = ""
-		log.Printf("Player %d (%s) died due to %s", victim.ID, victim.Name, cause.describe())
+		slog.Info("Player died", "player", victim.ID, "name", victim.Name, "cause", cause.describe())
+	}
+
+	gm.reportCombatStats(victim)
+	gm.rewardAssists(victim, killer, now)
+	victim.RecentDamagers = nil
+	gm.world.clearBounty(victim)
+	gm.dropCargo(victim, killer, now)
+
+	gm.world.checkDuelOver(victim)
+}
+
+// reportCombatStats sends a sunk ship's combat stats to its client for the
+// death screen, and logs them when the combat log is on
+func (gm *GameMechanics) reportCombatStats(victim *Player) {
+	stats := victim.Combat.summary()
+	if gm.world.config.CombatLog {
+		slog.Info("Combat stats",
+			"player", victim.ID, "name", victim.Name, "shotsFired", stats.ShotsFired, "hits", stats.Hits,
+			"accuracy", stats.Accuracy, "damageDealt", stats.DamageDealt, "damageTaken", stats.DamageTaken)
+	}
+	if client, exists := gm.world.clients[victim.ID]; exists {
+		client.sendGameEvent(GameEventMsg{
+			EventType: "combatSummary",
+			Combat:    &stats,
+		})
+	}
+}
+
+// dropCargo spills CoinDropFraction of a sunk ship's coins around the wreck as
+// coin items, as many as MaxItems leaves room for, reserved briefly for the
+// killer. Only the coins actually dropped are taken from the victim.
+func (gm *GameMechanics) dropCargo(victim, killer *Player, now time.Time) {
+	config := gm.world.config
+	if config.CoinDropFraction <= 0 || config.DisableItems {
+		return
+	}
+
+	total := int(float64(victim.Coins) * config.CoinDropFraction)
+	count := min(CoinDropMaxItems, total/CoinDropMinValue, MaxItems-len(gm.world.items))
+	if count <= 0 {
+		return
+	}
+
+	// The killer gets first claim on the cargo (see WorldConfig.LootOwnershipWindow)
+	var ownerID uint32
+	if killer != nil && killer.ID != victim.ID {
+		ownerID = killer.ID
+	}
+
+	value := total / count
+	dropped := 0
+	for i := range count {
+		coins := value
+		if i == count-1 {
+			coins = total - dropped // The last item carries the remainder
+		}
+		angle := gm.world.rng.Float64() * 2 * math.Pi
+		distance := gm.world.rng.Float64() * CoinDropScatter
+		x := clampfloat64(victim.X+math.Cos(angle)*distance, 0, WorldWidth)
+		y := clampfloat64(victim.Y+math.Sin(angle)*distance, 0, WorldHeight)
+		gm.SpawnLoot(x, y, ItemTypeCoinDrop, coins, 0, ownerID, now)
+		dropped += coins
+	}
+
+	victim.Coins -= dropped
+	slog.Debug("Ship spilled its cargo", "player", victim.ID, "coins", dropped, "items", count)
+}
+
+// DamageContribution is how much one attacker has hurt a ship and when they last hit it
+type DamageContribution struct {
+	Amount  float64 // Total this life, for dummy reports
+	LastHit time.Time
+	hits    []damageHit // Hits within AssistWindow, oldest first
+}
+
+// damageHit is a single hit kept for assist credit
+type damageHit struct {
+	amount float64
+	at     time.Time
+}
+
+// recentDamage sums the damage dealt within AssistWindow before now
+func (contribution DamageContribution) recentDamage(now time.Time) float64 {
+	total := 0.0
+	for _, hit := range contribution.hits {
+		if now.Sub(hit.at) <= AssistWindow {
+			total += hit.amount
+		}
 	}
+	return total
 }
 
-func (gm *GameMechanics) calculateKillOutcome(victim *Player) (xpReward int, coinReward int) {
-	xpReward = max(victim.Experience/2, 100)
+// recordDamage credits an attacker with damage dealt to the player, forgetting
+// hits that have fallen out of the assist window
+func (player *Player) recordDamage(attackerID uint32, damage float64, now time.Time) {
+	if player.RecentDamagers == nil {
+		player.RecentDamagers = make(map[uint32]DamageContribution)
+	}
+	contribution := player.RecentDamagers[attackerID]
+	contribution.Amount += damage
+	contribution.LastHit = now
+
+	stale := 0
+	for stale < len(contribution.hits) && now.Sub(contribution.hits[stale].at) > AssistWindow {
+		stale++
+	}
+	contribution.hits = append(contribution.hits[stale:], damageHit{amount: damage, at: now})
+	player.RecentDamagers[attackerID] = contribution
+}
+
+// rewardAssists pays every player, other than the killer, who dealt at least
+// AssistMinDamageFraction of the victim's max health within AssistWindow a
+// share of the reward they would have earned for the kill
+func (gm *GameMechanics) rewardAssists(victim, killer *Player, now time.Time) {
+	minDamage := victim.MaxHealth * AssistMinDamageFraction
+	for attackerID, contribution := range victim.RecentDamagers {
+		if (killer != nil && attackerID == killer.ID) || attackerID == victim.ID {
+			continue
+		}
+		damage := contribution.recentDamage(now)
+		if damage < minDamage {
+			continue
+		}
+		assister, exists := gm.world.players[attackerID]
+		if !exists {
+			continue
+		}
+
+		xpReward, coinReward := gm.calculateKillOutcome(assister, victim, now)
+		xpReward = int(float64(xpReward) * AssistRewardFraction)
+		coinReward = int(float64(coinReward) * AssistRewardFraction)
+
+		gm.world.notifyLevelUp(assister, assister.AddExperience(xpReward, gm.world.config.MaxLevel))
+		assister.addRewards(xpReward, coinReward, gm.world.config)
+
+		slog.Info("Player assisted",
+			"player", assister.ID, "name", assister.Name, "victim", victim.ID, "damage", damage,
+			"xpReward", xpReward, "coinReward", coinReward)
+
+		if client, exists := gm.world.clients[assister.ID]; exists {
+			client.sendGameEvent(GameEventMsg{
+				EventType:  "assist",
+				VictimID:   victim.ID,
+				VictimName: victim.Name,
+				Time:       now.UnixMilli(),
+			})
+		}
+	}
+}
+
+func (gm *GameMechanics) calculateKillOutcome(killer, victim *Player, now time.Time) (xpReward int, coinReward int) {
+	// Practice dummies are free to sink, so they pay nothing
+	if gm.world.isDummy(victim) {
+		return 0, 0
+	}
+
+	xpReward = max(min(victim.Experience/2, maxKillXPReward(victim.Level)), MinKillXPReward)
 	// use score to not penalize players for killing players who have spent everything
-	coinReward = max(victim.Score/2, 200)
-	if coinReward > 2000 {
-		coinReward = 2000
+	coinReward = min(max(victim.Score/2, MinKillCoinReward), maxKillCoinReward(victim.Level))
+
+	// The bounty pays out on top of the usual cap
+	if victim.IsBounty {
+		xpReward = int(float64(xpReward) * BountyRewardMultiplier)
+		coinReward = int(float64(coinReward) * BountyRewardMultiplier)
+	}
+
+	// Players parked in one spot farming bots earn progressively less
+	if victim.IsBot {
+		multiplier := killer.campingRewardMultiplier(now)
+		xpReward = int(float64(xpReward) * multiplier)
+		coinReward = int(float64(coinReward) * multiplier)
+	}
+
+	config := gm.world.config
+	xpReward, coinReward = config.scaleRewards(xpReward, coinReward)
+	return saturatingAdd(0, xpReward, config.MaxScore), saturatingAdd(0, coinReward, config.MaxCoins)
+}
+
+// maxKillXPReward caps the experience a kill pays at a few of the victim's levels
+func maxKillXPReward(level int) int {
+	span := GetExperienceRequiredForLevel(level+1) - GetExperienceRequiredForLevel(level)
+	return KillXPLevelSpans * span
+}
+
+// maxKillCoinReward caps the coins a kill pays in proportion to the victim's level
+func maxKillCoinReward(level int) int {
+	return max(KillCoinsPerLevel*level, MinKillCoinReward)
+}
+
+// armorMultiplier scales bullet damage by the facet of the hull a bullet
+// travelling at (velX, velY) strikes: the bow (plus any front armor modules)
+// shrugs off part of it, the stern takes extra, the sides take it as is
+func (player *Player) armorMultiplier(velX, velY float64) float64 {
+	if velX == 0 && velY == 0 {
+		return 1
 	}
 
-	return
+	// The facet faces back along the bullet's path
+	facing := math.Abs(normalizeAngle(math.Atan2(-velY, -velX) - player.Angle))
+	switch {
+	case facing <= ArmorFrontArc:
+		return math.Max(ArmorFrontMultiplier-player.Modifiers.FrontArmor, ArmorMinMultiplier)
+	case facing >= math.Pi-ArmorRearArc:
+		return ArmorRearMultiplier
+	default:
+		return 1
+	}
 }
 
 func (cause KillCause) describe() string {
@@ -100,6 +350,10 @@ func (cause KillCause) describe() string {
 		return "collision damage"
 	case KillCauseRam:
 		return "a ram"
+	case KillCauseBorder:
+		return "the world border"
+	case KillCauseZone:
+		return "the closing zone"
 	default:
 		return string(cause)
 	}
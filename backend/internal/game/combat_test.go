@@ -0,0 +1,426 @@
+package game
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSunkCargoIsReservedForKiller(t *testing.T) {
+	w := newTestWorld(t, func(config *WorldConfig) {
+		config.CoinDropFraction = 0.5
+	})
+	killer := addTestClient(t, w, 1000, 1000).Player
+	victim := addTestClient(t, w, 1200, 1000).Player
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	victim.Coins = 1000
+	now := time.Now()
+	w.mechanics.ApplyDamage(victim, victim.Health+1, killer, KillCauseBullet, now)
+
+	if victim.State != StateDead {
+		t.Fatal("victim survived")
+	}
+	drops := 0
+	for _, item := range w.items {
+		if item.Type != ItemTypeCoinDrop {
+			continue
+		}
+		drops++
+		if item.OwnerID != killer.ID {
+			t.Errorf("coin drop %d owner = %d, want killer %d", item.ID, item.OwnerID, killer.ID)
+		}
+		if item.collectibleBy(victim.ID, now) {
+			t.Errorf("coin drop %d collectible by someone other than the killer", item.ID)
+		}
+	}
+	if drops == 0 {
+		t.Fatal("no cargo dropped")
+	}
+}
+
+func TestCombatStatsCountHitsAndDamage(t *testing.T) {
+	w := newTestWorld(t, nil)
+	attacker := addTestClient(t, w, 1000, 1000).Player
+	target := addTestClient(t, w, 1300, 1000).Player
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	now := time.Now()
+	w.mechanics.ApplyDamage(target, 30, attacker, KillCauseBullet, now)
+	w.mechanics.ApplyDamage(target, target.Health+50, attacker, KillCauseBullet, now)
+
+	if attacker.Combat.DamageDealt != 100 || target.Combat.DamageTaken != 100 {
+		t.Errorf("dealt %v, taken %v; want both capped at the 100 health removed",
+			attacker.Combat.DamageDealt, target.Combat.DamageTaken)
+	}
+
+	stats := CombatStats{ShotsFired: 8, Hits: 2}.summary()
+	if stats.Accuracy != 0.25 {
+		t.Errorf("accuracy = %v, want 0.25", stats.Accuracy)
+	}
+	if (CombatStats{}).summary().Accuracy != 0 {
+		t.Error("accuracy without shots should be 0")
+	}
+}
+
+func TestBotRespawnClearsCombatState(t *testing.T) {
+	w := newTestWorld(t, func(config *WorldConfig) {
+		config.Bots.Count = 1
+	})
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.spawnBots()
+	var bot *Bot
+	for _, b := range w.bots {
+		bot = b
+	}
+	bot.Player.Combat = CombatStats{ShotsFired: 10, Hits: 4, DamageDealt: 80}
+	bot.Player.RecentDamagers = map[uint32]DamageContribution{7: {Amount: 40, LastHit: time.Now()}}
+	bot.Player.State = StateDead
+
+	w.respawnBot(bot, time.Now())
+	if bot.Player.Combat != (CombatStats{}) || bot.Player.RecentDamagers != nil {
+		t.Errorf("after respawn: combat %+v, damagers %v; want both cleared", bot.Player.Combat, bot.Player.RecentDamagers)
+	}
+}
+
+func TestAssistsOnlyCountDamageWithinTheWindow(t *testing.T) {
+	w := newTestWorld(t, nil)
+	killer := addTestClient(t, w, 1000, 1000).Player
+	veteran := addTestClient(t, w, 1300, 1000).Player
+	fresh := addTestClient(t, w, 1000, 1300).Player
+	victim := addTestClient(t, w, 1300, 1300).Player
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	now := time.Now()
+	share := victim.MaxHealth * AssistMinDamageFraction
+
+	// The veteran's total clears the threshold, but most of it is stale
+	victim.recordDamage(veteran.ID, share, now.Add(-3*AssistWindow))
+	victim.recordDamage(veteran.ID, share/2, now)
+	victim.recordDamage(fresh.ID, share, now)
+	veteranXP, freshXP := veteran.Experience, fresh.Experience
+
+	w.mechanics.ApplyDamage(victim, victim.Health+1, killer, KillCauseBullet, now)
+	if victim.State != StateDead {
+		t.Fatal("victim survived")
+	}
+	if veteran.Experience != veteranXP {
+		t.Errorf("veteran earned an assist for damage outside the window")
+	}
+	if fresh.Experience == freshXP {
+		t.Errorf("fresh attacker earned no assist")
+	}
+	if got := victim.RecentDamagers; got != nil {
+		t.Errorf("damagers not cleared on death: %v", got)
+	}
+}
+
+func TestKillRewardsAreBoundedByTheVictimsLevel(t *testing.T) {
+	w := newTestWorld(t, nil)
+	killer := addTestClient(t, w, 1000, 1000).Player
+	victim := addTestClient(t, w, 1300, 1000).Player
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	victim.Level = 5
+	victim.Experience = MaxExperience
+	victim.Score = w.config.MaxScore
+
+	xp, coins := w.mechanics.calculateKillOutcome(killer, victim, time.Now())
+	if xp != maxKillXPReward(5) || coins != maxKillCoinReward(5) {
+		t.Errorf("rewards = %d XP, %d coins; want the level 5 caps %d and %d",
+			xp, coins, maxKillXPReward(5), maxKillCoinReward(5))
+	}
+
+	killer.Score = w.config.MaxScore - 1
+	killer.Coins = w.config.MaxCoins - 1
+	killer.addRewards(xp, coins, w.config)
+	if killer.Score != w.config.MaxScore || killer.Coins != w.config.MaxCoins {
+		t.Errorf("score %d, coins %d; want both saturated at their caps", killer.Score, killer.Coins)
+	}
+}
+
+func TestBulletFromADisconnectedShooterStillHits(t *testing.T) {
+	w := newTestWorld(t, nil)
+	shooter := addTestClient(t, w, 1000, 1000)
+	target := addTestClient(t, w, 1300, 1000).Player
+
+	w.mu.Lock()
+	w.bullets[w.bulletID] = &Bullet{
+		ID: w.bulletID, X: target.X, Y: target.Y, OwnerID: shooter.ID,
+		CreatedAt: time.Now(), Radius: BulletSize, Damage: 10,
+	}
+	w.bulletID++
+	w.mu.Unlock()
+	w.RemoveClient(shooter.ID)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.updateBullets()
+	if target.Health != target.MaxHealth-10 {
+		t.Errorf("target health = %v, want %v", target.Health, target.MaxHealth-10)
+	}
+	if len(w.bullets) != 0 {
+		t.Error("bullet survived hitting the target")
+	}
+}
+
+func TestCampingFarmerEarnsLessForBotKills(t *testing.T) {
+	w := newTestWorld(t, nil)
+	camper := addTestClient(t, w, 1000, 1000).Player
+	active := addTestClient(t, w, 3000, 3000).Player
+	bot := NewPlayer(99)
+	bot.IsBot = true
+	bot.Experience, bot.Score = 2000, 2000
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	start := time.Now()
+	for _, player := range []*Player{camper, active} {
+		player.updateCampingState(start)
+	}
+	active.X += CampDisplacementMin * 2
+	windowEnd := start.Add(CampWindow)
+	for _, player := range []*Player{camper, active} {
+		player.updateCampingState(windowEnd)
+	}
+
+	killTime := windowEnd.Add(20 * time.Second)
+	camperXP, camperCoins := w.mechanics.calculateKillOutcome(camper, bot, killTime)
+	activeXP, activeCoins := w.mechanics.calculateKillOutcome(active, bot, killTime)
+	if camperXP >= activeXP || camperCoins >= activeCoins {
+		t.Errorf("camper earned %d XP and %d coins, active player %d XP and %d coins; want the camper to earn less",
+			camperXP, camperCoins, activeXP, activeCoins)
+	}
+
+	// Sinking another player pays the camper in full
+	human := NewPlayer(100)
+	human.Experience, human.Score = 2000, 2000
+	if xp, _ := w.mechanics.calculateKillOutcome(camper, human, killTime); xp != activeXP {
+		t.Errorf("camper earned %d XP for a player kill, want the full %d", xp, activeXP)
+	}
+}
+
+func TestSplashHurtsBystandersLessThanTheTarget(t *testing.T) {
+	w := newTestWorld(t, nil)
+	shooter := addTestClient(t, w, 1000, 1000).Player
+	target := addTestClient(t, w, 2000, 2000).Player
+	bystander := addTestClient(t, w, 2000, 2060).Player
+	outsider := addTestClient(t, w, 2000, 2000-NewBigCannon().SplashRadius-20).Player
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.bullets[w.bulletID] = &Bullet{
+		ID: w.bulletID, X: target.X, Y: target.Y, OwnerID: shooter.ID,
+		CreatedAt: time.Now(), Radius: BulletSize, Damage: 40,
+		SplashRadius: NewBigCannon().SplashRadius,
+	}
+	w.bulletID++
+	w.updateBullets()
+
+	targetLoss := target.MaxHealth - target.Health
+	bystanderLoss := bystander.MaxHealth - bystander.Health
+	if targetLoss != 40 {
+		t.Errorf("direct target lost %v health, want 40", targetLoss)
+	}
+	if bystanderLoss <= 0 || bystanderLoss >= targetLoss {
+		t.Errorf("bystander lost %v health, want some but less than the target's %v", bystanderLoss, targetLoss)
+	}
+	if outsider.Health != outsider.MaxHealth || shooter.Health != shooter.MaxHealth {
+		t.Errorf("outsider health %v, shooter health %v; want both untouched", outsider.Health, shooter.Health)
+	}
+}
+
+func TestReservedLootWaitsForTheKillerUntilTheWindowEnds(t *testing.T) {
+	w := newTestWorld(t, nil)
+	killer := addTestClient(t, w, 1000, 1000).Player
+	vulture := addTestClient(t, w, 3000, 3000).Player
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	now := time.Now()
+	reserved := w.mechanics.SpawnLoot(vulture.X, vulture.Y, ItemTypeCoinDrop, 50, 0, killer.ID, now)
+	w.checkCollisions()
+	if _, exists := w.items[reserved.ID]; !exists || vulture.Coins != 0 {
+		t.Fatal("vulture collected loot reserved for the killer")
+	}
+
+	killer.X, killer.Y = reserved.X, reserved.Y
+	w.checkCollisions()
+	if _, exists := w.items[reserved.ID]; exists || killer.Coins != 50 {
+		t.Errorf("killer did not collect their own loot (coins %d)", killer.Coins)
+	}
+
+	// Once the window has passed anyone can take it
+	killer.X, killer.Y = 1000, 1000
+	expired := w.mechanics.SpawnLoot(vulture.X, vulture.Y, ItemTypeCoinDrop, 50, 0, killer.ID, now.Add(-w.config.LootOwnershipWindow))
+	w.checkCollisions()
+	if _, exists := w.items[expired.ID]; exists || vulture.Coins != 50 {
+		t.Errorf("vulture could not collect loot after the window (coins %d)", vulture.Coins)
+	}
+}
+
+func TestKillFeedReachesEveryoneAndReplaysTheLatestToJoiners(t *testing.T) {
+	w := newTestWorld(t, nil)
+	killer := addTestClient(t, w, 1000, 1000).Player
+	victim := addTestClient(t, w, 1300, 1000).Player
+	watcher := addTestClient(t, w, 3000, 3000)
+	queuedMessages(watcher)
+
+	// Sink the same ship under a new name each time
+	const kills = KillFeedSize + 3
+	all := ""
+	w.mu.Lock()
+	for i := range kills {
+		victim.Name = string(rune('A' + i))
+		all += victim.Name
+		victim.State, victim.Health = StateAlive, victim.MaxHealth
+		w.mechanics.ApplyDamage(victim, victim.Health+1, killer, KillCauseBullet, time.Now())
+	}
+	w.mu.Unlock()
+
+	// sunkNames returns the victims of the playerSunk events queued for a client
+	sunkNames := func(client *Client) string {
+		names := ""
+		for _, event := range gameEvents(t, client) {
+			if event.EventType == "playerSunk" {
+				names += event.VictimName
+			}
+		}
+		return names
+	}
+
+	if got := sunkNames(watcher); got != all {
+		t.Errorf("watcher saw kills %q, want all %d", got, kills)
+	}
+	joiner := addTestClient(t, w, 4000, 4000)
+	if got := sunkNames(joiner); got != all[kills-KillFeedSize:] {
+		t.Errorf("joiner was replayed kills %q, want the latest %d", got, KillFeedSize)
+	}
+}
+
+func TestBulletsHitSofterAtTheEndOfTheirFlight(t *testing.T) {
+	if NewScatterCannon().DamageFalloff <= NewBigCannon().DamageFalloff {
+		t.Error("scatter shots should fall off faster than big cannon shells")
+	}
+
+	w := newTestWorld(t, nil)
+	shooter := addTestClient(t, w, 1000, 1000)
+	near := addTestClient(t, w, 2000, 1000).Player
+	far := addTestClient(t, w, 2000, 2000).Player
+
+	const damage, flight = 20.0, 400.0
+	falloff := NewScatterCannon().DamageFalloff
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, shot := range []struct {
+		target   *Player
+		traveled float64
+	}{{near, 0}, {far, flight}} {
+		w.bullets[w.bulletID] = &Bullet{
+			ID: w.bulletID, X: shot.target.X, Y: shot.target.Y, OwnerID: shooter.ID,
+			CreatedAt: time.Now(), Radius: BulletSize, Damage: damage,
+			Falloff: falloff, FlightRange: flight, Traveled: shot.traveled,
+		}
+		w.bulletID++
+	}
+	w.updateBullets()
+
+	if got := near.MaxHealth - near.Health; math.Abs(got-damage) > 1e-9 {
+		t.Errorf("point-blank damage = %v, want %v", got, damage)
+	}
+	if got, want := far.MaxHealth-far.Health, damage*(1-falloff); math.Abs(got-want) > 1e-9 {
+		t.Errorf("max-range damage = %v, want %v", got, want)
+	}
+}
+
+func TestBonusMultipliersDoubleItemAndKillRewards(t *testing.T) {
+	// rewards returns what a player earns from collecting an item and then
+	// sinking another player under the given bonus multiplier
+	rewards := func(multiplier float64) (itemXP, itemCoins, killXP, killCoins int) {
+		w := newTestWorld(t, func(config *WorldConfig) {
+			config.XPMultiplier = multiplier
+			config.CoinMultiplier = multiplier
+		})
+		client := addTestClient(t, w, 1000, 1000)
+		victim := addTestClient(t, w, 3000, 3000).Player
+		stop := make(chan struct{})
+		defer close(stop)
+		go drainClient(client, stop)
+		player := client.Player
+
+		w.mu.Lock()
+		victim.Level, victim.Score = 10, 5000
+		item := &GameItem{ID: w.itemID, X: player.X, Y: player.Y, Type: ItemTypeBlueDiamond, XP: 30, Coins: 30}
+		w.items[item.ID] = item
+		w.itemID++
+		score, coins := player.Score, player.Coins
+		w.mu.Unlock()
+
+		w.update()
+
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		itemXP, itemCoins = player.Score-score, player.Coins-coins
+		score, coins = player.Score, player.Coins
+		w.mechanics.ApplyDamage(victim, victim.Health+1000, player, KillCauseBullet, time.Now())
+		if victim.State != StateDead {
+			t.Fatal("victim survived a lethal hit")
+		}
+		return itemXP, itemCoins, player.Score - score, player.Coins - coins
+	}
+
+	itemXP, itemCoins, killXP, killCoins := rewards(1)
+	if itemXP == 0 || itemCoins == 0 || killXP == 0 || killCoins == 0 {
+		t.Fatalf("normal rewards: item %d XP %d coins, kill %d XP %d coins; want all positive", itemXP, itemCoins, killXP, killCoins)
+	}
+	bonusItemXP, bonusItemCoins, bonusKillXP, bonusKillCoins := rewards(2)
+	if bonusItemXP != 2*itemXP || bonusItemCoins != 2*itemCoins {
+		t.Errorf("2x item reward = %d XP %d coins, want %d XP %d coins", bonusItemXP, bonusItemCoins, 2*itemXP, 2*itemCoins)
+	}
+	if bonusKillXP != 2*killXP || bonusKillCoins != 2*killCoins {
+		t.Errorf("2x kill reward = %d XP %d coins, want %d XP %d coins", bonusKillXP, bonusKillCoins, 2*killXP, 2*killCoins)
+	}
+}
+
+func TestRearHitsDoMoreDamageThanHitsOnTheBow(t *testing.T) {
+	w := newTestWorld(t, nil)
+	shooter := addTestClient(t, w, 500, 500)
+	bow := addTestClient(t, w, 1000, 1000).Player
+	stern := addTestClient(t, w, 2000, 1000).Player
+	rammed := addTestClient(t, w, 3000, 1000).Player
+
+	const damage, speed = 20.0, 10.0
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	rammed.ShipConfig.FrontUpgrade = NewRamUpgrade()
+	rammed.updateModifiers()
+	for _, shot := range []struct {
+		target *Player
+		velX   float64
+	}{{bow, -speed}, {stern, speed}, {rammed, -speed}} {
+		shot.target.Angle = 0 // Bow toward +X
+		w.bullets[w.bulletID] = &Bullet{
+			ID: w.bulletID, X: shot.target.X, Y: shot.target.Y, VelX: shot.velX, OwnerID: shooter.ID,
+			CreatedAt: time.Now(), Radius: BulletSize, Damage: damage,
+		}
+		w.bulletID++
+	}
+	w.updateBullets()
+
+	taken := func(player *Player) float64 { return player.MaxHealth - player.Health }
+	if got, want := taken(bow), damage*ArmorFrontMultiplier; math.Abs(got-want) > 1e-9 {
+		t.Errorf("hit on the bow did %v, want %v", got, want)
+	}
+	if got, want := taken(stern), damage*ArmorRearMultiplier; math.Abs(got-want) > 1e-9 {
+		t.Errorf("hit on the stern did %v, want %v", got, want)
+	}
+	if taken(rammed) >= taken(bow) {
+		t.Errorf("ram-armored bow took %v, want less than a bare bow's %v", taken(rammed), taken(bow))
+	}
+}
@@ -0,0 +1,254 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// WorldConfig holds operator-tunable settings for a world
+type WorldConfig struct {
+	RecordPath string // File to record snapshots to (empty = recording off)
+	Seed       int64  // Seed for the world's random source (0 = seed from the clock)
+	AdminToken string // Token that unlocks admin commands on connect (empty = admin disabled)
+	StaticDir  string // Directory the frontend is served from
+
+	// Simulation steps per second; speeds are per second, so this trades CPU
+	// for smoother movement without changing how fast anything goes
+	TickRate int
+
+	// Passive income paid to living players over time
+	PassiveIncomePerSecond float64 // Coins earned per second alive (0 = off)
+	PassiveIncomeCap       int     // Maximum passive coins earned per life
+
+	// Respawn cost and protection
+	RespawnXPRetention   float64       // Fraction of XP and score kept on respawn
+	RespawnCoinRetention float64       // Fraction of coins kept on respawn
+	SpawnProtection      time.Duration // How long a respawned player is invulnerable (0 = off)
+
+	// Distance a client's predicted position may drift before it is corrected (0 = never)
+	PredictionCorrectionThreshold float64
+
+	Bots BotConfig // Bot population and difficulty
+
+	// How long loot from a sunk ship is reserved for the killer (0 = free-for-all)
+	LootOwnershipWindow time.Duration
+
+	// Fraction of a sunk ship's coins spilled as loot at the wreck for anyone
+	// to collect (0 = off)
+	CoinDropFraction float64
+
+	// Highest level a player can reach; further experience earns prestige
+	MaxLevel int
+
+	// Most coins and score a player can hold; rewards past these are dropped
+	MaxCoins int
+	MaxScore int
+
+	// Soft world border: ships within BorderMargin of the edge take damage
+	// and are pushed back toward the middle
+	BorderMargin       float64 // Width of the hazardous strip along each edge
+	BorderDamagePerSec float64 // Damage per second at the very edge (0 = off)
+	BorderPushForce    float64 // Push-back velocity in units per second at the very edge (0 = none)
+
+	// Bullet flood protection (0 = no cap)
+	MaxBulletsPerPlayer int // Live bullets one ship may have in the world
+	MaxBullets          int // Live bullets across the whole world
+
+	// Whether teammates that bump into each other deal collision and ram damage
+	// (they are always pushed apart)
+	FriendlyCollisionDamage bool
+
+	// Bonus event multipliers for item and kill rewards (1 = normal)
+	XPMultiplier   float64
+	CoinMultiplier float64
+
+	// Dash ability: a forward burst that briefly exceeds max speed (0 impulse = disabled)
+	DashImpulse  float64       // Extra forward velocity (units per second) added by a dash
+	DashCooldown time.Duration // Minimum time between dashes
+
+	// Give players a numbered name, like "Pirate (2)", when theirs is already in use
+	UniqueNames bool
+
+	// Chance each extra bullet of a spread shot (e.g. scatter) hits, used by
+	// the DPS shown to players (1 = count every bullet)
+	SpreadHitFactor float64
+
+	// Turn authority curve: turn speed scales from MinTurnFactor at a standstill
+	// up to full at base max speed, following (speed/max)^TurnSpeedExponent
+	MinTurnFactor     float64 // Fraction of turn speed kept at zero speed (0 = can't turn while stopped)
+	TurnSpeedExponent float64 // Shape of the curve (1 = linear)
+
+	// Velocity kick (units per second) per unit of cannon weight when firing
+	// (0 = recoil is cosmetic only)
+	RecoilStrength float64
+
+	// Wind and current zones placed at random when the world is created
+	CurrentCount    int     // Number of current zones (0 = calm seas)
+	CurrentStrength float64 // Drift velocity in units per second each current adds to a ship
+
+	// Inactivity limits (0 = never disconnect)
+	IdleTimeout time.Duration // Disconnect clients that send no messages at all for this long
+	AFKTimeout  time.Duration // Disconnect living players who don't steer, aim, fire or act for this long
+
+	// Cosmetic unlocks by account token, shared by every room (nil = accounts off)
+	Accounts AccountStore
+	// Loadout presets by session token, shared by every room (nil = not kept)
+	Sessions *SessionLoadouts
+	// Reject hull colors and flags a player's account hasn't unlocked
+	RankedCosmetics bool
+
+	// How side cannons pick their angle (SideCannonsBroadside by default) and,
+	// for the other modes, the half-angle of their firing arc in radians
+	SideCannonMode SideCannonMode
+	SideCannonArc  float64
+
+	// Delay between consecutive cannons of a broadside in ripple fire; each
+	// step fires one cannon per side, stern to bow (0 = all fire at once)
+	SideCannonRipple time.Duration
+
+	// Random points tried when picking a spawn; the one farthest from any enemy
+	// wins (1 = plain random spawn)
+	SpawnCandidates int
+
+	// Hide other ships beyond each player's vision range (see StatUpgradeVision);
+	// bullets and hits are always limited to vision range
+	FogOfWar bool
+
+	// Log each ship's combat stats (shots, hits, damage dealt and taken) when it sinks
+	CombatLog bool
+
+	// Round rules (both 0 = one endless round). The round ends when a ship
+	// reaches RoundKillTarget kills, or after RoundTimeLimit with the top scorer winning.
+	RoundKillTarget int
+	RoundTimeLimit  time.Duration
+
+	// Connections the world accepts (0 = MaxPlayers)
+	MaxClients int
+
+	// Don't spawn food or special items (used by duel arenas)
+	DisableItems bool
+
+	// Rule set and, for battle royale, the shrinking zone schedule
+	Mode GameMode
+	Zone ZoneConfig
+}
+
+// SideCannonMode selects how side cannons aim
+type SideCannonMode string
+
+const (
+	SideCannonsBroadside SideCannonMode = "broadside" // Always fire straight out from the hull
+	SideCannonsTarget    SideCannonMode = "target"    // Hold fire until an enemy is within the firing arc
+	SideCannonsAim       SideCannonMode = "aim"       // Swivel toward the mouse within the firing arc
+)
+
+// scaleRewards applies the bonus event multipliers to an XP and coin reward
+func (config WorldConfig) scaleRewards(xp, coins int) (int, int) {
+	return int(float64(xp) * config.XPMultiplier), int(float64(coins) * config.CoinMultiplier)
+}
+
+// tickSeconds returns the length of one simulation step in seconds
+func (config WorldConfig) tickSeconds() float64 {
+	if config.TickRate <= 0 {
+		return 1.0 / DefaultTickRate
+	}
+	return 1.0 / float64(config.TickRate)
+}
+
+// maxDroppedFrames returns how many snapshots in a row a client may miss before
+// SlowClientTimeout runs out; one snapshot goes out per tick
+func (config WorldConfig) maxDroppedFrames() int32 {
+	return int32(max(1, math.Round(SlowClientTimeout.Seconds()/config.tickSeconds())))
+}
+
+// turnFactor scales turn speed by how fast a ship is moving
+func (config WorldConfig) turnFactor(speed float64) float64 {
+	minFactor := math.Max(0, math.Min(config.MinTurnFactor, 1))
+	return minFactor + (1-minFactor)*math.Pow(speed/BaseShipMaxSpeed, config.TurnSpeedExponent)
+}
+
+// Validate reports the first setting that is out of range
+func (config WorldConfig) Validate() error {
+	switch config.Mode {
+	case ModeFreeForAll, ModeBattleRoyale, ModeDuel, ModeTeams:
+	default:
+		return fmt.Errorf("unknown game mode %q", config.Mode)
+	}
+	switch config.SideCannonMode {
+	case SideCannonsBroadside, SideCannonsTarget, SideCannonsAim:
+	default:
+		return fmt.Errorf("unknown side cannon mode %q", config.SideCannonMode)
+	}
+	if config.MaxLevel < 1 {
+		return fmt.Errorf("max level %d must be at least 1", config.MaxLevel)
+	}
+	if config.RespawnXPRetention < 0 || config.RespawnXPRetention > 1 {
+		return fmt.Errorf("respawn XP retention %v must be between 0 and 1", config.RespawnXPRetention)
+	}
+	if config.RespawnCoinRetention < 0 || config.RespawnCoinRetention > 1 {
+		return fmt.Errorf("respawn coin retention %v must be between 0 and 1", config.RespawnCoinRetention)
+	}
+	return nil
+}
+
+// DefaultWorldConfig returns the settings used when none are provided
+func DefaultWorldConfig() WorldConfig {
+	return WorldConfig{
+		RecordPath:             "",
+		TickRate:               DefaultTickRate,
+		StaticDir:              "./static",
+		PassiveIncomePerSecond: 0,
+		PassiveIncomeCap:       500,
+		RespawnXPRetention:     0.5,
+		RespawnCoinRetention:   0.5,
+		SpawnProtection:        3 * time.Second,
+
+		PredictionCorrectionThreshold: 25,
+
+		Bots: NewBotConfig(BotDifficultyNormal),
+
+		LootOwnershipWindow: 5 * time.Second,
+		CoinDropFraction:    0.25,
+		MaxLevel:            DefaultMaxLevel,
+		MaxCoins:            DefaultMaxCoins,
+		MaxScore:            DefaultMaxScore,
+		BorderMargin:        150,
+		BorderDamagePerSec:  0,
+		BorderPushForce:     0,
+
+		MaxBulletsPerPlayer: 60,
+		MaxBullets:          2000,
+
+		FriendlyCollisionDamage: false,
+
+		XPMultiplier:   1,
+		CoinMultiplier: 1,
+
+		DashImpulse:  300,
+		DashCooldown: 5 * time.Second,
+
+		UniqueNames: true,
+
+		SpreadHitFactor: 0.6,
+
+		MinTurnFactor:     0.3,
+		TurnSpeedExponent: 1,
+
+		RecoilStrength: 12,
+
+		CurrentCount:    0,
+		CurrentStrength: 45,
+
+		SpawnCandidates: 12,
+
+		SideCannonMode: SideCannonsBroadside,
+		SideCannonArc:  math.Pi / 6,
+
+		IdleTimeout: 5 * time.Minute,
+		AFKTimeout:  3 * time.Minute,
+
+		Mode: ModeFreeForAll,
+		Zone: DefaultZoneConfig(),
+	}
+}
@@ -0,0 +1,45 @@
+package game
+
+import "testing"
+
+func TestValidateRejectsUnknownModesAndLevelCap(t *testing.T) {
+	config := DefaultWorldConfig()
+	config.Mode = "capture"
+	if config.Validate() == nil {
+		t.Error("unknown game mode accepted")
+	}
+
+	config = DefaultWorldConfig()
+	config.SideCannonMode = "sideways"
+	if config.Validate() == nil {
+		t.Error("unknown side cannon mode accepted")
+	}
+
+	for _, level := range []int{0, -3} {
+		config = DefaultWorldConfig()
+		config.MaxLevel = level
+		if config.Validate() == nil {
+			t.Errorf("max level %d accepted", level)
+		}
+	}
+}
+
+func TestValidateRejectsRetentionOutsideZeroToOne(t *testing.T) {
+	if err := DefaultWorldConfig().Validate(); err != nil {
+		t.Fatalf("default config invalid: %v", err)
+	}
+
+	for _, retention := range []float64{-0.1, 1.5} {
+		config := DefaultWorldConfig()
+		config.RespawnXPRetention = retention
+		if config.Validate() == nil {
+			t.Errorf("XP retention %v accepted", retention)
+		}
+
+		config = DefaultWorldConfig()
+		config.RespawnCoinRetention = retention
+		if config.Validate() == nil {
+			t.Errorf("coin retention %v accepted", retention)
+		}
+	}
+}
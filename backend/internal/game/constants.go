@@ -1,34 +1,117 @@
 package game
 
+import (
+	"math"
+	"time"
+)
+
 // Game world constants
 const (
-	WorldWidth         = 5000.0
-	WorldHeight        = 5000.0
-	TickRate           = 30 // Server updates per second (reduced for performance)
-	PlayerSize         = 50.0
-	MaxPlayers         = 32
-	BulletVisibleRange = 1500.0 // Maximum distance to send bullets to clients
+	WorldWidth      = 5000.0
+	WorldHeight     = 5000.0
+	DefaultTickRate = 30 // Server updates per second unless WorldConfig.TickRate says otherwise
+	PlayerSize      = 50.0
+	MaxPlayers      = 32
+	BaseVisionRange = 1500.0 // How far a ship sees before crow's nest upgrades; bullets and hits beyond are not sent
+
+	MaxDamageEventsPerClient = 32  // Damage numbers sent to one client per snapshot
+	MinDamageEventAmount     = 1.0 // Smaller hits (per-tick burn, zone and border damage) aren't reported
 )
 
-// Ship physics constants
+// SlowClientTimeout is how long a client may keep its send buffer full, missing
+// every snapshot, before it is disconnected
+const SlowClientTimeout = 3 * time.Second
+
+// IdleSweepInterval is how often clients are checked against the idle and AFK timeouts
+const IdleSweepInterval = 5 * time.Second
+
+// ReloadProgressStep is the granularity of the reload progress sent to clients
+const ReloadProgressStep = 0.05
+
+// DebugInfoDecimals is the number of decimals DPS and range are rounded to in DebugInfo
+// (coarser values change less often, so fewer debug deltas are sent)
+const DebugInfoDecimals = 1
+
+// Item magnet constants (the magnet stat is off at level 0)
 const (
-	BaseShipTurnSpeed = 0.08 // Turning speed in radians per frame (doubled for 30 TPS)
-	ShipDeceleration  = 0.84 // Drag/friction factor (adjusted for 30 TPS)
-	BaseShipMaxSpeed  = 4    // Maximum speed (doubled for 30 TPS)
+	MagnetBaseRadius     = 80.0 // Pull radius at level 1, before the per-level bonus
+	MagnetRadiusPerLevel = 15.0
+	MagnetBasePull       = 60.0 // Units per second an item moves at level 1, before the per-level bonus
+	MagnetPullPerLevel   = 12.0
+)
+
+// VisionRangePerLevel is the extra sight each crow's nest (vision) level adds
+const VisionRangePerLevel = 150.0
+
+// UpgradeCooldown is the minimum time between module upgrades from one client
+const UpgradeCooldown = 500 * time.Millisecond
+
+// MaxUpgradeChoiceLength caps the module name a client may send; longer
+// requests are rejected without a lookup and truncated in logs
+const MaxUpgradeChoiceLength = 64
+
+// SinkDuration is how long a sunk ship stays in other players' snapshots as a wreck
+const SinkDuration = 2 * time.Second
+
+// Snapshot level-of-detail constants
+const (
+	LODFullDetailRange = 1200.0 // Other players farther than this are sent with reduced detail
+	LODPositionStep    = 4.0    // Reduced-detail positions are rounded to this grid
+)
+
+// Off-screen indicator constants
+const (
+	OffscreenIndicatorsEnabled = true   // Allow clients to opt into off-screen enemy bearings
+	OffscreenIndicatorMinRange = 1000.0 // Enemies closer than this are assumed on screen
+	OffscreenIndicatorMaxRange = 3000.0 // Enemies farther than this are not reported
+)
+
+// Ship physics constants (speeds are per second, so they hold at any tick rate)
+const (
+	BaseShipTurnSpeed  = 2.4    // Turning speed in radians per second
+	ShipDeceleration   = 0.84   // Share of max speed a ship cruises at after drag
+	ShipDriftRetention = 0.0054 // Share of recoil and dash drift left after one second
+	BaseShipMaxSpeed   = 120    // Maximum speed in units per second
+
+	ReverseSpeedFactor = 0.4 // Reverse speed as a fraction of max forward speed
+)
+
+// Movement validation constants
+const (
+	MaxMovementSpeedFactor = 2.0 // Allowed per-tick displacement as a multiple of the farthest the ship can sail in a tick
 )
 
 const (
 	HealthIncrease = 30
 )
 
+// Stat respec constants
+const (
+	RespecRefundRate = 0.8              // Fraction of spent coins returned on respec
+	RespecCooldown   = 60 * time.Second // Minimum time between respecs
+)
+
+// Turret rotation speeds in radians per second
+const (
+	BasicTurretRotationSpeed      = 2 * math.Pi
+	MachineGunTurretRotationSpeed = 2.5 * math.Pi
+	BigTurretRotationSpeed        = math.Pi
+)
+
 // Cannon and bullet constants
 const (
-	BulletSpeed    = 12  // Bullet travel speed (doubled for 30 TPS)
+	BulletSpeed    = 360 // Bullet travel speed in units per second
 	BulletLifetime = 2   // Seconds before bullet disappears
 	BulletSize     = 8.0 // Bullet radius
 	BulletDamage   = 6   // Damage per bullet hit (unchanged)
+
+	BulletHullClearance = 2.0 // Gap between a fresh bullet's edge and its owner's hull
 )
 
+// ProtocolVersion is the message format clients must speak; bump it whenever
+// a message or snapshot field changes in a way older clients can't read
+const ProtocolVersion = 1
+
 // Message types for client-server communication
 const (
 	MsgTypeSnapshot        = "snapshot"
@@ -36,12 +119,136 @@ const (
 	MsgTypeWelcome         = "welcome"
 	MsgTypeGameEvent       = "gameEvent"
 	MsgTypeResetShipConfig = "resetShipConfig"
+	MsgTypeMapInfo         = "mapInfo"
+	MsgTypeError           = "error"
+	MsgTypeEmote           = "emote"
+	MsgTypeChat            = "chat"
+	MsgTypeCorrection      = "correction"
+	MsgTypeAdminResult     = "adminResult"
+	MsgTypeBotDebug        = "botDebug"
+	MsgTypeDuel            = "duel"
+	MsgTypeUnlocks         = "unlocks"
+	MsgTypeRoundEnd        = "roundEnd"
+)
+
+// Burning (incendiary) constants
+const (
+	BurnDuration        = 3 * time.Second // Burn time added per incendiary hit
+	BurnDamagePerSecond = 4.0             // Burn damage per second (does not stack)
+)
+
+// Emote constants
+const (
+	EmoteRange    = 2000.0          // Clients within this distance of the sender see the emote
+	EmoteCooldown = 2 * time.Second // Minimum time between emotes from one player
+)
+
+// allowedEmotes is the fixed set of signals players can send
+var allowedEmotes = map[string]bool{
+	"help":    true,
+	"attack":  true,
+	"retreat": true,
+	"thanks":  true,
+	"gg":      true,
+}
+
+// DefaultMaxLevel is the level cap used when none is configured
+const DefaultMaxLevel = 45
+
+// Balance caps used when none are configured (see WorldConfig.MaxCoins and MaxScore)
+const (
+	DefaultMaxCoins = 1_000_000
+	DefaultMaxScore = 100_000_000
+)
+
+// MaxExperience is the most experience a player can bank at once, which keeps
+// the level formula far from integer overflow
+const MaxExperience = 1 << 40
+
+// Kill reward bounds; XP is half the victim's experience, coins half their score,
+// both capped relative to the victim's level so veterans aren't jackpots
+const (
+	MinKillXPReward   = 100
+	MinKillCoinReward = 200
+	KillXPLevelSpans  = 3  // XP cap, in multiples of the experience the victim's level spans
+	KillCoinsPerLevel = 50 // Coin cap per victim level (never below MinKillCoinReward)
+)
+
+// KillFeedSize is how many recent kills are kept for late joiners
+const KillFeedSize = 10
+
+// Chat constants
+const (
+	MaxChatMessageLength = 120             // Maximum characters in one chat message
+	ChatRateLimit        = 3               // Messages allowed per rate window
+	ChatRateWindow       = 5 * time.Second // Window the rate limit applies to
+)
+
+// visualFireStagger spaces the recoil timestamps of cannons that fire in the
+// same volley so clients can animate a ripple instead of one flash (0 = together)
+var visualFireStagger = map[WeaponType]time.Duration{
+	WeaponTypeCannon:     60 * time.Millisecond,
+	WeaponTypeIncendiary: 60 * time.Millisecond,
+	WeaponTypeRicochet:   60 * time.Millisecond,
+	WeaponTypeScatter:    30 * time.Millisecond,
+	WeaponTypeTurret:     40 * time.Millisecond,
+	WeaponTypeFlakTurret: 40 * time.Millisecond,
+}
+
+// Tractor beam constants
+const (
+	TractorBeamRange      = 600.0           // Maximum distance to lock onto a target
+	TractorBeamBreakRange = 800.0           // Targets farther than this break free
+	TractorBeamArc        = math.Pi / 3     // Full width of the forward lock-on cone
+	TractorBeamPullSpeed  = 90.0            // Distance the target is pulled per second
+	TractorBeamDuration   = 2 * time.Second // How long a beam holds its target
+	TractorBeamCooldown   = 6 * time.Second // Minimum time between activations
 )
 
 // Combat constants
 const (
 	BaseCollisionDamage = 5.0   // Base damage dealt per collision
 	CollisionCooldown   = 0.2 // Seconds between collision damage ticks
+
+	ManualFireCooldown = 100 * time.Millisecond // Minimum time between accepted manual fire inputs
+	AimedAutofireArc   = math.Pi / 12           // Half-angle an enemy must be within for aimed autofire to shoot
+)
+
+// Directional armor: bullet damage depends on which facet of the hull it
+// strikes, judged by the direction the bullet came from
+const (
+	ArmorFrontArc        = math.Pi / 4 // Half-angle around the bow that counts as a frontal hit
+	ArmorRearArc         = math.Pi / 4 // Half-angle around the stern that counts as a rear hit
+	ArmorFrontMultiplier = 0.75        // Damage taken on the reinforced bow
+	ArmorRearMultiplier  = 1.25        // Damage taken on the stern
+	ArmorMinMultiplier   = 0.25        // Frontal damage never drops below this, however much armor is fitted
+)
+
+// Anti-camping constants (discourage parking in place to farm bots)
+const (
+	AntiCampEnabled          = true
+	CampWindow               = 10 * time.Second // Window over which net displacement is measured
+	CampDisplacementMin      = 400.0            // Net displacement below this counts as stationary
+	CampRewardDecayPerSecond = 0.02             // Reward multiplier lost per second of camping
+	CampMinRewardMultiplier  = 0.25             // Rewards never drop below this fraction
+)
+
+// Assist constants (players who helped sink a ship but didn't land the kill)
+const (
+	AssistWindow            = 10 * time.Second // Damage older than this earns no assist
+	AssistMinDamageFraction = 0.25             // Share of the victim's max health an assister must have dealt
+	AssistRewardFraction    = 0.5              // Share of a full kill reward each assister receives
+)
+
+// LobbyCombatLock is how long after being hit by another ship a player can't
+// leave for the lobby
+const LobbyCombatLock = 5 * time.Second
+
+// Bounty constants (the top scorer is marked for everyone to hunt)
+const (
+	BountyUpdateInterval   = 2 * time.Second // How often the bounty target is recomputed
+	BountyMinScore         = 1000            // Nobody is marked until someone has at least this score
+	BountyRewardMultiplier = 1.5             // Kill rewards for sinking the bounty target
 )
 
 // Item constants
@@ -50,12 +257,50 @@ const (
 	MaxItems       = 300  // Maximum number of items in the world
 )
 
+// Player-scaled food spawning: the target item count and refill rate grow with
+// the number of connected (non-bot) players, up to MaxItems
+const (
+	BaseItemCount         = 60                     // Items kept in the world for the first player
+	ItemsPerPlayer        = 20                     // Extra items per additional player
+	BaseFoodSpawnInterval = 2 * time.Second        // Refill interval with a single player
+	MinFoodSpawnInterval  = 500 * time.Millisecond // Fastest refill on a busy server
+	PlayersPerSpawnStep   = 8                      // Each this many players shortens the interval by another base step
+)
+
+// Special item spawning constants
+const (
+	MaxSpecialItems          = 25 // Maximum number of special items in the world
+	SpecialItemsPerSpawn     = 5  // Special items spawned per special tick
+	SpecialItemMinPlayers    = 2  // Special items only spawn with more players than this
+	SpecialItemSpawnInterval = 10 // Seconds between special item spawns
+)
+
 // Item type constants
 const (
 	ItemTypeGrayCircle   = "gray_circle"
 	ItemTypeYellowCircle = "yellow_circle"
 	ItemTypeOrangeCircle = "orange_circle"
 	ItemTypeBlueDiamond  = "blue_diamond"
+	ItemTypeGoldStar     = "gold_star" // Special-spawn only
+	ItemTypeShield       = "shield"    // Power-up: damage reduction
+	ItemTypeSpeedBoost   = "speed_boost"
+	ItemTypeRapidReload  = "rapid_reload"
+	ItemTypeCoinDrop     = "coin_drop" // Coins spilled from a sunk ship
+)
+
+// Coin drop constants (see WorldConfig.CoinDropFraction)
+const (
+	CoinDropMaxItems = 8    // Most items one wreck spills
+	CoinDropMinValue = 10   // Smallest coin item; small purses spill fewer items
+	CoinDropScatter  = 60.0 // Items land up to this far from the wreck
+)
+
+// Power-up buff constants
+const (
+	BuffDuration           = 8 * time.Second // How long a power-up lasts
+	ShieldDamageMultiplier = 0.5             // Fraction of damage taken while shielded
+	SpeedBoostMultiplier   = 1.4             // Movement speed multiplier while boosted
+	RapidReloadMultiplier  = 0.6             // Reload time multiplier while boosted
 )
 
 // Player states
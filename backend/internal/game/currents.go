@@ -0,0 +1,69 @@
+package game
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Current zone size limits
+const (
+	MinCurrentSize = 400.0
+	MaxCurrentSize = 900.0
+)
+
+// Current is a rectangular region of wind or water current that pushes every
+// ship inside it along at a constant velocity
+type Current struct {
+	X      float64 `msgpack:"x"` // Top-left corner
+	Y      float64 `msgpack:"y"`
+	Width  float64 `msgpack:"width"`
+	Height float64 `msgpack:"height"`
+	VelX   float64 `msgpack:"velX"` // Drift velocity in units per second
+	VelY   float64 `msgpack:"velY"`
+}
+
+// contains reports whether a position lies inside the current
+func (current Current) contains(x, y float64) bool {
+	return x >= current.X && x <= current.X+current.Width && y >= current.Y && y <= current.Y+current.Height
+}
+
+// generateCurrents places count currents of the given strength at random
+// spots, each flowing in a random direction
+func generateCurrents(rng *rand.Rand, count int, strength float64) []Current {
+	currents := make([]Current, 0, count)
+	for range count {
+		width := MinCurrentSize + rng.Float64()*(MaxCurrentSize-MinCurrentSize)
+		height := MinCurrentSize + rng.Float64()*(MaxCurrentSize-MinCurrentSize)
+		direction := rng.Float64() * 2 * math.Pi
+		currents = append(currents, Current{
+			X:      rng.Float64() * (WorldWidth - width),
+			Y:      rng.Float64() * (WorldHeight - height),
+			Width:  width,
+			Height: height,
+			VelX:   math.Cos(direction) * strength,
+			VelY:   math.Sin(direction) * strength,
+		})
+	}
+	return currents
+}
+
+// currentAt returns the total drift velocity at a position; overlapping
+// currents add up
+func (w *World) currentAt(x, y float64) (velX, velY float64) {
+	for _, current := range w.currents {
+		if current.contains(x, y) {
+			velX += current.VelX
+			velY += current.VelY
+		}
+	}
+	return velX, velY
+}
+
+// compensateForCurrent returns the heading a ship moving at speed must steer
+// so that, once the current's drift is added, it travels along angle
+func compensateForCurrent(angle, speed, currentX, currentY float64) float64 {
+	if currentX == 0 && currentY == 0 {
+		return angle
+	}
+	return math.Atan2(math.Sin(angle)*speed-currentY, math.Cos(angle)*speed-currentX)
+}
@@ -0,0 +1,48 @@
+package game
+
+import (
+	"math"
+	"testing"
+)
+
+func TestShipInACurrentDriftsWithNoInput(t *testing.T) {
+	w := newTestWorld(t, nil)
+	w.mu.Lock()
+	w.currents = []Current{{X: 500, Y: 500, Width: 1000, Height: 1000, VelX: 1.5, VelY: -0.5}}
+	w.mu.Unlock()
+
+	drifting := addTestClient(t, w, 1000, 1000)
+	calm := addTestClient(t, w, 3000, 3000)
+	stop := make(chan struct{})
+	defer close(stop)
+	go drainClient(calm, stop)
+
+	// The joining client is told where the currents are
+	var mapInfo MapInfoMsg
+	for _, data := range queuedMessages(drifting) {
+		if decodeTestMsg(data, &mapInfo) && mapInfo.Type == MsgTypeMapInfo {
+			break
+		}
+	}
+	if len(mapInfo.Currents) != 1 || mapInfo.Currents[0] != w.currents[0] {
+		t.Errorf("map info currents = %+v, want %+v", mapInfo.Currents, w.currents)
+	}
+	go drainClient(drifting, stop)
+
+	w.mu.Lock()
+	for _, player := range []*Player{drifting.Player, calm.Player} {
+		player.Modifiers.MoveSpeedMultiplier = 0 // No throttle, so only the current moves the ship
+	}
+	w.mu.Unlock()
+
+	w.update()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	dt := w.config.tickSeconds()
+	if dx, dy := drifting.Player.X-1000, drifting.Player.Y-1000; math.Abs(dx-1.5*dt) > 1e-9 || math.Abs(dy+0.5*dt) > 1e-9 {
+		t.Errorf("ship in the current moved (%v, %v), want (%v, %v)", dx, dy, 1.5*dt, -0.5*dt)
+	}
+	if calm.Player.X != 3000 || calm.Player.Y != 3000 {
+		t.Errorf("ship outside the current moved to (%v, %v)", calm.Player.X, calm.Player.Y)
+	}
+}
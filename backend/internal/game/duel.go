@@ -0,0 +1,186 @@
+package game
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+)
+
+// Duel constants
+const (
+	DuelRoomPrefix     = "duel-"          // Rooms with this prefix are private 1v1 arenas
+	DuelPlayers        = 2                // Clients a duel arena accepts
+	DuelInviteTimeout  = 30 * time.Second // How long an invite can be accepted
+	DuelInviteCooldown = 5 * time.Second  // Minimum time between invites from one player
+	DuelArenaRadius    = 700.0            // Radius of the arena around the map center
+	DuelArenaDamage    = 15.0             // Damage per second to a ship outside the arena
+)
+
+// duelInvite is a pending challenge, keyed by the invited player's ID
+type duelInvite struct {
+	from    uint32
+	expires time.Time
+}
+
+// DuelTicketIssuer reserves a duel room and returns one single-use ticket per
+// duelist, which the client presents when it reconnects into the room
+type DuelTicketIssuer func(room string, players int) []string
+
+// duelState tracks the outcome of a duel arena (guarded by w.mu)
+type duelState struct {
+	joined int    // Clients that have entered the arena so far
+	over   bool   // A winner has been declared
+	winner uint32 // Player ID of the winner once over
+}
+
+// IsDuelRoom reports whether a room name belongs to a duel arena
+func IsDuelRoom(name string) bool {
+	return strings.HasPrefix(name, DuelRoomPrefix)
+}
+
+// DuelConfig derives a duel arena's settings from the server's: two players,
+// no bots, items or currents, and a fixed zone that keeps the fight in the middle
+func DuelConfig(base WorldConfig) WorldConfig {
+	config := base
+	config.Mode = ModeDuel
+	config.MaxClients = DuelPlayers
+	config.DisableItems = true
+	config.Bots.Count = 0
+	config.CurrentCount = 0
+	config.Zone = ZoneConfig{
+		Center:        Position{X: WorldWidth / 2, Y: WorldHeight / 2},
+		InitialRadius: DuelArenaRadius,
+		Phases:        []ZonePhase{{Radius: DuelArenaRadius, DamagePerSec: DuelArenaDamage}},
+	}
+	return config
+}
+
+// handleDuelInput processes duel invites and replies. Accepting sends both
+// players the name of a fresh duel room to reconnect to.
+func (w *World) handleDuelInput(client *Client, input InputMsg, now time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.config.Mode == ModeDuel {
+		return
+	}
+
+	switch input.Type {
+	case "duelInvite":
+		target, exists := w.clients[input.DuelTarget]
+		if !exists || target.ID == client.ID {
+			return
+		}
+		// One invite per sender every DuelInviteCooldown, and a target holds
+		// at most one pending invite until it is answered or expires
+		if now.Sub(client.lastDuelInvite) < DuelInviteCooldown {
+			slog.Debug("Duel invite rate limited", "player", client.ID)
+			return
+		}
+		if pending, exists := w.duelInvites[target.ID]; exists && now.Before(pending.expires) {
+			slog.Debug("Duel target already has an invite pending", "player", client.ID, "target", target.ID)
+			return
+		}
+		client.lastDuelInvite = now
+		w.duelInvites[target.ID] = duelInvite{from: client.ID, expires: now.Add(DuelInviteTimeout)}
+		target.sendDuel(DuelMsg{Event: "invite", PlayerID: client.ID, Name: client.Player.Name})
+
+	case "duelAccept", "duelDecline":
+		invite, exists := w.duelInvites[client.ID]
+		if !exists || invite.from != input.DuelTarget || now.After(invite.expires) {
+			return
+		}
+		delete(w.duelInvites, client.ID)
+
+		challenger, exists := w.clients[invite.from]
+		if !exists {
+			return
+		}
+		if input.Type == "duelDecline" {
+			challenger.sendDuel(DuelMsg{Event: "declined", PlayerID: client.ID, Name: client.Player.Name})
+			return
+		}
+
+		room := fmt.Sprintf("%s%08x", DuelRoomPrefix, w.rng.Uint32())
+		tickets := make([]string, DuelPlayers)
+		if w.duelTickets != nil {
+			tickets = w.duelTickets(room, DuelPlayers)
+		}
+		challenger.sendDuel(DuelMsg{Event: "start", Room: room, Ticket: tickets[0], PlayerID: client.ID, Name: client.Player.Name})
+		client.sendDuel(DuelMsg{Event: "start", Room: room, Ticket: tickets[1], PlayerID: challenger.ID, Name: challenger.Player.Name})
+		slog.Info("Duel starting", "room", room, "player", client.ID, "challenger", challenger.ID)
+	}
+}
+
+// SetDuelTicketIssuer installs the issuer used to reserve duel rooms when an
+// invite is accepted
+func (w *World) SetDuelTicketIssuer(issuer DuelTicketIssuer) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.duelTickets = issuer
+}
+
+// placeInDuelArena moves a freshly spawned duelist to its side of the arena,
+// facing the middle; caller must hold w.mu
+func (w *World) placeInDuelArena(player *Player) {
+	if w.duel == nil {
+		return
+	}
+
+	side := -1.0
+	if player.ID%2 == 0 {
+		side = 1.0
+	}
+	center := w.config.Zone.Center
+	player.X = center.X + side*DuelArenaRadius/2
+	player.Y = center.Y
+	player.Angle = 0
+	if side > 0 {
+		player.Angle = math.Pi
+	}
+}
+
+// duelOpen reports whether players may still enter the fight; caller must hold w.mu
+func (w *World) duelOpen() bool {
+	return w.duel == nil || !w.duel.over
+}
+
+// checkDuelOver declares the other duelist the winner once loser is sunk or
+// leaves the arena; caller must hold w.mu
+func (w *World) checkDuelOver(loser *Player) {
+	if w.duel == nil || w.duel.over || w.duel.joined < DuelPlayers {
+		return
+	}
+
+	var winner *Player
+	for _, player := range w.players {
+		if player.ID != loser.ID {
+			winner = player
+		}
+	}
+	if winner == nil {
+		return
+	}
+
+	w.duel.over = true
+	w.duel.winner = winner.ID
+	slog.Info("Duel over", "winner", winner.ID, "name", winner.Name, "loser", loser.ID)
+
+	msg := DuelMsg{Event: "end", WinnerID: winner.ID, WinnerName: winner.Name}
+	for _, client := range w.clients {
+		client.sendDuel(msg)
+	}
+}
+
+// DuelWinner returns the duel winner's player ID, if the duel has ended
+func (w *World) DuelWinner() (uint32, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.duel == nil || !w.duel.over {
+		return 0, false
+	}
+	return w.duel.winner, true
+}
@@ -0,0 +1,52 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func duelInvitesFor(t *testing.T, client *Client) int {
+	t.Helper()
+	count := 0
+	for _, data := range queuedMessages(client) {
+		var msg DuelMsg
+		if decodeTestMsg(data, &msg) && msg.Type == MsgTypeDuel && msg.Event == "invite" {
+			count++
+		}
+	}
+	return count
+}
+
+func TestDuelInvitesAreRateLimited(t *testing.T) {
+	w := newTestWorld(t, nil)
+	sender := addTestClient(t, w, 500, 500)
+	target := addTestClient(t, w, 900, 500)
+	other := addTestClient(t, w, 1300, 500)
+	duelInvitesFor(t, target)
+	duelInvitesFor(t, other)
+
+	now := time.Now()
+	invite := func(to *Client, at time.Time) {
+		w.handleDuelInput(sender, InputMsg{Type: "duelInvite", DuelTarget: to.ID}, at)
+	}
+
+	invite(target, now)
+	invite(target, now.Add(time.Second))
+	invite(other, now.Add(time.Second))
+	if got := duelInvitesFor(t, target); got != 1 {
+		t.Errorf("target got %d invites, want 1", got)
+	}
+	if got := duelInvitesFor(t, other); got != 0 {
+		t.Errorf("other player got %d invites inside the cooldown, want 0", got)
+	}
+
+	// After the cooldown a new target can be invited, but the pending one can't be re-spammed
+	invite(target, now.Add(DuelInviteCooldown))
+	invite(other, now.Add(2*DuelInviteCooldown))
+	if got := duelInvitesFor(t, target); got != 0 {
+		t.Errorf("target got %d more invites while one was pending, want 0", got)
+	}
+	if got := duelInvitesFor(t, other); got != 1 {
+		t.Errorf("other player got %d invites after the cooldown, want 1", got)
+	}
+}
@@ -0,0 +1,145 @@
+package game
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Practice range settings
+const (
+	DummyResetDelay  = 3 * time.Second // Quiet time after the last hit before a dummy's hull is restored
+	DummyHealthLevel = 10              // Hull strength level, so a dummy soaks up a long burst
+	dummyColor       = "#C8A165"
+)
+
+// DummyReport is the damage one attacker dealt to a practice dummy before it reset
+type DummyReport struct {
+	Damage  float64 `msgpack:"damage"`
+	Seconds float64 `msgpack:"seconds"` // From the dummy's first hit to its last
+	DPS     float64 `msgpack:"dps"`
+}
+
+// spawnDummies adds the configured practice dummies (BotConfig.Dummies);
+// caller must hold w.mu
+func (w *World) spawnDummies(now time.Time) {
+	for i := range w.config.Bots.Dummies {
+		w.spawnDummy(i, now)
+	}
+}
+
+// spawnDummy adds one stationary target dummy: a bot with no weapons that
+// never moves, shoots or retires; caller must hold w.mu
+func (w *World) spawnDummy(i int, now time.Time) {
+	id := w.nextPlayerID
+	w.nextPlayerID++
+
+	player := NewPlayer(id)
+	player.IsBot = true
+	player.Name = fmt.Sprintf("Dummy %d", i+1)
+	player.Color = dummyColor
+	player.InitializeStatUpgrades()
+	ForceStatUpgrades(player, map[UpgradeType]int{StatUpgradeHullStrength: DummyHealthLevel})
+	player.Health = player.MaxHealth
+	player.ShipConfig.SideUpgrade = nil
+	player.ShipConfig.TopUpgrade = nil
+	player.ShipConfig.FrontUpgrade = nil
+	player.ShipConfig.RearUpgrade = nil
+	player.ShipConfig.CalculateShipDimensions()
+	player.ShipConfig.UpdateUpgradePositions()
+
+	spawnPos := w.chooseSafeSpawn(player)
+	player.X = spawnPos.X
+	player.Y = spawnPos.Y
+	player.LastCollisionDamage = now
+
+	bot := &Bot{
+		ID:          id,
+		Player:      player,
+		GuardCenter: spawnPos,
+		Dummy:       true,
+		LastHealth:  player.Health,
+	}
+
+	w.players[id] = player
+	w.bots[id] = bot
+}
+
+// updateDummy pins a dummy to its post and, once it has gone DummyResetDelay
+// without a hit, reports the damage it soaked up and restores its hull
+func (w *World) updateDummy(bot *Bot, now time.Time) {
+	player := bot.Player
+	player.X = bot.GuardCenter.X
+	player.Y = bot.GuardCenter.Y
+	player.VelX, player.VelY = 0, 0
+	player.DriftVelX, player.DriftVelY = 0, 0
+
+	if player.Health < bot.LastHealth {
+		if bot.FirstHit.IsZero() {
+			bot.FirstHit = now
+		}
+		bot.LastHit = now
+	}
+	if !bot.LastHit.IsZero() && now.Sub(bot.LastHit) >= DummyResetDelay {
+		w.resetDummy(bot)
+	}
+	bot.LastHealth = player.Health
+}
+
+// resetDummy reports the damage each attacker dealt since the first hit and
+// restores the dummy to full health with a clean combat tally
+func (w *World) resetDummy(bot *Bot) {
+	player := bot.Player
+	seconds := bot.LastHit.Sub(bot.FirstHit).Seconds()
+	slog.Debug("Dummy reset", "player", player.ID, "name", player.Name, "damageTaken", player.Combat.DamageTaken, "seconds", seconds)
+
+	for attackerID, contribution := range player.RecentDamagers {
+		client, exists := w.clients[attackerID]
+		if !exists {
+			continue
+		}
+		report := DummyReport{Damage: contribution.Amount, Seconds: seconds}
+		if seconds > 0 {
+			report.DPS = contribution.Amount / seconds
+		}
+		client.sendGameEvent(GameEventMsg{
+			EventType:   "dummyReport",
+			VictimID:    player.ID,
+			VictimName:  player.Name,
+			DummyReport: &report,
+		})
+	}
+
+	player.Health = player.MaxHealth
+	player.Combat = CombatStats{}
+	player.RecentDamagers = nil
+	player.clearBurning()
+	bot.FirstHit = time.Time{}
+	bot.LastHit = time.Time{}
+}
+
+// respawnDummy raises a sunk dummy back up at its post
+func (w *World) respawnDummy(bot *Bot) {
+	player := bot.Player
+	player.State = StateAlive
+	player.RespawnTime = time.Time{}
+	w.resetDummy(bot)
+	bot.LastHealth = player.Health
+}
+
+// isDummy reports whether the player is a practice dummy; caller must hold w.mu
+func (w *World) isDummy(player *Player) bool {
+	bot, exists := w.bots[player.ID]
+	return exists && bot.Dummy
+}
+
+// fightingBots counts the bots that aren't practice dummies; caller must hold w.mu
+func (w *World) fightingBots() int {
+	count := 0
+	for _, bot := range w.bots {
+		if !bot.Dummy {
+			count++
+		}
+	}
+	return count
+}
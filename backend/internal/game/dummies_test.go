@@ -0,0 +1,74 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func newDummyWorld(t *testing.T) (*World, *Bot) {
+	t.Helper()
+	w := newTestWorld(t, func(config *WorldConfig) {
+		config.Bots.Dummies = 1
+	})
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.spawnDummies(time.Now())
+	for _, bot := range w.bots {
+		return w, bot
+	}
+	t.Fatal("no dummy spawned")
+	return nil, nil
+}
+
+func TestDummyResetsAndReportsDamage(t *testing.T) {
+	w, bot := newDummyWorld(t)
+	attacker := addTestClient(t, w, 100, 100)
+	gameEvents(t, attacker)
+	dummy := bot.Player
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	start := time.Now()
+	w.mechanics.ApplyDamage(dummy, 40, attacker.Player, KillCauseBullet, start)
+	w.updateDummy(bot, start)
+	w.mechanics.ApplyDamage(dummy, 60, attacker.Player, KillCauseBullet, start.Add(2*time.Second))
+	w.updateDummy(bot, start.Add(2*time.Second))
+
+	w.updateDummy(bot, start.Add(2*time.Second+DummyResetDelay/2))
+	if dummy.Health == dummy.MaxHealth {
+		t.Fatal("dummy reset before DummyResetDelay")
+	}
+
+	w.updateDummy(bot, start.Add(2*time.Second+DummyResetDelay))
+	if dummy.Health != dummy.MaxHealth {
+		t.Fatalf("dummy health after reset = %v, want %v", dummy.Health, dummy.MaxHealth)
+	}
+
+	event, found := findEvent(gameEvents(t, attacker), "dummyReport")
+	if !found || event.DummyReport == nil {
+		t.Fatal("attacker got no dummy report")
+	}
+	if event.DummyReport.Damage != 100 || event.DummyReport.Seconds != 2 || event.DummyReport.DPS != 50 {
+		t.Errorf("report = %+v, want 100 damage over 2s at 50 DPS", *event.DummyReport)
+	}
+}
+
+func TestSinkingDummyIsNotAKill(t *testing.T) {
+	w, bot := newDummyWorld(t)
+	attacker := addTestClient(t, w, 100, 100)
+	gameEvents(t, attacker)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.mechanics.ApplyDamage(bot.Player, bot.Player.Health+1, attacker.Player, KillCauseBullet, time.Now())
+
+	if bot.Player.State != StateDead {
+		t.Fatal("dummy survived")
+	}
+	if attacker.Player.Kills != 0 {
+		t.Errorf("kills = %d, want 0", attacker.Player.Kills)
+	}
+	if _, found := findEvent(gameEvents(t, attacker), "playerSunk"); found {
+		t.Error("dummy kill was broadcast to the kill feed")
+	}
+}
@@ -0,0 +1,82 @@
+package game
+
+import (
+	"log/slog"
+
+	"github.com/gorilla/websocket"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// ErrorCode identifies why a client was rejected or disconnected
+type ErrorCode string
+
+const (
+	ErrorServerFull       ErrorCode = "serverFull"
+	ErrorBanned           ErrorCode = "banned"
+	ErrorInvalidRoom      ErrorCode = "invalidRoom"
+	ErrorProtocolMismatch ErrorCode = "protocolMismatch"
+	ErrorKicked           ErrorCode = "kicked"
+	ErrorIdleTimeout      ErrorCode = "idleTimeout"
+	ErrorServerShutdown   ErrorCode = "serverShutdown"
+)
+
+// ClientError pairs a client-facing message with the websocket close code sent after it
+type ClientError struct {
+	Code      ErrorCode
+	CloseCode int
+	Message   string
+}
+
+// clientErrors is the catalog of every client-facing rejection and disconnect.
+// Application close codes live in the 4000-4999 range reserved by RFC 6455.
+var clientErrors = map[ErrorCode]ClientError{
+	ErrorServerFull:       {ErrorServerFull, websocket.CloseTryAgainLater, "Server is full"},
+	ErrorBanned:           {ErrorBanned, 4001, "You are banned from this server"},
+	ErrorInvalidRoom:      {ErrorInvalidRoom, 4002, "That room does not exist"},
+	ErrorProtocolMismatch: {ErrorProtocolMismatch, 4003, "Client version is not supported, please refresh"},
+	ErrorKicked:           {ErrorKicked, 4004, "You were kicked from the server"},
+	ErrorIdleTimeout:      {ErrorIdleTimeout, 4005, "Disconnected for inactivity"},
+	ErrorServerShutdown:   {ErrorServerShutdown, websocket.CloseGoingAway, "Server is shutting down"},
+}
+
+// LookupClientError returns the catalog entry for a code, falling back to a generic error
+func LookupClientError(code ErrorCode) ClientError {
+	if clientError, exists := clientErrors[code]; exists {
+		return clientError
+	}
+	return ClientError{Code: code, CloseCode: websocket.CloseInternalServerErr, Message: "Disconnected from server"}
+}
+
+// ToMsg builds the structured message sent to the client before the close frame
+func (e ClientError) ToMsg() ErrorMsg {
+	return ErrorMsg{
+		Type:      MsgTypeError,
+		Code:      string(e.Code),
+		CloseCode: e.CloseCode,
+		Message:   e.Message,
+	}
+}
+
+// CloseFrame returns the close frame payload matching this error
+func (e ClientError) CloseFrame() []byte {
+	return websocket.FormatCloseMessage(e.CloseCode, e.Message)
+}
+
+// sendError queues a structured error and records it as the reason for the
+// close frame written once the send channel is closed
+func (client *Client) sendError(code ErrorCode) {
+	clientError := LookupClientError(code)
+	client.CloseReason = &clientError
+
+	data, err := msgpack.Marshal(clientError.ToMsg())
+	if err != nil {
+		slog.Error("Error marshaling error message", "err", err)
+		return
+	}
+
+	select {
+	case client.Send <- data:
+	default:
+		slog.Warn("Could not send error, send buffer full", "client", client.ID, "code", code)
+	}
+}
@@ -0,0 +1,47 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestEveryErrorCodeSendsItsPayloadBeforeClosing(t *testing.T) {
+	wantCloseCodes := map[ErrorCode]int{
+		ErrorServerFull:       websocket.CloseTryAgainLater,
+		ErrorBanned:           4001,
+		ErrorInvalidRoom:      4002,
+		ErrorProtocolMismatch: 4003,
+		ErrorKicked:           4004,
+		ErrorIdleTimeout:      4005,
+		ErrorServerShutdown:   websocket.CloseGoingAway,
+	}
+
+	w := newTestWorld(t, nil)
+	for code, wantCloseCode := range wantCloseCodes {
+		client := addTestClient(t, w, 1000, 1000)
+		queuedMessages(client)
+		w.DisconnectClient(client.ID, code)
+
+		var msg ErrorMsg
+		messages := queuedMessages(client)
+		if len(messages) != 1 || !decodeTestMsg(messages[0], &msg) {
+			t.Errorf("%s: got %d messages, want one error message", code, len(messages))
+			continue
+		}
+		if msg.Type != MsgTypeError || msg.Code != string(code) || msg.CloseCode != wantCloseCode || msg.Message == "" {
+			t.Errorf("%s: payload = %+v, want code %q with close code %d and a message", code, msg, code, wantCloseCode)
+		}
+		if client.CloseReason == nil || client.CloseReason.CloseCode != wantCloseCode {
+			t.Errorf("%s: close reason = %+v, want close code %d", code, client.CloseReason, wantCloseCode)
+		}
+		if _, exists := w.GetClient(client.ID); exists {
+			t.Errorf("%s: client still in the world", code)
+		}
+	}
+
+	unknown := LookupClientError("mystery")
+	if unknown.CloseCode != websocket.CloseInternalServerErr || unknown.Message == "" {
+		t.Errorf("unknown code falls back to %+v, want an internal error with a message", unknown)
+	}
+}
@@ -0,0 +1,94 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// newTestWorld returns a world with no bots that is never started, so tests
+// drive it tick by tick
+func newTestWorld(t *testing.T, configure func(*WorldConfig)) *World {
+	t.Helper()
+	config := DefaultWorldConfig()
+	config.Bots.Count = 0
+	config.Bots.Dummies = 0
+	if configure != nil {
+		configure(&config)
+	}
+	return NewWorldWithConfig(config)
+}
+
+// addTestClient joins a client and spawns its ship at the given position
+func addTestClient(t *testing.T, w *World, x, y float64) *Client {
+	t.Helper()
+	client := NewClient(0, nil)
+	if !w.AddClient(client) {
+		t.Fatal("AddClient refused the client")
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	client.Player.spawn(Position{X: x, Y: y})
+	client.Player.SpawnProtectedUntil = time.Time{}
+	client.Player.Protected = false
+	return client
+}
+
+// drainClient discards everything queued for the client until stop is closed
+func drainClient(client *Client, stop <-chan struct{}) {
+	for {
+		select {
+		case <-client.Send:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// queuedMessages empties the client's send buffer and returns what was in it,
+// including what was queued before the buffer was closed
+func queuedMessages(client *Client) [][]byte {
+	var messages [][]byte
+	for {
+		select {
+		case data, ok := <-client.Send:
+			if !ok {
+				return messages
+			}
+			messages = append(messages, data)
+		default:
+			return messages
+		}
+	}
+}
+
+// gameEvents empties the client's send buffer and returns the game events in it
+func gameEvents(t *testing.T, client *Client) []GameEventMsg {
+	t.Helper()
+	var events []GameEventMsg
+	for _, data := range queuedMessages(client) {
+		var event GameEventMsg
+		if decodeTestMsg(data, &event) && event.Type == MsgTypeGameEvent {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// decodeTestMsg decodes a queued message, reporting false for anything that
+// isn't a plain msgpack map (e.g. compressed snapshots)
+func decodeTestMsg(data []byte, msg any) bool {
+	return msgpack.Unmarshal(data, msg) == nil
+}
+
+// findEvent returns the first event of the given type
+func findEvent(events []GameEventMsg, eventType string) (GameEventMsg, bool) {
+	for _, event := range events {
+		if event.EventType == eventType {
+			return event, true
+		}
+	}
+	return GameEventMsg{}, false
+}
@@ -0,0 +1,69 @@
+package game
+
+import (
+	"log/slog"
+	"time"
+)
+
+// isActiveInput reports whether an input shows the player is actually playing:
+// steering, firing, sending actions or moving the mouse since the previous input
+func isActiveInput(input, previous *InputMsg) bool {
+	return input.Up || input.Down || input.Left || input.Right || input.ManualFire ||
+		len(input.Actions) > 0 || input.Mouse != previous.Mouse
+}
+
+// sweepIdleClients periodically disconnects clients that went silent or AFK
+func (w *World) sweepIdleClients() {
+	ticker := time.NewTicker(IdleSweepInterval)
+	defer ticker.Stop()
+	defer w.workers.Done()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.disconnectIdleClients(time.Now())
+		}
+	}
+}
+
+// disconnectIdleClients removes clients past the idle or AFK timeout. A client
+// that sends nothing at all is idle; a living player whose inputs never steer,
+// aim or fire is AFK. Dead players on the menu are only subject to the idle timeout.
+func (w *World) disconnectIdleClients(now time.Time) {
+	idleTimeout, afkTimeout := w.config.IdleTimeout, w.config.AFKTimeout
+	if idleTimeout <= 0 && afkTimeout <= 0 {
+		return
+	}
+
+	// Snapshot the clients under the world lock, then read each client's
+	// timestamps under its own lock (HandleInput takes client.mu before w.mu)
+	type candidate struct {
+		client *Client
+		alive  bool
+	}
+	w.mu.RLock()
+	candidates := make([]candidate, 0, len(w.clients))
+	for _, client := range w.clients {
+		candidates = append(candidates, candidate{client, client.Player.State == StateAlive})
+	}
+	w.mu.RUnlock()
+
+	for _, c := range candidates {
+		c.client.mu.RLock()
+		silent := now.Sub(c.client.LastSeen)
+		inactive := now.Sub(c.client.LastActive)
+		c.client.mu.RUnlock()
+
+		switch {
+		case idleTimeout > 0 && silent >= idleTimeout:
+			slog.Info("Client idle, disconnecting", "client", c.client.ID, "silentFor", silent.Round(time.Second))
+		case afkTimeout > 0 && c.alive && inactive >= afkTimeout:
+			slog.Info("Player AFK, disconnecting", "player", c.client.ID, "inactiveFor", inactive.Round(time.Second))
+		default:
+			continue
+		}
+		w.DisconnectClient(c.client.ID, ErrorIdleTimeout)
+	}
+}
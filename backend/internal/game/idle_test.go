@@ -0,0 +1,52 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIdleAndAFKClientsAreDisconnectedWhileActiveOnesStay(t *testing.T) {
+	w := newTestWorld(t, func(config *WorldConfig) {
+		config.IdleTimeout = 5 * time.Minute
+		config.AFKTimeout = 3 * time.Minute
+	})
+	silent := addTestClient(t, w, 1000, 1000)
+	afk := addTestClient(t, w, 2000, 1000)
+	active := addTestClient(t, w, 3000, 1000)
+	menu := addTestClient(t, w, 4000, 1000)
+
+	// Everyone joined ten minutes ago
+	joined := time.Now().Add(-10 * time.Minute)
+	for _, client := range []*Client{silent, afk, active, menu} {
+		client.LastSeen, client.LastActive = joined, joined
+	}
+	w.mu.Lock()
+	menu.Player.State, menu.Player.InLobby = StateDead, true
+	w.mu.Unlock()
+
+	// The AFK player and the one on the menu keep sending empty inputs;
+	// only the active player steers
+	w.HandleInput(afk.ID, InputMsg{Type: "input"})
+	w.HandleInput(menu.ID, InputMsg{Type: "input"})
+	w.HandleInput(active.ID, InputMsg{Type: "input", Left: true})
+
+	w.disconnectIdleClients(time.Now())
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	for _, client := range []*Client{silent, afk} {
+		if _, connected := w.clients[client.ID]; connected {
+			t.Errorf("client %d is still connected past its timeout", client.ID)
+		}
+		var closing ErrorMsg
+		messages := queuedMessages(client)
+		if len(messages) == 0 || !decodeTestMsg(messages[len(messages)-1], &closing) || closing.Code != string(ErrorIdleTimeout) {
+			t.Errorf("client %d was not told it timed out: %+v", client.ID, closing)
+		}
+	}
+	for _, client := range []*Client{active, menu} {
+		if _, connected := w.clients[client.ID]; !connected {
+			t.Errorf("client %d was disconnected despite sending input", client.ID)
+		}
+	}
+}
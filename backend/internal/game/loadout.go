@@ -0,0 +1,201 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Loadout preset limits
+const (
+	MaxLoadoutModules    = 16    // Module picks one preset may hold
+	MaxLoadoutStats      = 80    // Stat purchases one preset may hold
+	MaxLoadoutDataLength = 4096  // Bytes of JSON a client may send for a preset
+	MaxSessionLoadouts   = 10000 // Session presets kept in memory before old ones are dropped
+)
+
+// LoadoutPreset is a player's preferred build, replayed automatically each
+// life as upgrade points and coins become available
+type LoadoutPreset struct {
+	Modules []LoadoutModule `json:"modules,omitempty"` // Module picks, in order
+	Stats   []UpgradeType   `json:"stats,omitempty"`   // Stat purchases, in order (repeat a stat for more levels)
+}
+
+// LoadoutModule is one module pick of a preset
+type LoadoutModule struct {
+	Slot   string `json:"slot"`   // "side", "top", "top2", "front" or "rear"
+	Choice string `json:"choice"` // Module name, as in a manual upgrade
+}
+
+// validate rejects presets no player could ever follow: too long, unknown
+// slots or stats, or module names longer than any real module
+func (preset *LoadoutPreset) validate(stats map[UpgradeType]Upgrade) error {
+	if len(preset.Modules) > MaxLoadoutModules {
+		return fmt.Errorf("%d module picks, at most %d allowed", len(preset.Modules), MaxLoadoutModules)
+	}
+	if len(preset.Stats) > MaxLoadoutStats {
+		return fmt.Errorf("%d stat purchases, at most %d allowed", len(preset.Stats), MaxLoadoutStats)
+	}
+	for _, module := range preset.Modules {
+		if moduleTypeForSlot(module.Slot) == "" {
+			return fmt.Errorf("unknown module slot %q", truncateString(module.Slot, MaxUpgradeChoiceLength))
+		}
+		if module.Choice == "" || len(module.Choice) > MaxUpgradeChoiceLength {
+			return fmt.Errorf("invalid module choice %q", truncateString(module.Choice, MaxUpgradeChoiceLength))
+		}
+	}
+	for _, stat := range preset.Stats {
+		if _, known := stats[stat]; !known {
+			return fmt.Errorf("unknown stat %q", truncateString(string(stat), MaxUpgradeChoiceLength))
+		}
+	}
+	return nil
+}
+
+// clone returns a deep copy, so stored presets don't share slices with clients
+func (preset *LoadoutPreset) clone() *LoadoutPreset {
+	if preset == nil {
+		return nil
+	}
+	return &LoadoutPreset{
+		Modules: slices.Clone(preset.Modules),
+		Stats:   slices.Clone(preset.Stats),
+	}
+}
+
+// SessionLoadouts keeps presets by the session token a browser tab sends, so a
+// reconnect keeps its build with or without an account. Safe for concurrent
+// use, since every room shares one.
+type SessionLoadouts struct {
+	mu      sync.Mutex
+	presets map[string]*LoadoutPreset
+}
+
+// NewSessionLoadouts returns an empty session preset store
+func NewSessionLoadouts() *SessionLoadouts {
+	return &SessionLoadouts{presets: make(map[string]*LoadoutPreset)}
+}
+
+// Load returns a copy of the session's preset (nil if none or the token is invalid)
+func (sessions *SessionLoadouts) Load(token string) *LoadoutPreset {
+	if sessions == nil || !validAccountToken(token) {
+		return nil
+	}
+	sessions.mu.Lock()
+	defer sessions.mu.Unlock()
+	return sessions.presets[token].clone()
+}
+
+// Save keeps a copy of the session's preset (nil forgets it). When the store is
+// full an arbitrary other session is dropped to make room.
+func (sessions *SessionLoadouts) Save(token string, preset *LoadoutPreset) {
+	if sessions == nil || !validAccountToken(token) {
+		return
+	}
+	sessions.mu.Lock()
+	defer sessions.mu.Unlock()
+
+	if preset == nil {
+		delete(sessions.presets, token)
+		return
+	}
+	if _, exists := sessions.presets[token]; !exists && len(sessions.presets) >= MaxSessionLoadouts {
+		for other := range sessions.presets {
+			delete(sessions.presets, other)
+			break
+		}
+	}
+	sessions.presets[token] = preset.clone()
+}
+
+// saveLoadout replaces the client's preset with the JSON in data (empty data
+// clears it) and keeps it for their session. Their account, if any, is saved
+// in the background since stores may write to disk (w.mu must be held).
+func (w *World) saveLoadout(client *Client, data string) error {
+	var preset *LoadoutPreset
+	if data != "" {
+		if len(data) > MaxLoadoutDataLength {
+			return fmt.Errorf("preset is %d bytes, at most %d allowed", len(data), MaxLoadoutDataLength)
+		}
+		preset = &LoadoutPreset{}
+		if err := json.Unmarshal([]byte(data), preset); err != nil {
+			return fmt.Errorf("decoding preset: %w", err)
+		}
+		if err := preset.validate(client.Player.Upgrades); err != nil {
+			return err
+		}
+	}
+
+	client.Loadout = preset
+	w.config.Sessions.Save(client.Session, preset)
+	if client.Account != nil && w.config.Accounts != nil {
+		w.accountSaves.Add(1)
+		go w.persistLoadout(client)
+	}
+	return nil
+}
+
+// persistLoadout stores the client's current preset on their account without
+// holding w.mu. Overlapping saves each write the latest preset, so an older
+// one never lands last.
+func (w *World) persistLoadout(client *Client) {
+	defer w.accountSaves.Done()
+	w.accountsMu.Lock()
+	defer w.accountsMu.Unlock()
+
+	w.mu.RLock()
+	stored := client.Account.clone()
+	preset := client.Loadout.clone()
+	w.mu.RUnlock()
+
+	account, err := w.updateAccount(stored, func(account *Account) error {
+		account.Loadout = preset
+		return nil
+	})
+	if err != nil {
+		slog.Error("Could not save loadout to account", "client", client.ID, "err", err)
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	client.Account = account
+}
+
+// applyLoadout takes the next step of the client's preset once the player can
+// afford it, through the same checks as a manual upgrade. Picks that aren't
+// on offer when a point is free (e.g. after a manual detour) are skipped
+// rather than stalling the rest of the build (w.mu must be held).
+func (w *World) applyLoadout(client *Client, player *Player, now time.Time) {
+	preset := client.Loadout
+	if preset == nil || player.State != StateAlive {
+		return
+	}
+
+	if player.AvailableUpgrades > 0 && player.loadoutModuleStep < len(preset.Modules) &&
+		now.Sub(client.LastUpgrade) >= UpgradeCooldown {
+		module := preset.Modules[player.loadoutModuleStep]
+		player.loadoutModuleStep++
+		upgradeType := moduleTypeForSlot(module.Slot)
+		if upgradeType != "" && player.ShipConfig.ApplyModule(upgradeType, module.Choice) {
+			w.moduleUpgraded(client, player, upgradeType, module.Choice, now)
+		} else {
+			slog.Debug("Skipped unavailable loadout module", "player", player.ID, "slot", module.Slot, "choice", module.Choice)
+		}
+	}
+
+	if player.loadoutStatStep < len(preset.Stats) {
+		stat := preset.Stats[player.loadoutStatStep]
+		upgrade, known := player.Upgrades[stat]
+		if known && upgrade.Level < upgrade.MaxLevel && player.Coins < upgrade.CurrentCost {
+			return // Wait for the coins
+		}
+		player.loadoutStatStep++
+		if !player.BuyUpgrade(stat) {
+			slog.Debug("Skipped unavailable loadout stat", "player", player.ID, "stat", stat)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadoutWaitsForUpgradePointsAndCoins(t *testing.T) {
+	w := newTestWorld(t, nil)
+	client := addTestClient(t, w, 1000, 1000)
+	player := client.Player
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	choice := player.ShipConfig.SideUpgrade.NextUpgrades[0].Name
+	client.Loadout = &LoadoutPreset{
+		Modules: []LoadoutModule{{Slot: "side", Choice: choice}},
+		Stats:   []UpgradeType{StatUpgradeMoveSpeed, StatUpgradeMoveSpeed},
+	}
+	player.AvailableUpgrades = 0
+	player.Coins = 0
+	now := time.Now()
+
+	w.applyLoadout(client, player, now)
+	if player.ShipConfig.SideUpgrade.Name == choice || player.Upgrades[StatUpgradeMoveSpeed].Level != 0 {
+		t.Fatal("preset applied without an upgrade point or coins")
+	}
+
+	// Enough coins for the first level only
+	player.Coins = player.Upgrades[StatUpgradeMoveSpeed].CurrentCost
+	player.AvailableUpgrades = 1
+	w.applyLoadout(client, player, now)
+	w.applyLoadout(client, player, now)
+	if player.ShipConfig.SideUpgrade.Name != choice || player.AvailableUpgrades != 0 {
+		t.Errorf("side module = %q with %d points left, want %q with none", player.ShipConfig.SideUpgrade.Name, player.AvailableUpgrades, choice)
+	}
+	if level := player.Upgrades[StatUpgradeMoveSpeed].Level; level != 1 || player.Coins != 0 {
+		t.Errorf("move speed level %d with %d coins left, want level 1 and the second purchase waiting", level, player.Coins)
+	}
+	if player.loadoutStatStep != 1 {
+		t.Errorf("stat step = %d, want the unaffordable purchase still pending", player.loadoutStatStep)
+	}
+}
+
+// blockingAccountStore holds every save until released, so a test can check
+// the world lock is free while an account is being written
+type blockingAccountStore struct {
+	*MemoryAccountStore
+	saving  chan struct{}
+	release chan struct{}
+}
+
+func (store *blockingAccountStore) SaveAccount(account *Account) error {
+	store.saving <- struct{}{}
+	<-store.release
+	return store.MemoryAccountStore.SaveAccount(account)
+}
+
+func TestLoadoutIsSavedOutsideTheWorldLock(t *testing.T) {
+	store := &blockingAccountStore{
+		MemoryAccountStore: NewMemoryAccountStore(),
+		saving:             make(chan struct{}),
+		release:            make(chan struct{}),
+	}
+	sessions := NewSessionLoadouts()
+	w := newTestWorld(t, func(config *WorldConfig) {
+		config.Accounts = store
+		config.Sessions = sessions
+	})
+	client := addTestClient(t, w, 1000, 1000)
+	const token = "account-token-0123456789"
+	const session = "session-token-0123456789"
+
+	w.mu.Lock()
+	client.Account = &Account{Token: token}
+	client.Session = session
+	err := w.saveLoadout(client, `{"stats":["moveSpeed"]}`)
+	w.mu.Unlock()
+	if err != nil {
+		t.Fatalf("saveLoadout: %v", err)
+	}
+
+	<-store.saving
+	w.mu.Lock() // Would deadlock if the save held the world lock
+	w.mu.Unlock()
+	close(store.release)
+	w.accountSaves.Wait()
+
+	account, _ := store.LoadAccount(token)
+	if account == nil || account.Loadout == nil || len(account.Loadout.Stats) != 1 {
+		t.Errorf("account preset = %+v, want the saved preset", account)
+	}
+	if preset := sessions.Load(session); preset == nil || len(preset.Stats) != 1 {
+		t.Errorf("session preset = %+v, want the saved preset", preset)
+	}
+}
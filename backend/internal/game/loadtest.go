@@ -0,0 +1,132 @@
+package game
+
+import (
+	"math/rand"
+	"runtime"
+	"time"
+)
+
+// LoadTestConfig describes a headless run of the game loop
+type LoadTestConfig struct {
+	World            WorldConfig // Base world settings (Bots.Count adds AI ships)
+	Players          int         // Synthetic clients driven by random inputs
+	Ticks            int         // Number of game loop ticks to run
+	Items            int         // Items in the world at the start of the run
+	AutofireFraction float64     // Share of synthetic players with autofire on (drives bullet density)
+}
+
+// LoadTestResult reports the cost of a headless run
+type LoadTestResult struct {
+	Ticks       int
+	Elapsed     time.Duration
+	PerTick     time.Duration
+	Allocs      uint64 // Heap allocations during the run
+	AllocBytes  uint64 // Bytes allocated during the run
+	PeakBullets int    // Most bullets alive at the end of any tick
+	Snapshots   int64  // Snapshots queued to synthetic clients
+}
+
+// RunLoadTest builds a world without a network or ticker, fills it with synthetic
+// players, and runs the game loop as fast as possible. Snapshot marshaling runs in
+// background goroutines, so it only partly shows up in the per-tick time.
+func RunLoadTest(config LoadTestConfig) LoadTestResult {
+	run := newLoadTest(config)
+	defer run.close()
+
+	result := LoadTestResult{Ticks: config.Ticks}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+
+	for tick := 0; tick < config.Ticks; tick++ {
+		run.tick()
+		result.PeakBullets = max(result.PeakBullets, len(run.world.bullets))
+	}
+
+	result.Elapsed = time.Since(start)
+	runtime.ReadMemStats(&after)
+	if config.Ticks > 0 {
+		result.PerTick = result.Elapsed / time.Duration(config.Ticks)
+	}
+	result.Allocs = after.Mallocs - before.Mallocs
+	result.AllocBytes = after.TotalAlloc - before.TotalAlloc
+	result.Snapshots, _ = run.world.GetSnapshotStats()
+
+	return result
+}
+
+// loadTest is a running headless world and the synthetic clients steering in it
+type loadTest struct {
+	world   *World
+	clients []*Client
+	rng     *rand.Rand
+}
+
+// newLoadTest builds the world for a headless run, with its bots, synthetic
+// players and items in place
+func newLoadTest(config LoadTestConfig) *loadTest {
+	w := NewWorldWithConfig(config.World)
+	w.running = true
+	w.startedAt = time.Now()
+	w.spawnInitialBots()
+
+	run := &loadTest{
+		world:   w,
+		clients: make([]*Client, 0, config.Players),
+		rng:     rand.New(rand.NewSource(config.World.Seed)),
+	}
+	for i := 0; i < config.Players; i++ {
+		client := NewClient(0, nil)
+		if !w.AddClient(client) {
+			break
+		}
+		w.mu.Lock()
+		client.Player.spawn(w.chooseSafeSpawn(client.Player))
+		w.mu.Unlock()
+		client.Player.AutofireEnabled = run.rng.Float64() < config.AutofireFraction
+		run.clients = append(run.clients, client)
+
+		// Drain snapshots so the send buffers never fill up
+		go func(c *Client) {
+			for range c.Send {
+			}
+		}(client)
+	}
+
+	for len(w.items) < config.Items {
+		w.mechanics.SpawnLoot(float64(w.rng.Intn(int(WorldWidth))), float64(w.rng.Intn(int(WorldHeight))),
+			ItemTypeGrayCircle, 10, 10, 0, time.Now())
+	}
+	return run
+}
+
+// tick gives every synthetic player a fresh random input and runs one game loop step
+func (run *loadTest) tick() {
+	for _, client := range run.clients {
+		client.Input = randomLoadTestInput(run.rng, client.Player)
+	}
+	run.world.update()
+}
+
+// close removes the synthetic players, which ends their snapshot drains
+func (run *loadTest) close() {
+	for _, client := range run.clients {
+		run.world.RemoveClient(client.ID)
+	}
+	run.world.running = false
+}
+
+// randomLoadTestInput makes a plausible input: random steering with the mouse near the ship
+func randomLoadTestInput(rng *rand.Rand, player *Player) InputMsg {
+	input := InputMsg{
+		Type:  "input",
+		Up:    rng.Intn(4) != 0,
+		Left:  rng.Intn(3) == 0,
+		Right: rng.Intn(3) == 0,
+	}
+	input.Mouse.X = player.X + rng.Float64()*800 - 400
+	input.Mouse.Y = player.Y + rng.Float64()*800 - 400
+	return input
+}
@@ -0,0 +1,49 @@
+package game
+
+import (
+	"fmt"
+	"log/slog"
+	"testing"
+)
+
+// BenchmarkUpdate runs the game loop under load, one tick per iteration. Vary
+// the mix with -bench, e.g. -bench 'Update/players=128'.
+func BenchmarkUpdate(b *testing.B) {
+	cases := []struct {
+		players, bots, items int
+		autofire             float64
+	}{
+		{players: 8, items: 0},
+		{players: 32, bots: 8, items: MaxItems, autofire: 0.5},
+		{players: 128, bots: 16, items: MaxItems, autofire: 0.5},
+		{players: 32, items: MaxItems, autofire: 1},
+	}
+
+	// Keep per-tick logging out of the timings
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.DiscardHandler))
+
+	for _, c := range cases {
+		name := fmt.Sprintf("players=%d/bots=%d/items=%d/autofire=%v", c.players, c.bots, c.items, c.autofire)
+		b.Run(name, func(b *testing.B) {
+			config := LoadTestConfig{
+				World:            DefaultWorldConfig(),
+				Players:          c.players,
+				Items:            c.items,
+				AutofireFraction: c.autofire,
+			}
+			config.World.Seed = 1
+			config.World.Bots.Count = c.bots
+			config.World.Bots.Dummies = 0
+			run := newLoadTest(config)
+			defer run.close()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for range b.N {
+				run.tick()
+			}
+			b.ReportMetric(float64(len(run.world.bullets)), "bullets")
+		})
+	}
+}
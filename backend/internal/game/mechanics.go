@@ -2,7 +2,6 @@ package game
 
 import (
 	"math"
-	"math/rand"
 	"time"
 )
 
@@ -78,6 +77,11 @@ func (gm *GameMechanics) handlePlayerCollision(player1, player2 *Player) {
 	// Ships push against each other when they collide
 	gm.pushShipsApart(player1, player2)
 
+	// Teammates only bump; they don't hurt each other unless configured to
+	if isTeammate(player1, player2) && !gm.world.config.FriendlyCollisionDamage {
+		return
+	}
+
 	// Apply collision damage if enough time has passed since last collision damage
 	gm.applyCollisionDamage(player1, player2, now)
 
@@ -92,6 +96,11 @@ func (gm *GameMechanics) handlePlayerCollision(player1, player2 *Player) {
 	}
 }
 
+// isTeammate reports whether two players are on the same team in team mode
+func isTeammate(player1, player2 *Player) bool {
+	return player1.Team != "" && player1.Team == player2.Team
+}
+
 // pushShipsApart pushes two colliding ships apart based on their bounding boxes
 func (gm *GameMechanics) pushShipsApart(p1, p2 *Player) {
 	bbox1 := p1.GetShipBoundingBox()
@@ -108,9 +117,9 @@ func (gm *GameMechanics) pushShipsApart(p1, p2 *Player) {
 		dy := p1.Y - p2.Y
 		distance := float64(math.Sqrt(float64(dx*dx + dy*dy)))
 
-		// Handle case where ships are at same position
-		if distance == 0 {
-			angle := rand.Float64() * 2 * math.Pi
+		// Handle case where ships are at same position (or a position is not a number)
+		if distance == 0 || !isFinite(distance) {
+			angle := gm.world.rng.Float64() * 2 * math.Pi
 			dx = float64(math.Cos(angle))
 			dy = float64(math.Sin(angle))
 			distance = 1
@@ -189,8 +198,22 @@ func (gm *GameMechanics) applyCollisionDamage(player1, player2 *Player, now time
 	}
 }
 
-// SpawnFoodItems spawns the new 4-tier item system around the map
-func (gm *GameMechanics) SpawnFoodItems() {
+// desiredItemCount is how many items the world should hold for the given number of players
+func desiredItemCount(playerCount int) int {
+	if playerCount <= 0 {
+		return 0
+	}
+	return min(BaseItemCount+ItemsPerPlayer*(playerCount-1), MaxItems)
+}
+
+// foodSpawnInterval is how often food is refilled for the given number of players
+func foodSpawnInterval(playerCount int) time.Duration {
+	interval := BaseFoodSpawnInterval / time.Duration(1+playerCount/PlayersPerSpawnStep)
+	return max(interval, MinFoodSpawnInterval)
+}
+
+// SpawnFoodItems spawns the new 4-tier item system around the map until the world holds target items
+func (gm *GameMechanics) SpawnFoodItems(target int) {
 	// Define the 4 item types with their properties
 	itemTypes := []struct {
 		name   string
@@ -202,6 +225,9 @@ func (gm *GameMechanics) SpawnFoodItems() {
 		{ItemTypeYellowCircle, 10, 10, 20}, // Common
 		{ItemTypeOrangeCircle, 20, 20, 20}, // Uncommon
 		{ItemTypeBlueDiamond, 30, 30, 10},  // Rare
+		{ItemTypeShield, 5, 5, 1},          // Power-ups are very rare
+		{ItemTypeSpeedBoost, 5, 5, 1},
+		{ItemTypeRapidReload, 5, 5, 1},
 	}
 
 	// Calculate total weight
@@ -210,10 +236,100 @@ func (gm *GameMechanics) SpawnFoodItems() {
 		totalWeight += itemType.weight
 	}
 
-	// Spawn until we reach the maximum item count
-	for len(gm.world.items) < MaxItems {
+	// Spawn until we reach the target item count
+	for len(gm.world.items) < min(target, MaxItems) {
 		// Select item type based on weighted probability
-		roll := rand.Intn(totalWeight)
+		roll := gm.world.rng.Intn(totalWeight)
+		currentWeight := 0
+		selectedType := itemTypes[0] // fallback
+
+		for _, itemType := range itemTypes {
+			currentWeight += itemType.weight
+			if roll < currentWeight {
+				selectedType = itemType
+				break
+			}
+		}
+
+		itemID := gm.world.itemID
+		gm.world.itemID++
+
+		item := &GameItem{
+			ID:    itemID,
+			X:     float64(gm.world.rng.Intn(int(WorldWidth-50)) + 25),
+			Y:     float64(gm.world.rng.Intn(int(WorldHeight-50)) + 25),
+			Type:  selectedType.name,
+			Coins: selectedType.coins,
+			XP:    selectedType.xp,
+		}
+		gm.world.items[item.ID] = item
+	}
+}
+
+// TrimItems removes items until the world holds at most target, so a server that
+// empties out does not keep a busy server's worth of loot lying around
+func (gm *GameMechanics) TrimItems(target int) {
+	for id, item := range gm.world.items {
+		if len(gm.world.items) <= target {
+			return
+		}
+		if item.OwnerID != 0 {
+			continue // Leave reserved kill loot for its owner
+		}
+		delete(gm.world.items, id)
+	}
+}
+
+// SpawnLoot drops a collectible item reserved for ownerID for the configured
+// ownership window, after which anyone can collect it (ownerID 0 = anyone)
+func (gm *GameMechanics) SpawnLoot(x, y float64, itemType string, coins, xp int, ownerID uint32, now time.Time) *GameItem {
+	itemID := gm.world.itemID
+	gm.world.itemID++
+
+	item := &GameItem{
+		ID:    itemID,
+		X:     x,
+		Y:     y,
+		Type:  itemType,
+		Coins: coins,
+		XP:    xp,
+	}
+	if ownerID != 0 && gm.world.config.LootOwnershipWindow > 0 {
+		item.OwnerID = ownerID
+		item.OwnedUntil = now.Add(gm.world.config.LootOwnershipWindow)
+	}
+
+	gm.world.items[item.ID] = item
+	return item
+}
+
+// SpawnSpecialItems spawns rare high-value items on the special cadence.
+// Special items have their own cap so they are not crowded out by food.
+func (gm *GameMechanics) SpawnSpecialItems() {
+	itemTypes := []struct {
+		name   string
+		coins  int
+		xp     int
+		weight int // Spawn weight (higher = more common)
+	}{
+		{ItemTypeBlueDiamond, 30, 30, 3}, // Rare
+		{ItemTypeGoldStar, 100, 100, 1},  // Very rare
+	}
+
+	totalWeight := 0
+	for _, itemType := range itemTypes {
+		totalWeight += itemType.weight
+	}
+
+	specialCount := 0
+	for _, item := range gm.world.items {
+		if item.Type == ItemTypeBlueDiamond || item.Type == ItemTypeGoldStar {
+			specialCount++
+		}
+	}
+
+	for spawned := 0; spawned < SpecialItemsPerSpawn && specialCount < MaxSpecialItems; spawned++ {
+		roll := gm.world.rng.Intn(totalWeight)
 		currentWeight := 0
 		selectedType := itemTypes[0] // fallback
 
@@ -230,12 +346,13 @@ func (gm *GameMechanics) SpawnFoodItems() {
 
 		item := &GameItem{
 			ID:    itemID,
-			X:     float64(rand.Intn(int(WorldWidth-50)) + 25),
-			Y:     float64(rand.Intn(int(WorldHeight-50)) + 25),
+			X:     float64(gm.world.rng.Intn(int(WorldWidth-50)) + 25),
+			Y:     float64(gm.world.rng.Intn(int(WorldHeight-50)) + 25),
 			Type:  selectedType.name,
 			Coins: selectedType.coins,
 			XP:    selectedType.xp,
 		}
 		gm.world.items[item.ID] = item
+		specialCount++
 	}
 }
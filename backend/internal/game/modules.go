@@ -2,23 +2,28 @@ package game
 
 import (
 	"math"
+	"slices"
+	"time"
 )
 
-// moduleType defines the category of ship upgrade
+// moduleType is the slot a ship module is fitted to, picked with an upgrade
+// point on level-up. Stat upgrades bought with coins are UpgradeType instead.
 type moduleType string
 
 const (
-	UpgradeTypeSide  moduleType = "side"  // Cannons on the side of the ship
-	UpgradeTypeTop   moduleType = "top"   // Turrets on top of the ship
-	UpgradeTypeFront moduleType = "front" // Ram, front cannons, etc.
-	UpgradeTypeRear  moduleType = "rear"  // Rudder, rear cannons, etc.
+	UpgradeTypeSide     moduleType = "side"  // Cannons on the side of the ship
+	UpgradeTypeTop      moduleType = "top"   // Turrets on top of the ship
+	UpgradeTypeExtraTop moduleType = "top2"  // Second turret slot, combines with the first
+	UpgradeTypeFront    moduleType = "front" // Ram, front cannons, etc.
+	UpgradeTypeRear     moduleType = "rear"  // Rudder, rear cannons, etc.
 )
 
 // ModuleModifier represents the effects an upgrade has on ship stats
 type ModuleModifier struct {
-	SpeedMultiplier     float64 `msgpack:"speedMultiplier"`     // Speed modification (1.0 = no change)
-	TurnRateMultiplier  float64 `msgpack:"turnRateMultiplier"`  // Turn rate modification (1.0 = no change)
-	ShipWidthMultiplier float64 `msgpack:"shipWidthMultiplier"` // Width modification (1.0 = no change)
+	SpeedMultiplier     float64 `msgpack:"speedMultiplier"`      // Speed modification (1.0 = no change)
+	TurnRateMultiplier  float64 `msgpack:"turnRateMultiplier"`   // Turn rate modification (1.0 = no change)
+	ShipWidthMultiplier float64 `msgpack:"shipWidthMultiplier"`  // Width modification (1.0 = no change)
+	FrontArmor          float64 `msgpack:"frontArmor,omitempty"` // Extra share of frontal bullet damage blocked
 }
 
 // ShipModule represents a single upgrade installed on a ship
@@ -32,6 +37,43 @@ type ShipModule struct {
 	Turrets []*Turret      `msgpack:"turrets"` // Turret weapons (if applicable)
 
 	NextUpgrades []*ShipModule `msgpack:"nextUpgrades,omitempty"` // Possible next upgrades
+
+	tractorBeam bool // Module can lock onto and pull enemies (see activateTractorBeam)
+
+	// Ripple fire sequence for side cannons (see WorldConfig.SideCannonRipple)
+	rippleIndex    int       // Next pair of cannons to fire
+	nextRippleShot time.Time // When that pair may fire
+}
+
+// clone deep-copies the module, its weapons and the upgrade tree below it so
+// snapshots can be read without the world lock while the original keeps firing
+func (m *ShipModule) clone() *ShipModule {
+	if m == nil {
+		return nil
+	}
+	copy := *m
+	if m.Cannons != nil {
+		copy.Cannons = make([]*Cannon, len(m.Cannons))
+		for i, cannon := range m.Cannons {
+			c := *cannon
+			copy.Cannons[i] = &c
+		}
+	}
+	if m.Turrets != nil {
+		copy.Turrets = make([]*Turret, len(m.Turrets))
+		for i, turret := range m.Turrets {
+			t := *turret
+			t.Cannons = slices.Clone(turret.Cannons)
+			copy.Turrets[i] = &t
+		}
+	}
+	if m.NextUpgrades != nil {
+		copy.NextUpgrades = make([]*ShipModule, len(m.NextUpgrades))
+		for i, next := range m.NextUpgrades {
+			copy.NextUpgrades[i] = next.clone()
+		}
+	}
+	return &copy
 }
 
 // Predefined upgrade templates
@@ -107,6 +149,42 @@ func NewScatterSideCannons(cannonCount int) *ShipModule {
 	}
 }
 
+func NewIncendiarySideCannons(cannonCount int) *ShipModule {
+	cannonCount = int(math.Max(1, float64(cannonCount))) // Ensure at least 1 cannon per side
+	// Create incendiary cannons for both sides (cannonCount per side)
+	cannons := make([]*Cannon, cannonCount*2)
+
+	// Left side incendiary cannons
+	for i := 0; i < cannonCount; i++ {
+		cannons[i] = &Cannon{
+			ID:    uint32(i + 1),
+			Stats: NewIncendiaryCannon(),
+			Type:  WeaponTypeIncendiary,
+		}
+	}
+
+	// Right side incendiary cannons
+	for i := 0; i < cannonCount; i++ {
+		cannons[cannonCount+i] = &Cannon{
+			ID:    uint32(cannonCount + i + 1),
+			Stats: NewIncendiaryCannon(),
+			Type:  WeaponTypeIncendiary,
+		}
+	}
+
+	return &ShipModule{
+		Type:    UpgradeTypeSide,
+		Name:    "Incendiary Cannons",
+		Count:   cannonCount,
+		Cannons: cannons,
+		Effect: ModuleModifier{
+			SpeedMultiplier:     -0.04,
+			TurnRateMultiplier:  0,
+			ShipWidthMultiplier: 1.0,
+		},
+	}
+}
+
 func NewBasicTurrets(turretCount int) *ShipModule {
 	turretCount = int(math.Max(0, float64(turretCount))) // Ensure non-negative
 
@@ -118,9 +196,10 @@ func NewBasicTurrets(turretCount int) *ShipModule {
 			Type:  WeaponTypeCannon,
 		}
 		turret := &Turret{
-			ID:      uint32(i + 1),
-			Cannons: []Cannon{turretCannon},
-			Type:    WeaponTypeTurret,
+			ID:            uint32(i + 1),
+			Cannons:       []Cannon{turretCannon},
+			Type:          WeaponTypeTurret,
+			RotationSpeed: BasicTurretRotationSpeed,
 		}
 		turrets[i] = turret
 	}
@@ -148,9 +227,10 @@ func NewBigTurrets(turretCount int) *ShipModule {
 			Type:  WeaponTypeCannon,
 		}
 		turret := &Turret{
-			ID:      uint32(i + 1),
-			Cannons: []Cannon{turretCannon},
-			Type:    WeaponTypeBigTurret,
+			ID:            uint32(i + 1),
+			Cannons:       []Cannon{turretCannon},
+			Type:          WeaponTypeBigTurret,
+			RotationSpeed: BigTurretRotationSpeed,
 		}
 		turrets[i] = turret
 	}
@@ -198,6 +278,7 @@ func NewMachineGunTurret(turretCount int) *ShipModule {
 			Cannons:         []Cannon{leftCannon, rightCannon},
 			Type:            WeaponTypeMachineGunTurret,
 			NextCannonIndex: 0, // Start with the first cannon
+			RotationSpeed:   MachineGunTurretRotationSpeed,
 		}
 		turrets[i] = turret
 	}
@@ -215,6 +296,38 @@ func NewMachineGunTurret(turretCount int) *ShipModule {
 	}
 }
 
+func NewFlakTurret(turretCount int) *ShipModule {
+	turretCount = int(math.Max(0, float64(turretCount))) // Ensure non-negative
+
+	turrets := make([]*Turret, turretCount)
+	for i := 0; i < turretCount; i++ {
+		turretCannon := Cannon{
+			ID:    uint32(i),
+			Stats: NewFlakCannon(),
+			Type:  WeaponTypeCannon,
+		}
+		turret := &Turret{
+			ID:            uint32(i + 1),
+			Cannons:       []Cannon{turretCannon},
+			Type:          WeaponTypeFlakTurret,
+			RotationSpeed: MachineGunTurretRotationSpeed,
+		}
+		turrets[i] = turret
+	}
+
+	return &ShipModule{
+		Type:    UpgradeTypeTop,
+		Name:    "Flak Turret",
+		Count:   turretCount,
+		Turrets: turrets,
+		Effect: ModuleModifier{
+			SpeedMultiplier:     -0.05,
+			TurnRateMultiplier:  -0.05,
+			ShipWidthMultiplier: 1.05,
+		},
+	}
+}
+
 func NewTopUpgradeTree() *ShipModule {
 	root := &ShipModule{
 		Type:    UpgradeTypeTop,
@@ -234,6 +347,9 @@ func NewTopUpgradeTree() *ShipModule {
 	bigTurret1 := NewBigTurrets(1)
 	bigTurret2 := NewBigTurrets(2)
 
+	// Point-defense branch off the machine gun path
+	flakTurret1 := NewFlakTurret(1)
+
 	// Link the upgrade paths
 	// From root, you can choose basic turret or machine gun turret
 	root.NextUpgrades = []*ShipModule{machineGunTurret1, turret1}
@@ -245,7 +361,7 @@ func NewTopUpgradeTree() *ShipModule {
 	bigTurret1.NextUpgrades = []*ShipModule{bigTurret2}
 
 	// machine gun path
-	machineGunTurret1.NextUpgrades = []*ShipModule{machineGunTurret2}
+	machineGunTurret1.NextUpgrades = []*ShipModule{machineGunTurret2, flakTurret1}
 	return root
 }
 
@@ -258,13 +374,16 @@ func NewSideUpgradeTree() *ShipModule {
 	// Build the scatter cannon branch: 1 (from root)
 	scatter1 := NewScatterSideCannons(1)
 
+	// Build the incendiary cannon branch: 2 (from basic 2)
+	incendiary2 := NewIncendiarySideCannons(2)
+
 	// Build the rowing oars branch: 1 -> 2 -> 3
 	rowing1 := NewRowingUpgrade(1)
 	rowing2 := NewRowingUpgrade(2)
 	rowing3 := NewRowingUpgrade(3)
 
 	// Link the basic cannon chain
-	basic2.NextUpgrades = []*ShipModule{basic3}
+	basic2.NextUpgrades = []*ShipModule{incendiary2, basic3}
 	basic3.NextUpgrades = []*ShipModule{basic4}
 
 	// Link the rowing oars chain
@@ -337,6 +456,7 @@ func NewRamUpgrade() *ShipModule {
 			SpeedMultiplier:     -0.2,
 			TurnRateMultiplier:  -0.2,
 			ShipWidthMultiplier: 1.0,
+			FrontArmor:          0.15,
 		},
 	}
 }
@@ -370,6 +490,72 @@ func NewChaseCannonUpgrade() *ShipModule {
 	}
 }
 
+func NewTractorBeamUpgrade() *ShipModule {
+	return &ShipModule{
+		Type:  UpgradeTypeFront,
+		Name:  "Tractor Beam",
+		Count: 1,
+
+		tractorBeam: true,
+		Effect: ModuleModifier{
+			SpeedMultiplier:     -0.05,
+			TurnRateMultiplier:  -0.05,
+			ShipWidthMultiplier: 1.0,
+		},
+	}
+}
+
+func NewRicochetCannonUpgrade() *ShipModule {
+	cannon1 := &Cannon{
+		ID:    1,
+		Stats: NewRicochetCannon(),
+		Type:  WeaponTypeRicochet,
+	}
+
+	cannon2 := &Cannon{
+		ID:    2,
+		Stats: NewRicochetCannon(),
+		Type:  WeaponTypeRicochet,
+	}
+
+	return &ShipModule{
+		Type:  UpgradeTypeFront,
+		Name:  "Ricochet Cannons",
+		Count: 2,
+		Cannons: []*Cannon{
+			cannon1,
+			cannon2,
+		},
+		Effect: ModuleModifier{
+			SpeedMultiplier:     -0.05,
+			TurnRateMultiplier:  -0.05,
+			ShipWidthMultiplier: 1.0,
+		},
+	}
+}
+
+func NewPiercingCannonUpgrade() *ShipModule {
+	cannon := &Cannon{
+		ID:    1,
+		Stats: NewPiercingCannon(),
+		Type:  WeaponTypePiercing,
+	}
+
+	return &ShipModule{
+		Type:  UpgradeTypeFront,
+		Name:  "Piercing Cannon",
+		Count: 1,
+		Cannons: []*Cannon{
+			cannon,
+		},
+		Effect: ModuleModifier{
+			SpeedMultiplier:     -0.05,
+			TurnRateMultiplier:  -0.05,
+			ShipWidthMultiplier: 1.0,
+		},
+	}
+}
+
 func NewFrontUpgradeTree() *ShipModule {
 	root := &ShipModule{
 		Type: UpgradeTypeFront,
@@ -378,11 +564,35 @@ func NewFrontUpgradeTree() *ShipModule {
 
 	ram := NewRamUpgrade()
 	chaseCannons := NewChaseCannonUpgrade()
-	root.NextUpgrades = []*ShipModule{ram, chaseCannons}
+	tractorBeam := NewTractorBeamUpgrade()
+	root.NextUpgrades = []*ShipModule{ram, chaseCannons, tractorBeam}
+
+	ricochetCannons := NewRicochetCannonUpgrade()
+	piercingCannon := NewPiercingCannonUpgrade()
+	chaseCannons.NextUpgrades = []*ShipModule{ricochetCannons, piercingCannon}
 
 	return root
 }
 
+// moduleTypeForSlot maps the slot name a client sends to its module type
+// ("" for an unknown slot)
+func moduleTypeForSlot(slot string) moduleType {
+	switch slot {
+	case "side":
+		return UpgradeTypeSide
+	case "top":
+		return UpgradeTypeTop
+	case "top2":
+		return UpgradeTypeExtraTop
+	case "front":
+		return UpgradeTypeFront
+	case "rear":
+		return UpgradeTypeRear
+	default:
+		return ""
+	}
+}
+
 // GetAvailableModules returns the next available upgrades for a given upgrade type
 func (sc *ShipConfiguration) GetAvailableModules(upgradeType moduleType) []*ShipModule {
 	var availableUpgrades []*ShipModule
@@ -404,6 +614,16 @@ func (sc *ShipConfiguration) GetAvailableModules(upgradeType moduleType) []*Ship
 		}
 		return sc.TopUpgrade.NextUpgrades
 
+	case UpgradeTypeExtraTop:
+		if sc.TopUpgrade == nil || sc.TopUpgrade.Name == "No Top Upgrades" {
+			// The second slot opens once the first holds a turret
+			return nil
+		}
+		if sc.ExtraTop == nil {
+			return NewTopUpgradeTree().NextUpgrades
+		}
+		return sc.ExtraTop.NextUpgrades
+
 	case UpgradeTypeFront:
 		if sc.FrontUpgrade == nil || sc.FrontUpgrade.Name == "No Front Upgrades" {
 			root := NewFrontUpgradeTree()
@@ -457,6 +677,8 @@ func (sc *ShipConfiguration) ApplyModule(moduleType moduleType, moduleID string)
 		sc.SideUpgrade = selectedModule
 	case UpgradeTypeTop:
 		sc.TopUpgrade = selectedModule
+	case UpgradeTypeExtraTop:
+		sc.ExtraTop = selectedModule
 	case UpgradeTypeFront:
 		sc.FrontUpgrade = selectedModule
 	case UpgradeTypeRear:
@@ -1,9 +1,9 @@
 package game
 
 import (
-	"log"
+	"log/slog"
 	"math"
-	"math/rand"
+	"slices"
 	"time"
 )
 
@@ -16,30 +16,102 @@ type Mods struct {
 	MoveSpeedMultiplier    float64
 	TurnSpeedMultiplier    float64
 	BodyDamageBonus        float64
+	InaccuracyMultiplier   float64
+	MagnetRadius           float64 // Items within this distance drift toward the ship (0 = off)
+	MagnetPull             float64 // Distance a magnetized item moves per second
+	VisionRange            float64 // How far away other ships and bullets are sent to this player
+	FrontArmor             float64 // Extra share of frontal bullet damage blocked by modules
 }
 
-// spawn spawns a player at a random safe location
-func (player *Player) spawn() {
-	// Simple random spawn - could be improved to avoid other players
-	player.X = float64(rand.Intn(int(WorldWidth-100)) + 50)
-	player.Y = float64(rand.Intn(int(WorldHeight-100)) + 50)
+// spawn brings a player to life at position (see World.chooseSafeSpawn)
+func (player *Player) spawn(position Position) {
+	player.X = position.X
+	player.Y = position.Y
 	player.State = StateAlive
+	player.InLobby = false
 	player.SpawnTime = time.Now() // Track when player spawned
+	// Spawning is a legitimate teleport, so restart movement validation
+	player.MovementTracked = false
+	player.resetCampingState()
+	player.RecentDamagers = nil
+	player.PassiveIncomeEarned = 0
+	player.passiveIncomeBalance = 0
+	player.loadoutModuleStep = 0
+	player.loadoutStatStep = 0
 }
 
-// respawnPlayer respawns a dead player when they request it
-func (player *Player) respawn() {
+// visionRange returns how far the player can see, falling back to the base
+// range if modifiers haven't been calculated yet
+func (player *Player) visionRange() float64 {
+	if player.Modifiers.VisionRange <= 0 {
+		return BaseVisionRange
+	}
+	return player.Modifiers.VisionRange
+}
+
+// resetCampingState clears movement tracking, e.g. after a spawn
+func (player *Player) resetCampingState() {
+	player.CampWindowStart = time.Time{}
+	player.CampingSince = time.Time{}
+}
+
+// updateCampingState measures net displacement over fixed windows to detect
+// players parked in one spot
+func (player *Player) updateCampingState(now time.Time) {
+	if player.CampWindowStart.IsZero() {
+		player.CampWindowStart = now
+		player.CampWindowOrigin = Position{X: player.X, Y: player.Y}
+		return
+	}
+
+	if now.Sub(player.CampWindowStart) < CampWindow {
+		return
+	}
+
+	dx := player.X - player.CampWindowOrigin.X
+	dy := player.Y - player.CampWindowOrigin.Y
+	if dx*dx+dy*dy < CampDisplacementMin*CampDisplacementMin {
+		if player.CampingSince.IsZero() {
+			player.CampingSince = player.CampWindowStart
+		}
+	} else {
+		player.CampingSince = time.Time{}
+	}
+
+	player.CampWindowStart = now
+	player.CampWindowOrigin = Position{X: player.X, Y: player.Y}
+}
+
+// campingRewardMultiplier returns the kill reward multiplier for a player,
+// decaying the longer they have been camping
+func (player *Player) campingRewardMultiplier(now time.Time) float64 {
+	if !AntiCampEnabled || player.CampingSince.IsZero() {
+		return 1.0
+	}
+
+	campedSeconds := now.Sub(player.CampingSince).Seconds()
+	return math.Max(CampMinRewardMultiplier, 1.0-campedSeconds*CampRewardDecayPerSecond)
+}
+
+// canRespawn reports whether the player is dead and past its respawn time
+func (player *Player) canRespawn(now time.Time) bool {
+	return player.State == StateDead && !now.Before(player.RespawnTime)
+}
+
+// respawnPlayer respawns a dead player when they request it, keeping the
+// configured fraction of their progress
+func (player *Player) respawn(config WorldConfig, position Position) {
 	now := time.Now()
 
 	// Only respawn if player is dead and respawn time has passed
-	if player.State != StateDead || now.Before(player.RespawnTime) {
+	if !player.canRespawn(now) {
 		return
 	}
 
-	// Save half of previous XP and coins
-	respawnXP := player.Experience / 2
-	respawnCoins := player.Coins / 2
-	respawnScore := player.Score / 2
+	// Save part of previous XP and coins
+	respawnXP := int(float64(player.Experience) * config.RespawnXPRetention)
+	respawnCoins := int(float64(player.Coins) * config.RespawnCoinRetention)
+	respawnScore := int(float64(player.Score) * config.RespawnXPRetention)
 
 	// Save player identity
 	playerID := player.ID
@@ -67,6 +139,11 @@ func (player *Player) respawn() {
 	player.KilledByName = ""
 	player.ScoreAtDeath = 0
 	player.SurvivalTime = 0
+	player.Combat = CombatStats{}
+
+	player.clearBurning()
+	player.releaseTractorBeam()
+	player.clearBuffs()
 
 	// Reset autofire to default enabled state
 	player.AutofireEnabled = false
@@ -85,17 +162,113 @@ func (player *Player) respawn() {
 		MoveSpeedMultiplier:    1.0,
 		TurnSpeedMultiplier:    1.0,
 		BodyDamageBonus:        1.0,
+		InaccuracyMultiplier:   1.0,
+		VisionRange:            BaseVisionRange,
 	}
 
-	// Reset stat upgrades
+	// Reset stat upgrades, then derive health and speed from the ship class
 	player.InitializeStatUpgrades()
+	player.updateModifiers()
+	player.Health = player.MaxHealth
 
-	player.spawn()
+	player.spawn(position)
+	player.protectSpawn(now, config.SpawnProtection)
 
 	// Send updated available upgrades to client
 	player.Client.sendAvailableUpgrades()
 
-	log.Printf("Player %d (%s) respawned with %d XP and %d coins", player.ID, player.Name, respawnXP, respawnCoins)
+	slog.Info("Player respawned", "player", player.ID, "name", player.Name, "xp", respawnXP, "coins", respawnCoins)
+}
+
+// sanitizeState resets any non-finite position, velocity or angle to a safe
+// value and reports whether anything had to be fixed
+func (player *Player) sanitizeState() bool {
+	fixed := false
+	if !isFinite(player.X) || !isFinite(player.Y) {
+		player.X = WorldWidth / 2
+		player.Y = WorldHeight / 2
+		fixed = true
+	}
+	if !isFinite(player.VelX) || !isFinite(player.VelY) {
+		player.VelX = 0
+		player.VelY = 0
+		fixed = true
+	}
+	if !isFinite(player.DriftVelX) || !isFinite(player.DriftVelY) {
+		player.DriftVelX = 0
+		player.DriftVelY = 0
+		fixed = true
+	}
+	if !isFinite(player.Angle) {
+		player.Angle = 0
+		fixed = true
+	}
+	return fixed
+}
+
+// applyRecoil pushes the ship opposite a shot fired at angle
+func (player *Player) applyRecoil(angle float64, stats CannonStats, strength float64) {
+	impulse := strength * stats.recoilWeight()
+	if impulse <= 0 {
+		return
+	}
+	player.DriftVelX -= math.Cos(angle) * impulse
+	player.DriftVelY -= math.Sin(angle) * impulse
+}
+
+// dash kicks the ship forward along its heading
+func (player *Player) dash(impulse float64) {
+	player.DriftVelX += math.Cos(player.Angle) * impulse
+	player.DriftVelY += math.Sin(player.Angle) * impulse
+}
+
+// isFinite reports whether value is neither NaN nor infinite
+func isFinite(value float64) bool {
+	return !math.IsNaN(value) && !math.IsInf(value, 0)
+}
+
+// isSinking reports whether the player died recently enough to still be shown as a wreck
+func (player *Player) isSinking(now time.Time) bool {
+	return player.State == StateDead && !player.DeathTime.IsZero() && now.Sub(player.DeathTime) < SinkDuration
+}
+
+// updateTimers refreshes the sink and dash timers clients are sent. They are
+// relative to now, so clients don't depend on their clock matching ours.
+func (player *Player) updateTimers(now time.Time) {
+	player.Sinking = player.isSinking(now)
+	player.SinkElapsed = 0
+	if player.Sinking {
+		player.SinkElapsed = now.Sub(player.DeathTime).Milliseconds()
+	}
+	player.DashCooldown = max(0, player.DashReadyAt.Sub(now).Milliseconds())
+}
+
+// protectSpawn makes a freshly spawned player invulnerable for duration (0 = off)
+func (player *Player) protectSpawn(now time.Time, duration time.Duration) {
+	player.SpawnProtectedUntil = now.Add(duration)
+	player.Protected = duration > 0
+}
+
+// isSpawnProtected reports whether the player is still inside their spawn protection window
+func (player *Player) isSpawnProtected(now time.Time) bool {
+	return now.Before(player.SpawnProtectedUntil)
+}
+
+// ignite sets the player on fire; repeated hits extend the duration but not the damage
+func (player *Player) ignite(sourceID uint32, now time.Time) {
+	if player.BurningUntil.Before(now) {
+		player.BurningUntil = now
+	}
+	player.BurningUntil = player.BurningUntil.Add(BurnDuration)
+	player.BurnSourceID = sourceID
+	player.Burning = true
+}
+
+// clearBurning puts out any fire on the player
+func (player *Player) clearBurning() {
+	player.Burning = false
+	player.BurningUntil = time.Time{}
+	player.BurnSourceID = 0
 }
 
 // updateShipGeometry updates ship dimensions based on cannon and turret count
@@ -123,27 +296,71 @@ func (player *Player) resetPlayerShipConfig() {
 		ShipWidth:    shipWidth,
 		Size:         PlayerSize,
 	}
+	player.ShipConfig.SideUpgrade = startingSideModule(player.ShipConfig.SideUpgrade, player.Class.stats().SideCannons)
 
 	// Recalculate ship dimensions and positions
 	player.updateShipGeometry()
 }
 
-// AddExperience adds experience and handles level ups
-func (p *Player) AddExperience(exp int) {
-	p.Experience += exp
+// AddExperience adds experience, handles level ups and returns the number of levels gained
+func (p *Player) AddExperience(exp int, maxLevel int) int {
+	p.Experience = saturatingAdd(p.Experience, exp, MaxExperience)
+	return p.applyLevelUps(maxLevel)
+}
+
+// addRewards credits score and coins, saturating at the configured caps
+func (p *Player) addRewards(score, coins int, config WorldConfig) {
+	p.Score = saturatingAdd(p.Score, score, config.MaxScore)
+	p.Coins = saturatingAdd(p.Coins, coins, config.MaxCoins)
+}
+
+// saturatingAdd returns value+amount clamped to [0, limit] without
+// overflowing (limit <= 0 = no cap beyond the int range)
+func saturatingAdd(value, amount, limit int) int {
+	if limit <= 0 {
+		limit = math.MaxInt
+	}
+	if amount > 0 && value > limit-amount {
+		return limit
+	}
+	return max(min(value+amount, limit), 0)
+}
+
+// applyLevelUps raises the player to the level their experience has earned,
+// capped at maxLevel, and returns the number of levels gained. Experience past
+// the cap is banked as prestige, so this runs in constant time however much
+// experience was granted.
+func (p *Player) applyLevelUps(maxLevel int) (gained int) {
+	if target := min(levelForExperience(p.Experience), maxLevel); target > p.Level {
+		gained = target - p.Level
+		p.AvailableUpgrades += gained
+		p.Level = target
+	}
+
+	if p.Level < maxLevel {
+		return gained
+	}
 
-	// Check for level up
-	if p.Experience >= p.GetExperienceRequiredForNextLevel() {
-		p.Level++
-		p.AvailableUpgrades++
+	// Each prestige costs as much as one more level past the cap would
+	capExperience := GetExperienceRequiredForLevel(maxLevel)
+	prestigeCost := GetExperienceRequiredForLevel(maxLevel+1) - capExperience
+	if surplus := p.Experience - capExperience; surplus >= prestigeCost {
+		ranks := surplus / prestigeCost
+		p.Prestige += ranks
+		p.Experience -= ranks * prestigeCost
 	}
+	return gained
 }
 
-// DebugLevelUp increases the player's level (for testing)
-func (p *Player) DebugLevelUp() {
+// DebugLevelUp increases the player's level (for testing) and returns the levels gained
+func (p *Player) DebugLevelUp(maxLevel int) int {
+	if p.Level >= maxLevel {
+		return 0
+	}
 	p.Level++
 	p.Experience = p.GetExperienceForCurrentLevel()
 	p.AvailableUpgrades++
+	return 1
 }
 
 // GetShipBoundingBox calculates the axis-aligned bounding box for a rotated ship
@@ -209,6 +426,11 @@ func copyPlayer(player Player) Player {
 		}
 	}
 
+	// Deep copy the modules and weapons; turrets keep aiming and cannons keep
+	// firing while the snapshot goroutines compute deltas from this copy
+	copy.ShipConfig = player.ShipConfig.clone()
+	copy.ActiveBuffs = slices.Clone(player.ActiveBuffs)
+
 	return copy
 }
 
@@ -223,6 +445,8 @@ func hasPlayerChanges(delta PlayerDelta) bool {
 		delta.State != nil ||
 		delta.Name != nil ||
 		delta.Color != nil ||
+		delta.Flag != nil ||
+		delta.Team != nil ||
 		delta.Health != nil ||
 		delta.MaxHealth != nil ||
 		delta.Level != nil ||
@@ -231,10 +455,24 @@ func hasPlayerChanges(delta PlayerDelta) bool {
 		delta.Coins != nil ||
 		delta.Upgrades != nil ||
 		delta.AutofireEnabled != nil ||
+		delta.AimedAutofire != nil ||
 		delta.DebugInfo != nil ||
 		delta.ScoreAtDeath != nil ||
 		delta.SurvivalTime != nil ||
-		delta.KilledByName != nil
+		delta.KilledBy != nil ||
+		delta.KilledByName != nil ||
+		delta.Burning != nil ||
+		delta.TractorTargetID != nil ||
+		delta.Protected != nil ||
+		delta.LastInputSequence != nil ||
+		delta.LastActionSeq != nil ||
+		delta.Prestige != nil ||
+		delta.Kills != nil ||
+		delta.IsBounty != nil ||
+		delta.ActiveBuffs != nil ||
+		delta.Sinking != nil ||
+		delta.SinkElapsed != nil ||
+		delta.DashCooldown != nil
 }
 
 // InitializeStatUpgrades initializes the stat upgrade system for a player
@@ -250,6 +488,9 @@ func (player *Player) InitializeStatUpgrades() {
 		StatUpgradeMoveSpeed,
 		StatUpgradeTurnSpeed,
 		StatUpgradeBodyDamage,
+		StatUpgradeAccuracy,
+		StatUpgradeMagnet,
+		StatUpgradeVision,
 	}
 
 	for _, upgradeType := range upgradeTypes {
@@ -313,6 +554,30 @@ func (player *Player) BuyUpgrade(upgradeType UpgradeType) bool {
 	return true
 }
 
+// RespecStats resets every stat upgrade to level 0 and refunds the coins spent
+// on them at RespecRefundRate (up to the coin cap), returning the refunded amount
+func (player *Player) RespecStats(config WorldConfig) int {
+	spent := 0
+	for upgradeType, upgrade := range player.Upgrades {
+		// Level n cost BaseCost*n, so n levels cost BaseCost*n*(n+1)/2
+		spent += upgrade.BaseCost * upgrade.Level * (upgrade.Level + 1) / 2
+		upgrade.Level = 0
+		upgrade.CurrentCost = upgrade.BaseCost
+		player.Upgrades[upgradeType] = upgrade
+	}
+
+	refund := int(float64(spent) * RespecRefundRate)
+	player.addRewards(0, refund, config)
+
+	player.updateModifiers()
+	player.Health = min(player.Health, player.MaxHealth)
+
+	// Rebuild the hull from the mounted modules, dropping the hull strength widening
+	player.updateShipGeometry()
+
+	return refund
+}
+
 // updateModifiers applies the effects of a stat upgrade to the player
 // modifiers are percentage multipliers off base values
 // stack additively
@@ -320,24 +585,27 @@ func (player *Player) updateModifiers() {
 	sc := &player.ShipConfig
 	moduleSpeedModifier := float64(0)
 	moduleTurnSpeedMultiplier := float64(0)
-	modules := []*ShipModule{sc.SideUpgrade, sc.TopUpgrade, sc.FrontUpgrade, sc.RearUpgrade}
+	moduleFrontArmor := float64(0)
+	modules := []*ShipModule{sc.SideUpgrade, sc.TopUpgrade, sc.ExtraTop, sc.FrontUpgrade, sc.RearUpgrade}
 
 	for _, module := range modules {
 		if module != nil {
 			moduleSpeedModifier += module.Effect.SpeedMultiplier * float64(module.Count)
 			moduleTurnSpeedMultiplier += module.Effect.TurnRateMultiplier * float64(module.Count)
-
+			moduleFrontArmor += module.Effect.FrontArmor * float64(module.Count)
 		}
 	}
+	player.Modifiers.FrontArmor = moduleFrontArmor
 
+	class := player.Class.stats()
 	healthLevel := player.Upgrades[StatUpgradeHullStrength].Level
-	player.MaxHealth = 100.0 + float64(healthLevel * HealthIncrease)
+	player.MaxHealth = class.BaseHealth + float64(healthLevel*HealthIncrease)
 
 	hullLevel := player.Upgrades[StatUpgradeHullStrength].Level
 	moveLevel := player.Upgrades[StatUpgradeMoveSpeed].Level
 	ramLevel := player.Upgrades[StatUpgradeBodyDamage].Level
 	// speed multipler is -1% per hull level, +2% per move level
-	player.Modifiers.MoveSpeedMultiplier = 1.0 - float64(hullLevel)*0.01 - float64(ramLevel)*0.01 + float64(moveLevel)*0.02
+	player.Modifiers.MoveSpeedMultiplier = class.SpeedMultiplier - float64(hullLevel)*0.01 - float64(ramLevel)*0.01 + float64(moveLevel)*0.02
 	player.Modifiers.MoveSpeedMultiplier += moduleSpeedModifier
 
 	repairLevel := player.Upgrades[StatUpgradeAutoRepairs].Level
@@ -353,8 +621,24 @@ func (player *Player) updateModifiers() {
 	player.Modifiers.ReloadSpeedMultiplier = 1.0 - (float64(reloadLevel) * 0.03) // 2% faster per level
 
 	turnLevel := player.Upgrades[StatUpgradeTurnSpeed].Level
-	player.Modifiers.TurnSpeedMultiplier = 1 + float64(turnLevel)*0.02 - float64(ramLevel)*0.01
+	player.Modifiers.TurnSpeedMultiplier = class.TurnSpeedMultiplier + float64(turnLevel)*0.02 - float64(ramLevel)*0.01
 	player.Modifiers.TurnSpeedMultiplier += moduleTurnSpeedMultiplier
 
 	player.Modifiers.BodyDamageBonus = float64(ramLevel) * 0.5
+
+	accuracyLevel := player.Upgrades[StatUpgradeAccuracy].Level
+	player.Modifiers.InaccuracyMultiplier = 1.0 - float64(accuracyLevel)*0.05 // 5% tighter per level
+
+	magnetLevel := player.Upgrades[StatUpgradeMagnet].Level
+	player.Modifiers.MagnetRadius = 0
+	player.Modifiers.MagnetPull = 0
+	if magnetLevel > 0 {
+		player.Modifiers.MagnetRadius = MagnetBaseRadius + float64(magnetLevel)*MagnetRadiusPerLevel
+		player.Modifiers.MagnetPull = MagnetBasePull + float64(magnetLevel)*MagnetPullPerLevel
+	}
+
+	visionLevel := player.Upgrades[StatUpgradeVision].Level
+	player.Modifiers.VisionRange = BaseVisionRange + float64(visionLevel)*VisionRangePerLevel
+
+	player.applyBuffModifiers()
 }
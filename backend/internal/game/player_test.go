@@ -0,0 +1,249 @@
+package game
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRespecRestoresHullWidth(t *testing.T) {
+	player := NewPlayer(1)
+	player.InitializeStatUpgrades()
+	if !player.ShipConfig.ApplyModule(UpgradeTypeTop, NewBasicTurrets(1).Name) {
+		t.Fatal("could not fit a turret")
+	}
+	player.updateShipGeometry()
+	player.updateModifiers()
+	width := player.ShipConfig.ShipWidth
+
+	player.Coins = 10000
+	for range 5 {
+		if !player.BuyUpgrade(StatUpgradeHullStrength) {
+			t.Fatal("could not buy hull strength")
+		}
+	}
+	if player.ShipConfig.ShipWidth <= width {
+		t.Fatalf("width after hull upgrades = %v, want wider than %v", player.ShipConfig.ShipWidth, width)
+	}
+
+	player.RespecStats(DefaultWorldConfig())
+	if player.ShipConfig.ShipWidth != width {
+		t.Errorf("width after respec = %v, want %v", player.ShipConfig.ShipWidth, width)
+	}
+}
+
+func TestTimersAreSentRelativeToTheSnapshot(t *testing.T) {
+	now := time.Now()
+	player := NewPlayer(1)
+	player.State = StateDead
+	player.DeathTime = now.Add(-500 * time.Millisecond)
+	player.DashReadyAt = now.Add(1500 * time.Millisecond)
+	player.updateTimers(now)
+
+	if !player.Sinking || player.SinkElapsed != 500 {
+		t.Errorf("sinking %v, elapsed %dms; want sinking for 500ms", player.Sinking, player.SinkElapsed)
+	}
+	if player.DashCooldown != 1500 {
+		t.Errorf("dash cooldown = %dms, want 1500", player.DashCooldown)
+	}
+
+	// Counting down doesn't produce a delta, restarting the dash does
+	later := *player
+	later.updateTimers(now.Add(100 * time.Millisecond))
+	if delta := calculatePlayerDeltas(player, &later, now); delta.SinkElapsed != nil || delta.DashCooldown != nil {
+		t.Error("timer countdown was sent as a delta")
+	}
+	later.DashReadyAt = now.Add(3 * time.Second)
+	if delta := calculatePlayerDeltas(player, &later, now); delta.DashCooldown == nil {
+		t.Error("restarted dash cooldown was not sent")
+	}
+}
+
+func TestRespecRefundSaturatesAtTheCoinCap(t *testing.T) {
+	config := DefaultWorldConfig()
+	config.MaxCoins = 1000
+	player := NewPlayer(1)
+	player.Coins = 990
+	upgrade := player.Upgrades[StatUpgradeMoveSpeed]
+	upgrade.Level = 5
+	player.Upgrades[StatUpgradeMoveSpeed] = upgrade
+
+	if refund := player.RespecStats(config); refund <= 10 {
+		t.Fatalf("refund = %d, want more than the room left under the cap", refund)
+	}
+	if player.Coins != config.MaxCoins {
+		t.Errorf("coins = %d after respec, want the cap %d", player.Coins, config.MaxCoins)
+	}
+}
+
+func TestHugeExperienceGrantStopsAtTheLevelCap(t *testing.T) {
+	w := newTestWorld(t, nil)
+	player := addTestClient(t, w, 1000, 1000).Player
+	maxLevel := w.config.MaxLevel
+
+	w.mu.Lock()
+	w.items[w.itemID] = &GameItem{ID: w.itemID, X: player.X, Y: player.Y, Type: ItemTypeCoinDrop, XP: MaxExperience}
+	w.itemID++
+	upgrades := player.AvailableUpgrades
+	w.mu.Unlock()
+
+	start := time.Now()
+	w.update()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("tick took %v after the grant", elapsed)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if player.Level != maxLevel || player.AvailableUpgrades != upgrades+maxLevel-1 {
+		t.Errorf("level %d with %d upgrades, want the cap %d with %d",
+			player.Level, player.AvailableUpgrades, maxLevel, upgrades+maxLevel-1)
+	}
+	prestigeCost := GetExperienceRequiredForLevel(maxLevel+1) - GetExperienceRequiredForLevel(maxLevel)
+	if player.Prestige == 0 || player.Experience >= GetExperienceRequiredForLevel(maxLevel)+prestigeCost {
+		t.Errorf("prestige %d with %d experience left; want the surplus banked as prestige", player.Prestige, player.Experience)
+	}
+}
+
+func TestRespecActionRefundsCoinsAndResetsModifiers(t *testing.T) {
+	w := newTestWorld(t, nil)
+	client := addTestClient(t, w, 1000, 1000)
+	stop := make(chan struct{})
+	defer close(stop)
+	go drainClient(client, stop)
+	player := client.Player
+
+	w.mu.Lock()
+	player.updateModifiers()
+	baseline, baseHealth := player.Modifiers, player.MaxHealth
+	player.Coins = 2000
+	for _, stat := range []UpgradeType{StatUpgradeHullStrength, StatUpgradeHullStrength, StatUpgradeMoveSpeed, StatUpgradeCannonDamage} {
+		if !player.BuyUpgrade(stat) {
+			w.mu.Unlock()
+			t.Fatalf("could not buy %s", stat)
+		}
+	}
+	spent := 2000 - player.Coins
+	w.mu.Unlock()
+
+	respec := func(sequence uint32) {
+		w.HandleInput(client.ID, InputMsg{Type: "input", Actions: []InputAction{{Type: "respecStats", Sequence: sequence}}})
+		w.update()
+	}
+	respec(1)
+
+	w.mu.Lock()
+	if want := 2000 - spent + int(float64(spent)*RespecRefundRate); player.Coins != want {
+		t.Errorf("coins after respec = %d, want %d", player.Coins, want)
+	}
+	if player.Modifiers != baseline {
+		t.Errorf("modifiers after respec = %+v, want baseline %+v", player.Modifiers, baseline)
+	}
+	if player.MaxHealth != baseHealth {
+		t.Errorf("max health after respec = %v, want %v", player.MaxHealth, baseHealth)
+	}
+	for stat, upgrade := range player.Upgrades {
+		if upgrade.Level != 0 {
+			t.Errorf("%s level after respec = %d, want 0", stat, upgrade.Level)
+		}
+	}
+
+	// A second respec inside the cooldown refunds nothing
+	if !player.BuyUpgrade(StatUpgradeMoveSpeed) {
+		w.mu.Unlock()
+		t.Fatal("could not buy move speed again")
+	}
+	coins := player.Coins
+	w.mu.Unlock()
+
+	respec(2)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if player.Coins != coins || player.Upgrades[StatUpgradeMoveSpeed].Level != 1 {
+		t.Errorf("respec inside the cooldown went through: coins %d, move speed level %d", player.Coins, player.Upgrades[StatUpgradeMoveSpeed].Level)
+	}
+}
+
+func TestDashBurstsPastMaxSpeedOnlyOffCooldownAndWhileAlive(t *testing.T) {
+	w := newTestWorld(t, nil)
+	client := addTestClient(t, w, 2000, 2000)
+	stop := make(chan struct{})
+	defer close(stop)
+	go drainClient(client, stop)
+	player := client.Player
+
+	w.mu.Lock()
+	player.Angle = 0
+	maxSpeed := BaseShipMaxSpeed * player.Modifiers.MoveSpeedMultiplier
+	w.mu.Unlock()
+
+	dash := func(sequence uint32) {
+		w.HandleInput(client.ID, InputMsg{Type: "input", Actions: []InputAction{{Type: "dash", Sequence: sequence}}})
+		w.update()
+	}
+
+	dash(1)
+	w.mu.Lock()
+	if player.VelX <= maxSpeed || math.Abs(player.VelY) > 1e-9 {
+		t.Errorf("velocity after a dash = (%v, %v), want forward faster than %v", player.VelX, player.VelY, maxSpeed)
+	}
+	if !player.DashReadyAt.After(time.Now()) {
+		t.Error("dash did not start its cooldown")
+	}
+	drift := player.DriftVelX
+	w.mu.Unlock()
+
+	// A second dash inside the cooldown adds nothing; the burst just fades
+	dash(2)
+	w.mu.Lock()
+	if player.DriftVelX >= drift {
+		t.Errorf("drift after a dash on cooldown = %v, want it fading below %v", player.DriftVelX, drift)
+	}
+
+	// Dead ships can't dash, even once the cooldown is over
+	player.State = StateDead
+	player.DriftVelX, player.DriftVelY = 0, 0
+	delete(player.ActionCooldowns, "dash")
+	player.DashReadyAt = time.Time{}
+	w.mu.Unlock()
+
+	dash(3)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if player.DriftVelX != 0 || !player.DashReadyAt.IsZero() {
+		t.Errorf("dead ship dashed: drift %v, ready at %v", player.DriftVelX, player.DashReadyAt)
+	}
+}
+
+func TestCrossingALevelThresholdSendsOneLevelUpEvent(t *testing.T) {
+	w := newTestWorld(t, nil)
+	client := addTestClient(t, w, 1000, 1000)
+	player := client.Player
+
+	w.mu.Lock()
+	xp := GetExperienceRequiredForLevel(player.Level+1) - player.Experience
+	item := &GameItem{ID: w.itemID, X: player.X, Y: player.Y, Type: ItemTypeBlueDiamond, XP: xp}
+	w.items[item.ID] = item
+	w.itemID++
+	w.mu.Unlock()
+	queuedMessages(client)
+
+	countLevelUps := func() (count int, last GameEventMsg) {
+		for _, event := range gameEvents(t, client) {
+			if event.EventType == "levelUp" {
+				count++
+				last = event
+			}
+		}
+		return count, last
+	}
+
+	w.update()
+	if count, event := countLevelUps(); count != 1 || event.Level != 2 || event.AvailableUpgrades != player.AvailableUpgrades {
+		t.Errorf("got %d level-up events (last %+v), want one for level 2", count, event)
+	}
+	w.update()
+	if count, _ := countLevelUps(); count != 0 {
+		t.Errorf("got %d more level-up events without gaining a level", count)
+	}
+}
@@ -0,0 +1,158 @@
+package game
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Recording frames are laid out as:
+//
+//	[8 bytes] big-endian unix milliseconds
+//	[4 bytes] big-endian payload length
+//	[n bytes] msgpack-encoded Snapshot
+const (
+	recorderBufferFrames = 256             // Frames queued before new ones are dropped
+	recorderFlushEvery   = 1 * time.Second // How often buffered frames are flushed to disk
+	maxRecordedFrameSize = 64 << 20        // Sanity limit when reading frames back
+)
+
+// Recorder appends one snapshot per tick to a file without blocking the game loop
+type Recorder struct {
+	file    *os.File
+	writer  *bufio.Writer
+	frames  chan recordedFrame
+	done    chan struct{}
+	dropped int64 // Frames dropped because the writer fell behind
+}
+
+type recordedFrame struct {
+	time     int64
+	snapshot Snapshot
+}
+
+// RecordedFrame is a single decoded frame read back from a recording
+type RecordedFrame struct {
+	Time     time.Time
+	Snapshot Snapshot
+}
+
+// NewRecorder creates (or truncates) the recording file and starts the writer goroutine
+func NewRecorder(path string) (*Recorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Recorder{
+		file:   file,
+		writer: bufio.NewWriter(file),
+		frames: make(chan recordedFrame, recorderBufferFrames),
+		done:   make(chan struct{}),
+	}
+	go r.run()
+
+	slog.Info("Recording snapshots", "path", path)
+	return r, nil
+}
+
+// Record queues a snapshot for writing; it never blocks and drops frames if
+// the disk falls behind. The snapshot is marshaled by the writer goroutine, so
+// it must not share mutable state with the world (see copyPlayer).
+func (r *Recorder) Record(snapshot Snapshot) {
+	select {
+	case r.frames <- recordedFrame{time: snapshot.Time, snapshot: snapshot}:
+	default:
+		atomic.AddInt64(&r.dropped, 1)
+	}
+}
+
+// Close flushes any queued frames and closes the file
+func (r *Recorder) Close() error {
+	close(r.frames)
+	<-r.done
+
+	if dropped := atomic.LoadInt64(&r.dropped); dropped > 0 {
+		slog.Warn("Recorder dropped frames", "frames", dropped)
+	}
+	return r.file.Close()
+}
+
+// run writes queued frames and flushes periodically
+func (r *Recorder) run() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(recorderFlushEvery)
+	defer ticker.Stop()
+
+	header := make([]byte, 12)
+	for {
+		select {
+		case frame, ok := <-r.frames:
+			if !ok {
+				if err := r.writer.Flush(); err != nil {
+					slog.Error("Error flushing recording", "err", err)
+				}
+				return
+			}
+
+			data, err := msgpack.Marshal(frame.snapshot)
+			if err != nil {
+				slog.Error("Error marshaling recorded snapshot", "err", err)
+				continue
+			}
+
+			binary.BigEndian.PutUint64(header[0:8], uint64(frame.time))
+			binary.BigEndian.PutUint32(header[8:12], uint32(len(data)))
+			if _, err := r.writer.Write(header); err != nil {
+				slog.Error("Error writing recording", "err", err)
+				continue
+			}
+			if _, err := r.writer.Write(data); err != nil {
+				slog.Error("Error writing recording", "err", err)
+			}
+
+		case <-ticker.C:
+			if err := r.writer.Flush(); err != nil {
+				slog.Error("Error flushing recording", "err", err)
+			}
+		}
+	}
+}
+
+// ReadRecordedFrame reads the next frame from a recording, returning io.EOF at the end
+func ReadRecordedFrame(reader io.Reader) (RecordedFrame, error) {
+	header := make([]byte, 12)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return RecordedFrame{}, fmt.Errorf("truncated frame header: %w", err)
+		}
+		return RecordedFrame{}, err
+	}
+
+	timestamp := int64(binary.BigEndian.Uint64(header[0:8]))
+	length := binary.BigEndian.Uint32(header[8:12])
+	if length > maxRecordedFrameSize {
+		return RecordedFrame{}, fmt.Errorf("frame too large: %d bytes", length)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(reader, data); err != nil {
+		return RecordedFrame{}, fmt.Errorf("truncated frame body: %w", err)
+	}
+
+	var snapshot Snapshot
+	if err := msgpack.Unmarshal(data, &snapshot); err != nil {
+		return RecordedFrame{}, fmt.Errorf("decoding frame: %w", err)
+	}
+
+	return RecordedFrame{Time: time.UnixMilli(timestamp), Snapshot: snapshot}, nil
+}
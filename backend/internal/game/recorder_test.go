@@ -0,0 +1,63 @@
+package game
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecorderWritesEveryTick(t *testing.T) {
+	w := newTestWorld(t, nil)
+	client := addTestClient(t, w, 500, 500)
+	stop := make(chan struct{})
+	defer close(stop)
+	go drainClient(client, stop)
+
+	path := filepath.Join(t.TempDir(), "match.rec")
+	recorder, err := NewRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.mu.Lock()
+	w.recorder = recorder
+	w.mu.Unlock()
+
+	const ticks = 20
+	for i := range ticks {
+		w.mu.Lock()
+		client.Input.Mouse.X = float64(100 + 10*i)
+		w.mu.Unlock()
+		w.update()
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	frames := 0
+	for {
+		frame, err := ReadRecordedFrame(reader)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("frame %d: %v", frames, err)
+		}
+		if len(frame.Snapshot.Players) != 1 || frame.Snapshot.Players[0].ID != client.ID {
+			t.Fatalf("frame %d players = %+v, want only player %d", frames, frame.Snapshot.Players, client.ID)
+		}
+		frames++
+	}
+	if frames != ticks {
+		t.Errorf("read %d frames, want %d", frames, ticks)
+	}
+}
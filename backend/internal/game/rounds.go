@@ -0,0 +1,121 @@
+package game
+
+import (
+	"cmp"
+	"log/slog"
+	"time"
+)
+
+// Reasons a round can end
+const (
+	RoundEndKills = "kills" // Someone reached the kill target
+	RoundEndTime  = "time"  // The time limit ran out
+)
+
+// roundState tracks the current round (guarded by w.mu)
+type roundState struct {
+	number    int       // Rounds started so far, starting at 1
+	startedAt time.Time // When the current round began
+}
+
+// roundsEnabled reports whether any round rule is configured
+func (config WorldConfig) roundsEnabled() bool {
+	return config.RoundKillTarget > 0 || config.RoundTimeLimit > 0
+}
+
+// updateRound ends the round once a ship reaches the kill target or the time
+// limit runs out; caller must hold w.mu
+func (w *World) updateRound(now time.Time) {
+	if !w.config.roundsEnabled() || w.config.Mode == ModeDuel {
+		return
+	}
+	if w.round.startedAt.IsZero() {
+		w.round = roundState{number: 1, startedAt: now}
+		return
+	}
+
+	if w.config.RoundKillTarget > 0 {
+		if leader := w.roundLeader(byKills, true); leader != nil && leader.Kills >= w.config.RoundKillTarget {
+			w.endRound(leader, RoundEndKills, now)
+			return
+		}
+	}
+
+	// Bots join with a seeded score, so only humans can win a round on score
+	if w.config.RoundTimeLimit > 0 && now.Sub(w.round.startedAt) >= w.config.RoundTimeLimit {
+		w.endRound(w.roundLeader(byScore, false), RoundEndTime, now)
+	}
+}
+
+// byKills ranks ships by kills, then by score
+func byKills(a, b *Player) int {
+	if a.Kills != b.Kills {
+		return cmp.Compare(a.Kills, b.Kills)
+	}
+	return cmp.Compare(a.Score, b.Score)
+}
+
+// byScore ranks ships by score, then by kills
+func byScore(a, b *Player) int {
+	if a.Score != b.Score {
+		return cmp.Compare(a.Score, b.Score)
+	}
+	return cmp.Compare(a.Kills, b.Kills)
+}
+
+// roundLeader returns the ship ranked highest by rank, breaking ties by
+// lowest ID so the result doesn't depend on map order
+func (w *World) roundLeader(rank func(a, b *Player) int, includeBots bool) *Player {
+	var leader *Player
+	for _, player := range w.players {
+		if player.IsBot && !includeBots {
+			continue
+		}
+		if leader == nil {
+			leader = player
+			continue
+		}
+		if order := rank(player, leader); order > 0 || (order == 0 && player.ID < leader.ID) {
+			leader = player
+		}
+	}
+	return leader
+}
+
+// endRound announces the winner (nil if the world was empty), then clears
+// kills and scores and scatters the living ships for the next round
+func (w *World) endRound(winner *Player, reason string, now time.Time) {
+	msg := RoundEndMsg{Type: MsgTypeRoundEnd, Round: w.round.number, Reason: reason}
+	if winner != nil {
+		msg.WinnerID = winner.ID
+		msg.WinnerName = winner.Name
+		msg.Kills = winner.Kills
+		msg.Score = winner.Score
+		slog.Info("Round over", "round", w.round.number, "reason", reason, "winner", winner.ID, "name", winner.Name, "kills", winner.Kills)
+	} else {
+		slog.Info("Round over without a winner", "round", w.round.number, "reason", reason)
+	}
+	for _, client := range w.clients {
+		client.sendRoundEnd(msg)
+	}
+
+	for _, player := range w.players {
+		player.Kills = 0
+		player.Score = 0
+		if player.State != StateAlive {
+			continue
+		}
+
+		spawnPos := w.chooseSafeSpawn(player)
+		player.X = spawnPos.X
+		player.Y = spawnPos.Y
+		player.VelX, player.VelY = 0, 0
+		player.DriftVelX, player.DriftVelY = 0, 0
+		player.Health = player.MaxHealth
+		player.MovementTracked = false
+		player.resetCampingState()
+		player.protectSpawn(now, w.config.SpawnProtection)
+	}
+
+	w.round = roundState{number: w.round.number + 1, startedAt: now}
+}
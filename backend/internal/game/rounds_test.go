@@ -0,0 +1,56 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeLimitRoundGoesToTopScore(t *testing.T) {
+	w := newTestWorld(t, func(config *WorldConfig) {
+		config.RoundTimeLimit = time.Minute
+		config.RoundKillTarget = 10
+	})
+	killer := addTestClient(t, w, 500, 500)
+	scorer := addTestClient(t, w, 1500, 500)
+	queuedMessages(killer)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	start := time.Now()
+	w.updateRound(start)
+	killer.Player.Kills, killer.Player.Score = 3, 100
+	scorer.Player.Kills, scorer.Player.Score = 1, 900
+
+	w.updateRound(start.Add(time.Minute))
+
+	var msg RoundEndMsg
+	found := false
+	for _, data := range queuedMessages(killer) {
+		if decodeTestMsg(data, &msg) && msg.Type == MsgTypeRoundEnd {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Fatal("no round end message")
+	}
+	if msg.Reason != RoundEndTime || msg.WinnerID != scorer.ID {
+		t.Errorf("round end = %+v, want %q won by %d", msg, RoundEndTime, scorer.ID)
+	}
+}
+
+func TestKillTargetRoundGoesToTopKiller(t *testing.T) {
+	w := newTestWorld(t, func(config *WorldConfig) {
+		config.RoundKillTarget = 3
+	})
+	killer := addTestClient(t, w, 500, 500)
+	scorer := addTestClient(t, w, 1500, 500)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	killer.Player.Kills, killer.Player.Score = 3, 100
+	scorer.Player.Kills, scorer.Player.Score = 1, 900
+	if leader := w.roundLeader(byKills, true); leader != killer.Player {
+		t.Errorf("kill leader = %d, want %d", leader.ID, killer.ID)
+	}
+}
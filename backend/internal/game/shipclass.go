@@ -0,0 +1,83 @@
+package game
+
+import "log/slog"
+
+// ShipClass names the starting hull a player picks before setting sail
+type ShipClass string
+
+const (
+	ShipClassSloop   ShipClass = "sloop"   // Balanced all-rounder (default)
+	ShipClassScout   ShipClass = "scout"   // Fast and nimble, but fragile
+	ShipClassGalleon ShipClass = "galleon" // Tough and better armed, but slow
+)
+
+// ShipClassStats are the base values a class starts from before stat upgrades
+type ShipClassStats struct {
+	BaseHealth          float64 // Max health with no hull strength upgrades
+	SpeedMultiplier     float64 // Base move speed multiplier
+	TurnSpeedMultiplier float64 // Base turn speed multiplier
+	SideCannons         int     // Cannons per side at spawn
+}
+
+// shipClasses is the catalog of selectable classes
+var shipClasses = map[ShipClass]ShipClassStats{
+	ShipClassSloop:   {BaseHealth: 100, SpeedMultiplier: 1.0, TurnSpeedMultiplier: 1.0, SideCannons: 1},
+	ShipClassScout:   {BaseHealth: 70, SpeedMultiplier: 1.25, TurnSpeedMultiplier: 1.2, SideCannons: 1},
+	ShipClassGalleon: {BaseHealth: 150, SpeedMultiplier: 0.8, TurnSpeedMultiplier: 0.85, SideCannons: 2},
+}
+
+// ParseShipClass validates a class requested by a client
+func ParseShipClass(name string) (ShipClass, bool) {
+	class := ShipClass(name)
+	_, known := shipClasses[class]
+	return class, known
+}
+
+// stats returns the class's base values; unknown or unset classes sail as a sloop
+func (class ShipClass) stats() ShipClassStats {
+	if stats, known := shipClasses[class]; known {
+		return stats
+	}
+	return shipClasses[ShipClassSloop]
+}
+
+// startingSideModule follows the basic side cannon chain from the upgrade tree
+// root until it reaches the requested cannon count, so later upgrades still apply
+func startingSideModule(root *ShipModule, cannonsPerSide int) *ShipModule {
+	module := root
+	for module.Count < cannonsPerSide {
+		next := module
+		for _, candidate := range module.NextUpgrades {
+			if candidate.Name == root.Name && candidate.Count == module.Count+1 {
+				next = candidate
+				break
+			}
+		}
+		if next == module {
+			break
+		}
+		module = next
+	}
+	return module
+}
+
+// selectShipClass records a requested class for the player's next spawn,
+// ignoring empty or unknown classes
+func (player *Player) selectShipClass(requested string) {
+	if requested == "" {
+		return
+	}
+	class, known := ParseShipClass(requested)
+	if !known {
+		slog.Debug("Player requested unknown ship class", "player", player.ID, "class", requested)
+		return
+	}
+	player.Class = class
+}
+
+// applyShipClass rebuilds the ship for the player's class and heals it to full
+func (player *Player) applyShipClass() {
+	player.resetPlayerShipConfig()
+	player.updateModifiers()
+	player.Health = player.MaxHealth
+}
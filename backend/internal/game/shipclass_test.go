@@ -0,0 +1,44 @@
+package game
+
+import "testing"
+
+func TestEachShipClassSetsSailWithItsBaseStats(t *testing.T) {
+	// setSail joins a fresh client and sets sail with the requested class
+	setSail := func(class string) *Player {
+		w := newTestWorld(t, nil)
+		client := NewClient(0, nil)
+		if !w.AddClient(client) {
+			t.Fatal("AddClient refused the client")
+		}
+		w.HandleInput(client.ID, InputMsg{Type: "startGame", StartGame: true, ShipClass: class})
+		w.mu.RLock()
+		defer w.mu.RUnlock()
+		if client.Player.State != StateAlive {
+			t.Fatalf("%q ship did not set sail", class)
+		}
+		return client.Player
+	}
+
+	speeds := make(map[ShipClass]float64)
+	for class, stats := range shipClasses {
+		player := setSail(string(class))
+		if player.Class != class {
+			t.Errorf("class after setting sail as %q = %q", class, player.Class)
+		}
+		if player.MaxHealth != stats.BaseHealth || player.Health != stats.BaseHealth {
+			t.Errorf("%s health %v/%v, want %v", class, player.Health, player.MaxHealth, stats.BaseHealth)
+		}
+		if got := player.ShipConfig.SideUpgrade.Count; got != stats.SideCannons {
+			t.Errorf("%s starts with %d cannons per side, want %d", class, got, stats.SideCannons)
+		}
+		speeds[class] = player.Modifiers.MoveSpeedMultiplier
+	}
+	if !(speeds[ShipClassScout] > speeds[ShipClassSloop] && speeds[ShipClassSloop] > speeds[ShipClassGalleon]) {
+		t.Errorf("speed multipliers %v, want scout > sloop > galleon", speeds)
+	}
+
+	// An unknown class is ignored and the ship sails as a sloop
+	if player := setSail("dreadnought"); player.Class == "dreadnought" || player.MaxHealth != shipClasses[ShipClassSloop].BaseHealth {
+		t.Errorf("unknown class gave class %q with %v health", player.Class, player.MaxHealth)
+	}
+}
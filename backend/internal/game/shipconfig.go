@@ -7,7 +7,8 @@ import (
 // ShipConfiguration holds all upgrades for a ship
 type ShipConfiguration struct {
 	SideUpgrade  *ShipModule `msgpack:"sideUpgrade"`  // Side cannons upgrade (single)
-	TopUpgrade   *ShipModule `msgpack:"topUpgrade"`   // Top turrets upgrade (single)
+	TopUpgrade   *ShipModule `msgpack:"topUpgrade"`   // Top turrets upgrade (primary slot)
+	ExtraTop     *ShipModule `msgpack:"extraTop"`     // Second turret slot, unlocked once the primary slot is filled
 	FrontUpgrade *ShipModule `msgpack:"frontUpgrade"` // Front weapons upgrade (single)
 	RearUpgrade  *ShipModule `msgpack:"rearUpgrade"`  // Rear weapons upgrade (single)
 	ShipLength   float64     `msgpack:"shipLength"`   // Calculated ship length based on upgrades
@@ -15,6 +16,53 @@ type ShipConfiguration struct {
 	Size         float64     `msgpack:"size"`         // Base size of the ship
 }
 
+// TopModules returns the modules mounted in the turret slots, primary first
+func (sc *ShipConfiguration) TopModules() []*ShipModule {
+	modules := make([]*ShipModule, 0, 2)
+	for _, module := range []*ShipModule{sc.TopUpgrade, sc.ExtraTop} {
+		if module != nil {
+			modules = append(modules, module)
+		}
+	}
+	return modules
+}
+
+// topTurrets returns the turrets from every turret slot in mounting order
+func (sc *ShipConfiguration) topTurrets() []*Turret {
+	var turrets []*Turret
+	for _, module := range sc.TopModules() {
+		turrets = append(turrets, module.Turrets...)
+	}
+	return turrets
+}
+
+// clone deep-copies every mounted module (see ShipModule.clone)
+func (sc ShipConfiguration) clone() ShipConfiguration {
+	sc.SideUpgrade = sc.SideUpgrade.clone()
+	sc.TopUpgrade = sc.TopUpgrade.clone()
+	sc.ExtraTop = sc.ExtraTop.clone()
+	sc.FrontUpgrade = sc.FrontUpgrade.clone()
+	sc.RearUpgrade = sc.RearUpgrade.clone()
+	return sc
+}
+
+// combinedTopModule merges the turret slots into one module so snapshots and
+// the client can keep treating the top of the ship as a single upgrade
+func (sc *ShipConfiguration) combinedTopModule() *ShipModule {
+	if sc.ExtraTop == nil {
+		return sc.TopUpgrade
+	}
+	name := sc.ExtraTop.Name
+	if sc.TopUpgrade != nil {
+		name = sc.TopUpgrade.Name + " + " + name
+	}
+	return &ShipModule{
+		Type:    UpgradeTypeTop,
+		Name:    name,
+		Turrets: sc.topTurrets(),
+	}
+}
+
 // GetTotalEffect calculates the combined effect of all upgrades
 func (sc *ShipConfiguration) GetTotalModuleEffects() ModuleModifier {
 	effect := ModuleModifier{
@@ -24,7 +72,7 @@ func (sc *ShipConfiguration) GetTotalModuleEffects() ModuleModifier {
 	}
 
 	// Collect all non-nil upgrades
-	upgrades := []*ShipModule{sc.SideUpgrade, sc.TopUpgrade, sc.FrontUpgrade, sc.RearUpgrade}
+	upgrades := []*ShipModule{sc.SideUpgrade, sc.TopUpgrade, sc.ExtraTop, sc.FrontUpgrade, sc.RearUpgrade}
 
 	for _, upgrade := range upgrades {
 		if upgrade != nil {
@@ -49,6 +97,8 @@ func (sc *ShipConfiguration) GetUpgrade(upgradeType moduleType) *ShipModule {
 		return sc.SideUpgrade
 	case UpgradeTypeTop:
 		return sc.TopUpgrade
+	case UpgradeTypeExtraTop:
+		return sc.ExtraTop
 	case UpgradeTypeFront:
 		return sc.FrontUpgrade
 	case UpgradeTypeRear:
@@ -88,65 +138,77 @@ func (sc *ShipConfiguration) UpdateUpgradePositions() {
 		}
 	}
 
-	topUpgrade := sc.TopUpgrade
-	if topUpgrade != nil && len(topUpgrade.Turrets) > 0 {
+	// Turrets from both slots share the center line so they never overlap
+	topTurrets := sc.topTurrets()
+	if len(topTurrets) > 0 {
 		// Position turrets evenly along the center line of the ship
 		// Use consistent spacing with the dimension calculation
-		turretSpacing := sc.ShipLength / float64(len(topUpgrade.Turrets))
+		turretSpacing := sc.ShipLength / float64(len(topTurrets))
 
-		if len(topUpgrade.Turrets) == 1 {
+		if len(topTurrets) == 1 {
 			// Single turret goes in the center
-			topUpgrade.Turrets[0].Position = Position{
+			topTurrets[0].Position = Position{
 				X: 0,
 				Y: 0,
 			}
-			for j := range topUpgrade.Turrets[0].Cannons {
-				topUpgrade.Turrets[0].Cannons[j].Position = Position{
-					X: 0,
-					Y: 0,
-				}
-			}
-
 		} else {
 			// Multiple turrets: space them evenly
-			totalTurretLength := turretSpacing * float64(len(topUpgrade.Turrets)-1)
+			totalTurretLength := turretSpacing * float64(len(topTurrets)-1)
 			startOffset := -totalTurretLength / 2
 
-			for i := 0; i < len(topUpgrade.Turrets); i++ {
+			for i := 0; i < len(topTurrets); i++ {
 				offset := startOffset + turretSpacing*float64(i)
-				topUpgrade.Turrets[i].Position = Position{
+				topTurrets[i].Position = Position{
 					X: offset,
 					Y: 0,
 				}
-				for j := range topUpgrade.Turrets[i].Cannons {
-					topUpgrade.Turrets[i].Cannons[j].Position = Position{
-						X: offset,
-						Y: 0,
-					}
-				}
-
 			}
 		}
 	}
 
 	frontUpgrade := sc.FrontUpgrade
 	if frontUpgrade != nil && len(frontUpgrade.Cannons) > 0 {
-		// position the 2 front cannons on the left and right sides of the front of the ship
-		gunWidth := sc.Size * 0.2
-		gunOffsetX := sc.ShipLength/2 + 10
-		// left cannon
-		frontUpgrade.Cannons[0].Position = Position{
-			X: gunOffsetX,
-			Y: sc.ShipWidth/2 - gunWidth/2,
-		}
-		frontUpgrade.Cannons[0].Angle = 0 // Facing forward
-		frontUpgrade.Cannons[1].Position = Position{
-			X: gunOffsetX,
-			Y: -sc.ShipWidth/2 + gunWidth/2,
+		// position front cannons across the bow, the outer two on the left and right edges
+		positionEndCannons(frontUpgrade.Cannons, sc.ShipLength/2+10, 0, sc.ShipWidth/2-sc.Size*0.1)
+	}
+
+	rearUpgrade := sc.RearUpgrade
+	if rearUpgrade != nil && len(rearUpgrade.Cannons) > 0 {
+		// rear cannons mirror the front layout and face backward
+		positionEndCannons(rearUpgrade.Cannons, -sc.ShipLength/2-10, math.Pi, sc.ShipWidth/2-sc.Size*0.1)
+	}
+
+	// Front and rear modules may also carry turrets; mount them near the bow and stern
+	if frontUpgrade != nil && len(frontUpgrade.Turrets) > 0 {
+		positionModuleTurrets(frontUpgrade.Turrets, sc.ShipLength/2-sc.Size*0.25, sc.ShipWidth)
+	}
+
+	if rearUpgrade != nil && len(rearUpgrade.Turrets) > 0 {
+		positionModuleTurrets(rearUpgrade.Turrets, -sc.ShipLength/2+sc.Size*0.25, sc.ShipWidth)
+	}
+}
+
+// positionEndCannons spreads bow or stern cannons evenly between +edgeY and -edgeY
+func positionEndCannons(cannons []*Cannon, offsetX, angle, edgeY float64) {
+	for i, cannon := range cannons {
+		y := 0.0
+		if len(cannons) > 1 {
+			y = edgeY * (1 - 2*float64(i)/float64(len(cannons)-1))
 		}
-		frontUpgrade.Cannons[1].Angle = 0 // Facing forward
+		cannon.Position = Position{X: offsetX, Y: y}
+		cannon.Angle = angle
 	}
+}
 
+// positionModuleTurrets spreads turrets across the ship's width at a fixed lengthwise offset
+func positionModuleTurrets(turrets []*Turret, offsetX, shipWidth float64) {
+	spacing := shipWidth / float64(len(turrets)+1)
+	for i, turret := range turrets {
+		turret.Position = Position{
+			X: offsetX,
+			Y: -shipWidth/2 + spacing*float64(i+1),
+		}
+	}
 }
 
 // CalculateShipDimensions calculates ship size based on upgrades
@@ -171,22 +233,27 @@ func (sc *ShipConfiguration) CalculateShipDimensions() {
 		sideLength += spacing * float64(maxSideCannonCount-1)
 	}
 
-	// Add length for turrets
+	// Add length for turrets; every turret past the first needs room for its
+	// own module's spacing, and the widest module sets the ship's width
 	turretCount := 0
-	if sc.TopUpgrade != nil {
-		turretCount = len(sc.TopUpgrade.Turrets)
-		sc.ShipWidth = baseWidth * sc.TopUpgrade.Effect.ShipWidthMultiplier
-	}
+	for i, module := range sc.TopModules() {
+		if i == 0 || baseWidth*module.Effect.ShipWidthMultiplier > sc.ShipWidth {
+			sc.ShipWidth = baseWidth * module.Effect.ShipWidthMultiplier
+		}
 
-	if turretCount > 0 {
 		turretSpacing := size * 0.6
-		if sc.TopUpgrade.Name == "Machine Gun Turret" {
+		if module.Name == "Machine Gun Turret" {
 			turretSpacing = size * 1
 		}
-		if sc.TopUpgrade.Name == "Big Turret" {
+		if module.Name == "Big Turret" {
 			turretSpacing = size * 1.5
 		}
-		turretLength = baseLength + turretSpacing*float64(turretCount-1)
+		for range module.Turrets {
+			if turretCount > 0 {
+				turretLength += turretSpacing
+			}
+			turretCount++
+		}
 	}
 
 	sc.ShipLength = max(sideLength, turretLength)
@@ -194,7 +261,7 @@ func (sc *ShipConfiguration) CalculateShipDimensions() {
 }
 
 // ToMinimalShipConfig converts a ShipConfiguration to MinimalShipConfig for delta snapshots
-func (sc *ShipConfiguration) ToMinimalShipConfig() ShipConfigDelta {
+func (sc *ShipConfiguration) ToMinimalShipConfig(clock reloadClock) ShipConfigDelta {
 	minimal := ShipConfigDelta{
 		ShipLength: sc.ShipLength,
 		ShipWidth:  sc.ShipWidth,
@@ -211,6 +278,10 @@ func (sc *ShipConfiguration) ToMinimalShipConfig() ShipConfigDelta {
 				Position:   cannon.Position,
 				Type:       string(cannon.Type),
 				RecoilTime: cannon.RecoilTime,
+				FireOrder:  cannon.FireOrder,
+
+				ReloadProgress: clock.cannon(cannon),
+				Ammo:           clock.ammo(cannon),
 			}
 		}
 	}
@@ -220,12 +291,17 @@ func (sc *ShipConfiguration) ToMinimalShipConfig() ShipConfigDelta {
 		minimal.FrontUpgrade = &ShipModuleDelta{
 			Name:    sc.FrontUpgrade.Name,
 			Cannons: make([]CannonDelta, len(sc.FrontUpgrade.Cannons)),
+			Turrets: toTurretDeltas(clock, sc.FrontUpgrade.Turrets),
 		}
 		for i, cannon := range sc.FrontUpgrade.Cannons {
 			minimal.FrontUpgrade.Cannons[i] = CannonDelta{
 				Position:   cannon.Position,
 				Type:       string(cannon.Type),
 				RecoilTime: cannon.RecoilTime,
+				FireOrder:  cannon.FireOrder,
+
+				ReloadProgress: clock.cannon(cannon),
+				Ammo:           clock.ammo(cannon),
 			}
 		}
 	}
@@ -233,33 +309,50 @@ func (sc *ShipConfiguration) ToMinimalShipConfig() ShipConfigDelta {
 	// Convert rear upgrade
 	if sc.RearUpgrade != nil {
 		minimal.RearUpgrade = &ShipModuleDelta{
-			Name: sc.RearUpgrade.Name,
+			Name:    sc.RearUpgrade.Name,
+			Turrets: toTurretDeltas(clock, sc.RearUpgrade.Turrets),
 		}
 	}
 
-	// Convert top upgrade (turrets)
-	if sc.TopUpgrade != nil {
+	// Convert top upgrades (turrets from every slot)
+	if top := sc.combinedTopModule(); top != nil {
 		minimal.TopUpgrade = &ShipModuleDelta{
-			Turrets: make([]TurretDelta, len(sc.TopUpgrade.Turrets)),
-		}
-		for i, turret := range sc.TopUpgrade.Turrets {
-			minimalTurret := TurretDelta{
-				Position:        turret.Position,
-				Angle:           turret.Angle,
-				Type:            string(turret.Type),
-				NextCannonIndex: turret.NextCannonIndex,
-				Cannons:         make([]CannonDelta, len(turret.Cannons)),
-			}
-			for j, cannon := range turret.Cannons {
-				minimalTurret.Cannons[j] = CannonDelta{
-					Position:   cannon.Position,
-					Type:       string(cannon.Type),
-					RecoilTime: cannon.RecoilTime,
-				}
-			}
-			minimal.TopUpgrade.Turrets[i] = minimalTurret
+			Turrets: toTurretDeltas(clock, top.Turrets),
 		}
 	}
 
 	return minimal
 }
+
+// toTurretDeltas converts turrets to the minimal form used for rendering
+func toTurretDeltas(clock reloadClock, turrets []*Turret) []TurretDelta {
+	if len(turrets) == 0 {
+		return nil
+	}
+
+	deltas := make([]TurretDelta, len(turrets))
+	for i, turret := range turrets {
+		minimalTurret := TurretDelta{
+			Position:        turret.Position,
+			Angle:           turret.Angle,
+			Type:            string(turret.Type),
+			NextCannonIndex: turret.NextCannonIndex,
+			Cannons:         make([]CannonDelta, len(turret.Cannons)),
+
+			ReloadProgress: clock.turret(turret),
+		}
+		for j, cannon := range turret.Cannons {
+			minimalTurret.Cannons[j] = CannonDelta{
+				Position:   cannon.Position,
+				Type:       string(cannon.Type),
+				RecoilTime: cannon.RecoilTime,
+				FireOrder:  cannon.FireOrder,
+
+				ReloadProgress: clock.cannon(&cannon),
+				Ammo:           clock.ammo(&cannon),
+			}
+		}
+		deltas[i] = minimalTurret
+	}
+	return deltas
+}
@@ -0,0 +1,75 @@
+package game
+
+import "testing"
+
+func TestFrontAndRearModuleTurretsAreMounted(t *testing.T) {
+	sc := NewPlayer(1).ShipConfig
+	front := NewChaseCannonUpgrade()
+	front.Turrets = NewBasicTurrets(2).Turrets
+	rear := NewRudderUpgrade()
+	rear.Turrets = NewBasicTurrets(1).Turrets
+	for _, turret := range append(front.Turrets, rear.Turrets...) {
+		turret.Position = Position{X: 999, Y: 999}
+	}
+	sc.FrontUpgrade = front
+	sc.RearUpgrade = rear
+	sc.CalculateShipDimensions()
+	sc.UpdateUpgradePositions()
+
+	for i, turret := range front.Turrets {
+		if turret.Position.X <= 0 || turret.Position.X > sc.ShipLength/2 || turret.Position.Y == 999 {
+			t.Errorf("front turret %d at %+v, want on the bow half of a %v long hull", i, turret.Position, sc.ShipLength)
+		}
+	}
+	if front.Turrets[0].Position.Y == front.Turrets[1].Position.Y {
+		t.Error("front turrets share a mount")
+	}
+	if pos := rear.Turrets[0].Position; pos.X >= 0 || pos.X < -sc.ShipLength/2 || pos.Y != 0 {
+		t.Errorf("rear turret at %+v, want centered on the stern half", pos)
+	}
+	for i, cannon := range front.Cannons {
+		if cannon.Position.X <= sc.ShipLength/2 {
+			t.Errorf("chase cannon %d at %+v, want past the bow", i, cannon.Position)
+		}
+	}
+}
+
+func TestBothTurretSlotsFireFromSeparateMounts(t *testing.T) {
+	w := newTestWorld(t, nil)
+	client := addTestClient(t, w, 2000, 2000)
+	stop := make(chan struct{})
+	defer close(stop)
+	go drainClient(client, stop)
+
+	w.mu.Lock()
+	sc := &client.Player.ShipConfig
+	if !sc.ApplyModule(UpgradeTypeTop, NewBasicTurrets(1).Name) {
+		w.mu.Unlock()
+		t.Fatal("could not fit a basic turret in the first slot")
+	}
+	if !sc.ApplyModule(UpgradeTypeExtraTop, NewMachineGunTurret(1).Name) {
+		w.mu.Unlock()
+		t.Fatal("could not fit a machine gun turret in the second slot")
+	}
+	sc.CalculateShipDimensions()
+	sc.UpdateUpgradePositions()
+	client.Player.AutofireEnabled = true
+	client.Input.Mouse.X, client.Input.Mouse.Y = 2300, 2000
+	w.mu.Unlock()
+
+	for range DefaultTickRate {
+		w.update()
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	basic, machineGun := sc.TopUpgrade.Turrets[0], sc.ExtraTop.Turrets[0]
+	if basic.Position == machineGun.Position {
+		t.Errorf("both turrets are mounted at %v", basic.Position)
+	}
+	for _, turret := range []*Turret{basic, machineGun} {
+		if turret.LastFireTime.IsZero() {
+			t.Errorf("%v turret never fired", turret.Type)
+		}
+	}
+}
@@ -1,14 +1,17 @@
 package game
 
 import (
-	"log"
+	"log/slog"
+	"math"
+	"slices"
 	"sync/atomic"
 	"time"
 
 	"github.com/vmihailenco/msgpack/v5"
 )
 
-// calculateItemDeltas compares current items with client's last snapshot to find added/removed items
+// calculateItemDeltas compares current items with client's last snapshot to find added/removed items.
+// Items that moved (pulled by a magnet) are resent as added so the client replaces them.
 func (w *World) calculateItemDeltas(currentItems []GameItem, lastSnapshot Snapshot) ([]GameItem, []uint32) {
 	// Create maps for efficient lookup
 	lastItemMap := make(map[uint32]GameItem)
@@ -24,9 +27,9 @@ func (w *World) calculateItemDeltas(currentItems []GameItem, lastSnapshot Snapsh
 	var itemsAdded []GameItem
 	var itemsRemoved []uint32
 
-	// Find added items (in current but not in last)
+	// Find added items (in current but not in last) and items that moved
 	for _, item := range currentItems {
-		if _, exists := lastItemMap[item.ID]; !exists {
+		if lastItem, exists := lastItemMap[item.ID]; !exists || lastItem.X != item.X || lastItem.Y != item.Y {
 			itemsAdded = append(itemsAdded, item)
 		}
 	}
@@ -79,25 +82,26 @@ func (w *World) GetSnapshotStats() (count int64, totalSize int64) {
 	return atomic.LoadInt64(&w.snapshotCount), atomic.LoadInt64(&w.totalSnapshotSize)
 }
 
-// getBulletsInRange returns bullets within visible range of a player
-func (w *World) getBulletsInRange(player *Player) []Bullet {
+// bulletsInRange returns the bullets within a viewer's vision range.
+// It only reads the given copy, so it is safe to call without holding w.mu.
+func bulletsInRange(allBullets []Bullet, viewX, viewY, visionRange float64) []Bullet {
 	bullets := make([]Bullet, 0, 50) // Pre-allocate reasonable capacity
 	maxBullets := 200                // Limit bullets per client to prevent overload
 
 	bulletCount := 0
-	for _, bullet := range w.bullets {
+	for _, bullet := range allBullets {
 		if bulletCount >= maxBullets {
 			break
 		}
 
 		// Calculate distance squared (avoid sqrt for performance)
-		dx := bullet.X - player.X
-		dy := bullet.Y - player.Y
+		dx := bullet.X - viewX
+		dy := bullet.Y - viewY
 		distSq := dx*dx + dy*dy
 
 		// Include bullet if within visible range
-		if distSq <= BulletVisibleRange*BulletVisibleRange {
-			bullets = append(bullets, *bullet)
+		if distSq <= visionRange*visionRange {
+			bullets = append(bullets, bullet)
 			bulletCount++
 		}
 	}
@@ -105,23 +109,150 @@ func (w *World) getBulletsInRange(player *Player) []Bullet {
 	return bullets
 }
 
+// damageEventsInRange returns the hits within the viewer's vision range,
+// capped at MaxDamageEventsPerClient
+func damageEventsInRange(events []DamageEvent, viewX, viewY, visionRange float64) []DamageEvent {
+	var visible []DamageEvent
+	for _, event := range events {
+		if len(visible) >= MaxDamageEventsPerClient {
+			break
+		}
+		dx := event.X - viewX
+		dy := event.Y - viewY
+		if dx*dx+dy*dy <= visionRange*visionRange {
+			visible = append(visible, event)
+		}
+	}
+	return visible
+}
+
+// detailTier is how much of a player's state a viewer is sent
+type detailTier int
+
+const (
+	detailFull    detailTier = iota // Everything, for the viewer and nearby ships
+	detailReduced                   // Coarse position, heavy fields frozen, for distant ships
+)
+
+// playerDetailTier picks the level of detail for a player as seen from a viewer position
+func playerDetailTier(player *Player, viewerID uint32, viewX, viewY float64) detailTier {
+	if player.ID == viewerID {
+		return detailFull
+	}
+	dx := player.X - viewX
+	dy := player.Y - viewY
+	if dx*dx+dy*dy > LODFullDetailRange*LODFullDetailRange {
+		return detailReduced
+	}
+	return detailFull
+}
+
+// playersForViewer returns the players one client is sent. Other players' wrecks are
+// dropped once they finish sinking, ships beyond fogRange are hidden (0 = no fog), and
+// distant ships are reduced: their DebugInfo and Upgrades stay at whatever this viewer
+// was last sent, so no delta is produced until the ship comes back into full detail.
+func playersForViewer(players []Player, lastPlayers []Player, viewerID uint32, viewX, viewY, fogRange float64) []Player {
+	lastByID := make(map[uint32]*Player, len(lastPlayers))
+	for i := range lastPlayers {
+		lastByID[lastPlayers[i].ID] = &lastPlayers[i]
+	}
+
+	detailed := make([]Player, 0, len(players))
+	for _, player := range players {
+		if player.State == StateDead && !player.Sinking && player.ID != viewerID {
+			continue
+		}
+		if fogRange > 0 && player.ID != viewerID {
+			dx := player.X - viewX
+			dy := player.Y - viewY
+			if dx*dx+dy*dy > fogRange*fogRange {
+				continue
+			}
+		}
+		if playerDetailTier(&player, viewerID, viewX, viewY) == detailReduced {
+			player.X = math.Round(player.X/LODPositionStep) * LODPositionStep
+			player.Y = math.Round(player.Y/LODPositionStep) * LODPositionStep
+			if last, exists := lastByID[player.ID]; exists {
+				player.DebugInfo = last.DebugInfo
+				player.Upgrades = last.Upgrades
+			} else {
+				player.DebugInfo = DebugInfo{}
+				player.Upgrades = nil
+			}
+		}
+		detailed = append(detailed, player)
+	}
+	return detailed
+}
+
+// getOffscreenEnemyBearings returns the bearing from the player to each living enemy
+// that is too far away to be on screen but still close enough to matter; with fog of
+// war, enemies the player can't see aren't reported
+func (w *World) getOffscreenEnemyBearings(player *Player) []float64 {
+	if player.State != StateAlive {
+		return nil
+	}
+
+	maxRange := OffscreenIndicatorMaxRange
+	if w.config.FogOfWar {
+		maxRange = min(maxRange, player.visionRange())
+	}
+
+	var bearings []float64
+	for _, other := range w.players {
+		if other.ID == player.ID || other.State != StateAlive {
+			continue
+		}
+
+		dx := other.X - player.X
+		dy := other.Y - player.Y
+		distSq := dx*dx + dy*dy
+		if distSq <= OffscreenIndicatorMinRange*OffscreenIndicatorMinRange ||
+			distSq > maxRange*maxRange {
+			continue
+		}
+
+		bearings = append(bearings, math.Atan2(dy, dx))
+	}
+
+	return bearings
+}
+
+// viewPosition returns the point a client's snapshot is centered on and how far
+// it sees: the client's own ship, or its killer's while dead and spectating
+func (w *World) viewPosition(client *Client) (float64, float64, float64) {
+	player := client.Player
+	if client.SpectateKiller && player.State == StateDead && player.KilledBy != 0 {
+		if killer, ok := w.players[player.KilledBy]; ok && killer.State == StateAlive {
+			return killer.X, killer.Y, killer.visionRange()
+		}
+	}
+	return player.X, player.Y, player.visionRange()
+}
+
 // broadcastSnapshot sends the current game state to all clients (optimized)
 func (w *World) broadcastSnapshot() {
 	// Limit data to reduce bandwidth
 	maxItems := MaxItems * 2
+	now := time.Now()
 
 	currentSnapshot := Snapshot{
 		Type:    MsgTypeSnapshot,
 		Players: make([]Player, 0, len(w.players)),
 		Items:   make([]GameItem, 0, min(len(w.items), maxItems)),
 		Bullets: []Bullet{},
-		Time:    time.Now().UnixMilli(),
+		Time:    now.UnixMilli(),
+	}
+	if w.zone != nil {
+		zone := *w.zone
+		currentSnapshot.Zone = &zone
 	}
 
 	// Add all players to snapshot
 	for _, player := range w.players {
 		// Calculate debug info for this player
 		player.DebugInfo = w.calculateDebugInfo(player)
+		player.updateTimers(now)
 		currentSnapshot.Players = append(currentSnapshot.Players, copyPlayer(*player))
 	}
 
@@ -135,52 +266,82 @@ func (w *World) broadcastSnapshot() {
 		itemCount++
 	}
 
+	// Copy every bullet while the world lock is held; the per-client goroutines
+	// below filter this copy instead of reading the live world maps
+	allBullets := make([]Bullet, 0, len(w.bullets))
+	for _, bullet := range w.bullets {
+		allBullets = append(allBullets, *bullet)
+	}
+
+	// Hand this tick's hits to the snapshot and start collecting afresh
+	damageEvents := w.damageEvents
+	w.damageEvents = nil
+
+	// Record the full tick once (all bullets, not just a client's view)
+	if w.recorder != nil {
+		recordedSnapshot := currentSnapshot
+		recordedSnapshot.Bullets = allBullets
+		w.recorder.Record(recordedSnapshot)
+	}
+
 	// Send to all clients concurrently (non-blocking)
 	for _, client := range w.clients {
-		go func(c *Client) {
-			defer func() {
-				if r := recover(); r != nil {
-					// Client disconnected, channel closed - ignore
-				}
-			}()
+		// Bearings and the viewer position are read here while the world lock is held
+		var offscreenEnemies []float64
+		if OffscreenIndicatorsEnabled && client.OffscreenIndicators {
+			offscreenEnemies = w.getOffscreenEnemyBearings(client.Player)
+		}
+		viewX, viewY, visionRange := w.viewPosition(client)
+		fogRange := 0.0
+		if w.config.FogOfWar {
+			fogRange = visionRange
+		}
+
+		go func(c *Client, offscreenEnemies []float64, viewX, viewY, visionRange, fogRange float64) {
+			defer c.RecoverPanic("snapshot")
 
 			var data []byte
 			var err error
 
 			c.mu.RLock()
-			isFirstSnapshot := c.lastSnapshot.Time == 0
+			lastSnapshot := c.lastSnapshot
 			c.mu.RUnlock()
+			isFirstSnapshot := lastSnapshot.Time == 0
 
-			// Create client-specific snapshot with filtered bullets
+			// Create client-specific snapshot with filtered bullets, sunk wrecks dropped, and distant ships reduced
 			clientSnapshot := currentSnapshot
-			clientSnapshot.Bullets = w.getBulletsInRange(c.Player)
+			clientSnapshot.Players = playersForViewer(currentSnapshot.Players, lastSnapshot.Players, c.ID, viewX, viewY, fogRange)
+			clientSnapshot.Bullets = bulletsInRange(allBullets, viewX, viewY, visionRange)
+			clientSnapshot.DamageEvents = damageEventsInRange(damageEvents, viewX, viewY, visionRange)
 
 			if isFirstSnapshot {
 				// First snapshot for this client - send full snapshot
 				data, err = msgpack.Marshal(clientSnapshot)
 				if err != nil {
-					log.Printf("Error marshaling snapshot for client %d: %v", c.ID, err)
+					slog.Error("Error marshaling snapshot", "client", c.ID, "err", err)
 					return
 				}
 			} else {
 				// Calculate delta changes for items based on client's last snapshot
-				c.mu.RLock()
-				itemsAdded, itemsRemoved := w.calculateItemDeltas(clientSnapshot.Items, c.lastSnapshot)
-				bulletsAdded, bulletsRemoved := w.calculateBulletDeltas(clientSnapshot.Bullets, c.lastSnapshot)
-				c.mu.RUnlock()
+				itemsAdded, itemsRemoved := w.calculateItemDeltas(clientSnapshot.Items, lastSnapshot)
+				bulletsAdded, bulletsRemoved := w.calculateBulletDeltas(clientSnapshot.Bullets, lastSnapshot)
 
 				// Calculate player deltas based on client's last snapshot
 				var playerDeltas []PlayerDelta
 				lastPlayerMap := make(map[uint32]*Player)
 				currentPlayerMap := make(map[uint32]bool)
-				for i := range c.lastSnapshot.Players {
-					lastPlayerMap[c.lastSnapshot.Players[i].ID] = &c.lastSnapshot.Players[i]
+				for i := range lastSnapshot.Players {
+					lastPlayerMap[lastSnapshot.Players[i].ID] = &lastSnapshot.Players[i]
 				}
 
 				for _, currentPlayer := range clientSnapshot.Players {
 					currentPlayerMap[currentPlayer.ID] = true
 					if lastPlayer, exists := lastPlayerMap[currentPlayer.ID]; exists {
-						delta := calculatePlayerDeltas(lastPlayer, &currentPlayer)
+						delta := calculatePlayerDeltas(lastPlayer, &currentPlayer, now)
+						// Only the owner is told which of its actions were processed
+						if currentPlayer.ID == c.ID && lastPlayer.LastProcessedAction != currentPlayer.LastProcessedAction {
+							delta.LastActionSeq = &currentPlayer.LastProcessedAction
+						}
 						// Only include deltas that have changes (at least one field changed)
 						if hasPlayerChanges(delta) {
 							playerDeltas = append(playerDeltas, delta)
@@ -198,19 +359,37 @@ func (w *World) broadcastSnapshot() {
 							State:             &currentPlayer.State,
 							Name:              &currentPlayer.Name,
 							Color:             &currentPlayer.Color,
+							Flag:              &currentPlayer.Flag,
+							Team:              &currentPlayer.Team,
 							Health:            &currentPlayer.Health,
 							MaxHealth:         &currentPlayer.MaxHealth,
 							Level:             &currentPlayer.Level,
 							Experience:        &currentPlayer.Experience,
 							AvailableUpgrades: &currentPlayer.AvailableUpgrades,
-							ShipConfig:        currentPlayer.ShipConfig.ToMinimalShipConfig(),
+							ShipConfig:        currentPlayer.ShipConfig.ToMinimalShipConfig(reloadClock{player: &currentPlayer, now: now}),
 							Coins:             &currentPlayer.Coins,
 							Upgrades:          &currentPlayer.Upgrades,
 							AutofireEnabled:   &currentPlayer.AutofireEnabled,
+							AimedAutofire:     &currentPlayer.AimedAutofire,
 							DebugInfo:         &currentPlayer.DebugInfo,
 							ScoreAtDeath:      &currentPlayer.ScoreAtDeath,
 							SurvivalTime:      &currentPlayer.SurvivalTime,
+							KilledBy:          &currentPlayer.KilledBy,
 							KilledByName:      &currentPlayer.KilledByName,
+							Burning:           &currentPlayer.Burning,
+							TractorTargetID:   &currentPlayer.TractorTargetID,
+							Protected:         &currentPlayer.Protected,
+							LastInputSequence: &currentPlayer.LastInputSequence,
+							Prestige:          &currentPlayer.Prestige,
+							Kills:             &currentPlayer.Kills,
+							IsBounty:          &currentPlayer.IsBounty,
+							ActiveBuffs:       &currentPlayer.ActiveBuffs,
+							Sinking:           &currentPlayer.Sinking,
+							SinkElapsed:       &currentPlayer.SinkElapsed,
+							DashCooldown:      &currentPlayer.DashCooldown,
+						}
+						if currentPlayer.ID == c.ID {
+							delta.LastActionSeq = &currentPlayer.LastProcessedAction
 						}
 						playerDeltas = append(playerDeltas, delta)
 					}
@@ -226,18 +405,21 @@ func (w *World) broadcastSnapshot() {
 
 				// Create delta snapshot
 				deltaSnapshot := DeltaSnapshot{
-					Type:           MsgTypeDeltaSnapshot,
-					Players:        playerDeltas,
-					PlayersRemoved: playersRemoved,
-					ItemsAdded:     itemsAdded,
-					ItemsRemoved:   itemsRemoved,
-					BulletsAdded:   bulletsAdded,
-					BulletsRemoved: bulletsRemoved,
+					Type:             MsgTypeDeltaSnapshot,
+					Players:          playerDeltas,
+					PlayersRemoved:   playersRemoved,
+					ItemsAdded:       itemsAdded,
+					ItemsRemoved:     itemsRemoved,
+					BulletsAdded:     bulletsAdded,
+					BulletsRemoved:   bulletsRemoved,
+					OffscreenEnemies: offscreenEnemies,
+					Zone:             clientSnapshot.Zone,
+					DamageEvents:     clientSnapshot.DamageEvents,
 				}
 
 				data, err = msgpack.Marshal(deltaSnapshot)
 				if err != nil {
-					log.Printf("Error marshaling delta snapshot for client %d: %v", c.ID, err)
+					slog.Error("Error marshaling delta snapshot", "client", c.ID, "err", err)
 					return
 				}
 			}
@@ -247,20 +429,17 @@ func (w *World) broadcastSnapshot() {
 			c.lastSnapshot = clientSnapshot
 			c.mu.Unlock()
 
-			// Send to client
-			select {
-			case c.Send <- data:
+			// Send to client, dropping the frame if its buffer is full
+			if c.queueSnapshot(data, w.config.maxDroppedFrames()) {
 				// Track snapshot size
 				atomic.AddInt64(&w.snapshotCount, 1)
 				atomic.AddInt64(&w.totalSnapshotSize, int64(len(data)))
-			case <-time.After(10 * time.Millisecond):
-				// Skip slow clients to prevent blocking
 			}
-		}(client)
+		}(client, offscreenEnemies, viewX, viewY, visionRange, fogRange)
 	}
 }
 
-func calculateShipConfigDeltas(oldConfig, newConfig *ShipConfiguration) ShipConfigDelta {
+func calculateShipConfigDeltas(oldConfig, newConfig *ShipConfiguration, clock reloadClock) ShipConfigDelta {
 	delta := ShipConfigDelta{}
 
 	if oldConfig.ShipLength != newConfig.ShipLength {
@@ -271,21 +450,21 @@ func calculateShipConfigDeltas(oldConfig, newConfig *ShipConfiguration) ShipConf
 	}
 
 	// Compare side upgrade
-	delta.SideUpgrade = calculateShipModuleDelta(oldConfig.SideUpgrade, newConfig.SideUpgrade)
+	delta.SideUpgrade = calculateShipModuleDelta(oldConfig.SideUpgrade, newConfig.SideUpgrade, clock)
 
 	// Compare front upgrade
-	delta.FrontUpgrade = calculateShipModuleDelta(oldConfig.FrontUpgrade, newConfig.FrontUpgrade)
+	delta.FrontUpgrade = calculateShipModuleDelta(oldConfig.FrontUpgrade, newConfig.FrontUpgrade, clock)
 
 	// Compare rear upgrade
-	delta.RearUpgrade = calculateShipModuleDelta(oldConfig.RearUpgrade, newConfig.RearUpgrade)
+	delta.RearUpgrade = calculateShipModuleDelta(oldConfig.RearUpgrade, newConfig.RearUpgrade, clock)
 
-	// Compare top upgrade (turrets)
-	delta.TopUpgrade = calculateShipModuleDelta(oldConfig.TopUpgrade, newConfig.TopUpgrade)
+	// Compare top upgrades (turrets from every slot)
+	delta.TopUpgrade = calculateShipModuleDelta(oldConfig.combinedTopModule(), newConfig.combinedTopModule(), clock)
 
 	return delta
 }
 
-func calculateShipModuleDelta(oldModule, newModule *ShipModule) *ShipModuleDelta {
+func calculateShipModuleDelta(oldModule, newModule *ShipModule, clock reloadClock) *ShipModuleDelta {
 	if oldModule == nil && newModule == nil {
 		return nil
 	}
@@ -296,10 +475,10 @@ func calculateShipModuleDelta(oldModule, newModule *ShipModule) *ShipModuleDelta
 	}
 
 	// Compare cannons
-	delta.Cannons = calculateCannonDeltas(oldModule.Cannons, newModule.Cannons)
+	delta.Cannons = calculateCannonDeltas(oldModule.Cannons, newModule.Cannons, clock)
 
 	// compare turrets
-	delta.Turrets = calculateTurretDeltas(newModule.Turrets)
+	delta.Turrets = calculateTurretDeltas(newModule.Turrets, clock)
 
 	// Return nil if no changes were detected
 	if delta.Name == "" && len(delta.Cannons) == 0 && len(delta.Turrets) == 0 {
@@ -309,7 +488,7 @@ func calculateShipModuleDelta(oldModule, newModule *ShipModule) *ShipModuleDelta
 	return delta
 }
 
-func calculateTurretDeltas(newTurrets []*Turret) []TurretDelta {
+func calculateTurretDeltas(newTurrets []*Turret, clock reloadClock) []TurretDelta {
 	delta := []TurretDelta{}
 	for _, turret := range newTurrets {
 		// Convert []Cannon to []*Cannon
@@ -322,14 +501,16 @@ func calculateTurretDeltas(newTurrets []*Turret) []TurretDelta {
 			Angle:           turret.Angle,
 			Type:            string(turret.Type),
 			NextCannonIndex: turret.NextCannonIndex,
-			Cannons:         calculateCannonDeltas(nil, cannonPtrs),
+			Cannons:         calculateCannonDeltas(nil, cannonPtrs, clock),
+
+			ReloadProgress: clock.turret(turret),
 		}
 		delta = append(delta, turretDelta)
 	}
 	return delta
 }
 
-func calculateCannonDeltas(oldCannons, newCannons []*Cannon) []CannonDelta {
+func calculateCannonDeltas(oldCannons, newCannons []*Cannon, clock reloadClock) []CannonDelta {
 	if len(oldCannons) != len(newCannons) {
 		deltas := make([]CannonDelta, len(newCannons))
 		for i, cannon := range newCannons {
@@ -337,6 +518,10 @@ func calculateCannonDeltas(oldCannons, newCannons []*Cannon) []CannonDelta {
 				Position:   cannon.Position,
 				Type:       string(cannon.Type),
 				RecoilTime: cannon.RecoilTime,
+				FireOrder:  cannon.FireOrder,
+
+				ReloadProgress: clock.cannon(cannon),
+				Ammo:           clock.ammo(cannon),
 			}
 		}
 		return deltas
@@ -351,6 +536,10 @@ func calculateCannonDeltas(oldCannons, newCannons []*Cannon) []CannonDelta {
 				Position:   newCannon.Position,
 				Type:       string(newCannon.Type),
 				RecoilTime: newCannon.RecoilTime,
+				FireOrder:  newCannon.FireOrder,
+
+				ReloadProgress: clock.cannon(newCannon),
+				Ammo:           clock.ammo(newCannon),
 			}
 			deltas = append(deltas, delta)
 		}
@@ -358,8 +547,31 @@ func calculateCannonDeltas(oldCannons, newCannons []*Cannon) []CannonDelta {
 	return deltas
 }
 
+// reloadClock measures weapon reload progress for one player at snapshot time
+type reloadClock struct {
+	player *Player
+	now    time.Time
+}
+
+func (clock reloadClock) cannon(cannon *Cannon) float64 {
+	return cannon.ReloadProgress(clock.player, clock.now)
+}
+
+// ammo returns the cannon's magazine count, or nil when it has no magazine
+func (clock reloadClock) ammo(cannon *Cannon) *int {
+	if cannon.Stats.MagazineSize <= 0 {
+		return nil
+	}
+	ammo := cannon.Ammo(clock.now)
+	return &ammo
+}
+
+func (clock reloadClock) turret(turret *Turret) float64 {
+	return turret.ReloadProgress(clock.player, clock.now)
+}
+
 // calculatePlayerDeltas compares two players and returns only the changed fields
-func calculatePlayerDeltas(oldPlayer, newPlayer *Player) PlayerDelta {
+func calculatePlayerDeltas(oldPlayer, newPlayer *Player, now time.Time) PlayerDelta {
 	delta := PlayerDelta{
 		ID: newPlayer.ID, // Always include ID
 	}
@@ -396,6 +608,12 @@ func calculatePlayerDeltas(oldPlayer, newPlayer *Player) PlayerDelta {
 	if oldPlayer.Color != newPlayer.Color {
 		delta.Color = &newPlayer.Color
 	}
+	if oldPlayer.Flag != newPlayer.Flag {
+		delta.Flag = &newPlayer.Flag
+	}
+	if oldPlayer.Team != newPlayer.Team {
+		delta.Team = &newPlayer.Team
+	}
 
 	// Compare health (changes frequently)
 	if oldPlayer.Health != newPlayer.Health {
@@ -435,16 +653,63 @@ func calculatePlayerDeltas(oldPlayer, newPlayer *Player) PlayerDelta {
 		delta.SurvivalTime = &newPlayer.SurvivalTime
 	}
 
+	if oldPlayer.KilledBy != newPlayer.KilledBy {
+		delta.KilledBy = &newPlayer.KilledBy
+	}
+
 	if oldPlayer.KilledByName != newPlayer.KilledByName {
 		delta.KilledByName = &newPlayer.KilledByName
 	}
 
-	delta.ShipConfig = calculateShipConfigDeltas(&oldPlayer.ShipConfig, &newPlayer.ShipConfig)
+	if oldPlayer.Burning != newPlayer.Burning {
+		delta.Burning = &newPlayer.Burning
+	}
+
+	if oldPlayer.TractorTargetID != newPlayer.TractorTargetID {
+		delta.TractorTargetID = &newPlayer.TractorTargetID
+	}
+
+	if oldPlayer.Protected != newPlayer.Protected {
+		delta.Protected = &newPlayer.Protected
+	}
+
+	if oldPlayer.LastInputSequence != newPlayer.LastInputSequence {
+		delta.LastInputSequence = &newPlayer.LastInputSequence
+	}
+
+	if oldPlayer.Prestige != newPlayer.Prestige {
+		delta.Prestige = &newPlayer.Prestige
+	}
+	if oldPlayer.Kills != newPlayer.Kills {
+		delta.Kills = &newPlayer.Kills
+	}
+	if oldPlayer.IsBounty != newPlayer.IsBounty {
+		delta.IsBounty = &newPlayer.IsBounty
+	}
+
+	if !slices.Equal(oldPlayer.ActiveBuffs, newPlayer.ActiveBuffs) {
+		delta.ActiveBuffs = &newPlayer.ActiveBuffs
+	}
+	if oldPlayer.Sinking != newPlayer.Sinking {
+		delta.Sinking = &newPlayer.Sinking
+	}
+	// The timers count down every tick; only resend them when they restart
+	if !oldPlayer.DeathTime.Equal(newPlayer.DeathTime) {
+		delta.SinkElapsed = &newPlayer.SinkElapsed
+	}
+	if !oldPlayer.DashReadyAt.Equal(newPlayer.DashReadyAt) {
+		delta.DashCooldown = &newPlayer.DashCooldown
+	}
+
+	delta.ShipConfig = calculateShipConfigDeltas(&oldPlayer.ShipConfig, &newPlayer.ShipConfig, reloadClock{player: newPlayer, now: now})
 
 	// Compare autofire (changes rarely)
 	if oldPlayer.AutofireEnabled != newPlayer.AutofireEnabled {
 		delta.AutofireEnabled = &newPlayer.AutofireEnabled
 	}
+	if oldPlayer.AimedAutofire != newPlayer.AimedAutofire {
+		delta.AimedAutofire = &newPlayer.AimedAutofire
+	}
 
 	// Compare debug info (changes frequently for display)
 	if !debugInfoEqual(oldPlayer.DebugInfo, newPlayer.DebugInfo) {
@@ -465,7 +730,11 @@ func debugInfoEqual(a, b DebugInfo) bool {
 		a.SideDPS == b.SideDPS &&
 		a.RearDPS == b.RearDPS &&
 		a.TopDPS == b.TopDPS &&
-		a.TotalDPS == b.TotalDPS
+		a.TotalDPS == b.TotalDPS &&
+		a.FrontRange == b.FrontRange &&
+		a.SideRange == b.SideRange &&
+		a.RearRange == b.RearRange &&
+		a.TopRange == b.TopRange
 }
 
 // upgradesEqual compares two upgrade maps
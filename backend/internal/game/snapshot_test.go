@@ -0,0 +1,498 @@
+package game
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+// Snapshot goroutines compute deltas and marshal from the copied players while
+// the next tick aims turrets and fires cannons; run with -race.
+func TestSnapshotsDoNotShareWeaponsWithLiveShips(t *testing.T) {
+	w := newTestWorld(t, nil)
+	client := addTestClient(t, w, 500, 500)
+
+	w.mu.Lock()
+	if !client.Player.ShipConfig.ApplyModule(UpgradeTypeTop, NewBasicTurrets(1).Name) {
+		w.mu.Unlock()
+		t.Fatal("could not fit a turret")
+	}
+	client.Player.ShipConfig.CalculateShipDimensions()
+	client.Player.ShipConfig.UpdateUpgradePositions()
+	w.mu.Unlock()
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go drainClient(client, stop)
+
+	for i := range 60 {
+		w.mu.Lock()
+		client.Input.Mouse.X = float64(100 + 10*i)
+		client.Input.Mouse.Y = float64(900 - 10*i)
+		w.mu.Unlock()
+		w.update()
+	}
+}
+
+// nextSnapshot waits for the snapshot queued by the last update
+func nextSnapshot(t *testing.T, client *Client) []byte {
+	t.Helper()
+	select {
+	case data := <-client.Send:
+		return data
+	case <-time.After(time.Second):
+		t.Fatal("no snapshot queued")
+		return nil
+	}
+}
+
+func TestActionAckAdvancesOnlyForTheOwner(t *testing.T) {
+	w := newTestWorld(t, nil)
+	owner := addTestClient(t, w, 1000, 1000)
+	watcher := addTestClient(t, w, 1100, 1000)
+	queuedMessages(owner)
+	queuedMessages(watcher)
+
+	// The first snapshot is a full one
+	w.update()
+	nextSnapshot(t, owner)
+	nextSnapshot(t, watcher)
+
+	w.mu.Lock()
+	owner.Player.LastProcessedAction = 7
+	w.mu.Unlock()
+	w.update()
+
+	var ownerDelta, watcherDelta DeltaSnapshot
+	if !decodeTestMsg(nextSnapshot(t, owner), &ownerDelta) || !decodeTestMsg(nextSnapshot(t, watcher), &watcherDelta) {
+		t.Fatal("could not decode delta snapshots")
+	}
+	acked := false
+	for _, delta := range ownerDelta.Players {
+		if delta.ID == owner.ID && delta.LastActionSeq != nil && *delta.LastActionSeq == 7 {
+			acked = true
+		}
+	}
+	if !acked {
+		t.Error("owner's delta did not acknowledge action 7")
+	}
+	for _, delta := range watcherDelta.Players {
+		if delta.LastActionSeq != nil {
+			t.Errorf("watcher was sent player %d's action ack", delta.ID)
+		}
+	}
+}
+
+func TestInputSequenceIsEchoedAndDivergentPredictionsCorrected(t *testing.T) {
+	w := newTestWorld(t, nil)
+	client := addTestClient(t, w, 2000, 2000)
+	queuedMessages(client)
+	w.update()
+	nextSnapshot(t, client)
+
+	// tick applies the input and returns the owner's sequence ack from the
+	// delta snapshot along with any corrections queued ahead of it
+	tick := func(input InputMsg) (uint32, []CorrectionMsg) {
+		w.HandleInput(client.ID, input)
+		w.update()
+		var corrections []CorrectionMsg
+		for {
+			data := nextSnapshot(t, client)
+			var correction CorrectionMsg
+			if decodeTestMsg(data, &correction) && correction.Type == MsgTypeCorrection {
+				corrections = append(corrections, correction)
+				continue
+			}
+			var delta DeltaSnapshot
+			if !decodeTestMsg(data, &delta) || delta.Type != MsgTypeDeltaSnapshot {
+				continue
+			}
+			var ack uint32
+			for _, player := range delta.Players {
+				if player.ID == client.ID && player.LastInputSequence != nil {
+					ack = *player.LastInputSequence
+				}
+			}
+			return ack, corrections
+		}
+	}
+
+	w.mu.RLock()
+	closeX, closeY := client.Player.X, client.Player.Y
+	w.mu.RUnlock()
+	ack, corrections := tick(InputMsg{Type: "input", Sequence: 3, PredictedX: &closeX, PredictedY: &closeY})
+	if ack != 3 || len(corrections) != 0 {
+		t.Errorf("close prediction: ack %d with corrections %+v, want ack 3 and none", ack, corrections)
+	}
+
+	farX, farY := closeX+w.config.PredictionCorrectionThreshold*4, closeY
+	ack, corrections = tick(InputMsg{Type: "input", Sequence: 4, PredictedX: &farX, PredictedY: &farY})
+	w.mu.RLock()
+	serverX, serverY := client.Player.X, client.Player.Y
+	w.mu.RUnlock()
+	if ack != 4 || len(corrections) != 1 {
+		t.Fatalf("divergent prediction: ack %d with corrections %+v, want ack 4 and one correction", ack, corrections)
+	}
+	if got := corrections[0]; got.Sequence != 4 || got.X != serverX || got.Y != serverY {
+		t.Errorf("correction %+v, want sequence 4 at the server's (%v, %v)", got, serverX, serverY)
+	}
+
+	// A late, older input neither rewinds the ack nor triggers a correction
+	tick(InputMsg{Type: "input", Sequence: 2, PredictedX: &farX, PredictedY: &farY})
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if client.Player.LastInputSequence != 4 {
+		t.Errorf("stale input moved the ack to %d, want 4", client.Player.LastInputSequence)
+	}
+}
+
+// Clients join and leave while ticks run and snapshot goroutines filter
+// bullets for the ones still connected; run with -race.
+func TestSnapshotsSurviveClientsJoiningAndLeaving(t *testing.T) {
+	w := newTestWorld(t, nil)
+	shooter := addTestClient(t, w, 2000, 2000)
+	go func() {
+		for range shooter.Send {
+		}
+	}()
+	w.mu.Lock()
+	shooter.Player.AutofireEnabled = true
+	w.mu.Unlock()
+
+	stop := make(chan struct{})
+	ticked := make(chan struct{})
+	go func() {
+		defer close(ticked)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				w.update()
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}()
+
+	for i := range 50 {
+		client := addTestClient(t, w, 2000+float64(i%10)*20, 2050)
+		go func() {
+			for range client.Send {
+			}
+		}()
+		time.Sleep(time.Millisecond)
+		w.RemoveClient(client.ID)
+	}
+	close(stop)
+	<-ticked
+}
+
+func TestDistantPlayersAreSentWithReducedDetail(t *testing.T) {
+	w := newTestWorld(t, nil)
+	viewer := addTestClient(t, w, 1000, 1000)
+	near := addTestClient(t, w, 1300, 1000)
+	far := addTestClient(t, w, 1000+LODFullDetailRange+500, 1000)
+	stop := make(chan struct{})
+	defer close(stop)
+	go drainClient(near, stop)
+	go drainClient(far, stop)
+	queuedMessages(viewer)
+
+	w.update()
+	nextSnapshot(t, viewer)
+
+	w.mu.Lock()
+	for _, client := range []*Client{near, far} {
+		ForceStatUpgrades(client.Player, map[UpgradeType]int{StatUpgradeCannonDamage: 3})
+	}
+	w.mu.Unlock()
+	w.update()
+
+	var delta DeltaSnapshot
+	if !decodeTestMsg(nextSnapshot(t, viewer), &delta) {
+		t.Fatal("could not decode delta snapshot")
+	}
+	sent := make(map[uint32]PlayerDelta)
+	for _, player := range delta.Players {
+		sent[player.ID] = player
+	}
+	if sent[near.ID].Upgrades == nil {
+		t.Error("nearby player's upgrades were not sent")
+	}
+	farDelta := sent[far.ID]
+	if farDelta.Upgrades != nil || farDelta.DebugInfo != nil {
+		t.Error("distant player was sent upgrades or debug info")
+	}
+	if farDelta.X != nil && math.Mod(*farDelta.X, LODPositionStep) != 0 {
+		t.Errorf("distant player's position %v is not on the %v grid", *farDelta.X, LODPositionStep)
+	}
+}
+
+func TestSunkShipIsSentAsSinkingThenDropped(t *testing.T) {
+	w := newTestWorld(t, nil)
+	viewer := addTestClient(t, w, 1000, 1000)
+	victim := addTestClient(t, w, 1300, 1000)
+	stop := make(chan struct{})
+	defer close(stop)
+	go drainClient(victim, stop)
+	queuedMessages(viewer)
+
+	w.update()
+	nextSnapshot(t, viewer)
+
+	// tick returns the viewer's next delta snapshot
+	tick := func() DeltaSnapshot {
+		w.update()
+		for {
+			var delta DeltaSnapshot
+			if decodeTestMsg(nextSnapshot(t, viewer), &delta) && delta.Type == MsgTypeDeltaSnapshot {
+				return delta
+			}
+		}
+	}
+
+	w.mu.Lock()
+	w.mechanics.ApplyDamage(victim.Player, victim.Player.Health+1, nil, KillCauseCollision, time.Now())
+	w.mu.Unlock()
+	sinking := false
+	for _, player := range tick().Players {
+		if player.ID == victim.ID && player.Sinking != nil && *player.Sinking {
+			sinking = true
+		}
+	}
+	if !sinking {
+		t.Error("sunk ship was not sent as sinking")
+	}
+
+	w.mu.Lock()
+	victim.Player.DeathTime = victim.Player.DeathTime.Add(-SinkDuration)
+	w.mu.Unlock()
+	if delta := tick(); !slices.Contains(delta.PlayersRemoved, victim.ID) {
+		t.Errorf("wreck was not dropped once it finished sinking (removed %v)", delta.PlayersRemoved)
+	}
+}
+
+func TestDeadSpectatorSeesAroundTheirKiller(t *testing.T) {
+	// visible sinks a ship at (500, 500) from a killer across the map and
+	// returns whether its next snapshot holds a bullet flying beside the killer
+	visible := func(spectate bool) bool {
+		w := newTestWorld(t, nil)
+		victim := addTestClient(t, w, 500, 500)
+		killer := addTestClient(t, w, 4500, 4500)
+		stop := make(chan struct{})
+		defer close(stop)
+		go drainClient(killer, stop)
+
+		w.HandleInput(victim.ID, InputMsg{Type: "profile", SpectateKiller: spectate})
+		w.mu.Lock()
+		w.mechanics.ApplyDamage(victim.Player, victim.Player.Health+1000, killer.Player, KillCauseBullet, time.Now())
+		if victim.Player.KilledBy != killer.ID {
+			w.mu.Unlock()
+			t.Fatalf("victim killed by %d, want %d", victim.Player.KilledBy, killer.ID)
+		}
+		bullet := &Bullet{ID: w.bulletID, X: 4500, Y: 4200, OwnerID: killer.ID, CreatedAt: time.Now(), Radius: BulletSize, Damage: 10}
+		w.bullets[bullet.ID] = bullet
+		w.bulletID++
+		w.mu.Unlock()
+		queuedMessages(victim)
+
+		w.update()
+		var snapshot Snapshot
+		if !decodeTestMsg(nextSnapshot(t, victim), &snapshot) || snapshot.Type != MsgTypeSnapshot {
+			t.Fatal("could not decode the full snapshot")
+		}
+		return len(snapshot.Bullets) == 1
+	}
+
+	if visible(false) {
+		t.Fatal("bullet across the map was sent to a dead player who isn't spectating")
+	}
+	if !visible(true) {
+		t.Error("spectating dead player was not sent the bullets around their killer")
+	}
+}
+
+func TestFiredCannonReportsReloadProgressRisingToReady(t *testing.T) {
+	w := newTestWorld(t, nil)
+	client := addTestClient(t, w, 2000, 2000)
+	queuedMessages(client)
+	w.update()
+	nextSnapshot(t, client)
+
+	w.mu.Lock()
+	player := client.Player
+	player.AutofireEnabled = false
+	cannon := player.ShipConfig.SideUpgrade.Cannons[0]
+	reload := time.Duration(cannon.Stats.ReloadTime * player.Modifiers.ReloadSpeedMultiplier * float64(time.Second))
+	w.mu.Unlock()
+
+	// progress sends a snapshot as if the cannon fired ago and returns the
+	// reload progress the owner is told for it
+	progress := func(ago time.Duration) float64 {
+		w.mu.Lock()
+		cannon.LastFireTime = time.Now().Add(-ago)
+		cannon.RecoilTime = cannon.LastFireTime
+		w.mu.Unlock()
+		w.update()
+		for {
+			var delta DeltaSnapshot
+			if !decodeTestMsg(nextSnapshot(t, client), &delta) || delta.Type != MsgTypeDeltaSnapshot {
+				continue
+			}
+			for _, sent := range delta.Players {
+				if sent.ID == client.ID && sent.ShipConfig.SideUpgrade != nil && len(sent.ShipConfig.SideUpgrade.Cannons) > 0 {
+					return sent.ShipConfig.SideUpgrade.Cannons[0].ReloadProgress
+				}
+			}
+			t.Fatal("fired cannon was not in the owner's delta")
+		}
+	}
+
+	if fresh := progress(0); fresh > 0.1 {
+		t.Errorf("reload progress just after firing = %v, want near 0", fresh)
+	}
+	if half := progress(reload / 2); half < 0.4 || half > 0.6 {
+		t.Errorf("reload progress halfway through the reload = %v, want about 0.5", half)
+	}
+	if ready := progress(reload); ready != 1 {
+		t.Errorf("reload progress after the full reload = %v, want 1", ready)
+	}
+}
+
+func TestBulletHitSendsADamageEventAfterTheShootersMultiplier(t *testing.T) {
+	w := newTestWorld(t, nil)
+	target := addTestClient(t, w, 1000, 1000)
+	shooter := addTestClient(t, w, 4000, 4000)
+	stop := make(chan struct{})
+	defer close(stop)
+	go drainClient(shooter, stop)
+
+	const damage, multiplier = 10.0, 1.5
+	w.mu.Lock()
+	target.Player.AutofireEnabled = false
+	shooter.Player.AutofireEnabled = false
+	shooter.Player.Modifiers.BulletDamageMultiplier = multiplier
+	w.bullets[w.bulletID] = &Bullet{
+		ID: w.bulletID, X: target.Player.X, Y: target.Player.Y, OwnerID: shooter.ID,
+		CreatedAt: time.Now(), Radius: BulletSize, Damage: damage,
+	}
+	w.bulletID++
+	w.mu.Unlock()
+	queuedMessages(target)
+
+	w.update()
+	var snapshot Snapshot
+	if !decodeTestMsg(nextSnapshot(t, target), &snapshot) || snapshot.Type != MsgTypeSnapshot {
+		t.Fatal("could not decode the full snapshot")
+	}
+	if len(snapshot.DamageEvents) != 1 {
+		t.Fatalf("snapshot carried %d damage events, want 1", len(snapshot.DamageEvents))
+	}
+	event := snapshot.DamageEvents[0]
+	if event.TargetID != target.ID || event.Cause != KillCauseBullet {
+		t.Errorf("damage event hit %d by %v, want %d by %v", event.TargetID, event.Cause, target.ID, KillCauseBullet)
+	}
+	if want := damage * multiplier; math.Abs(event.Amount-want) > 1e-9 {
+		t.Errorf("damage event amount = %v, want %v", event.Amount, want)
+	}
+
+	w.update()
+	var delta DeltaSnapshot
+	if decodeTestMsg(nextSnapshot(t, target), &delta) && len(delta.DamageEvents) != 0 {
+		t.Errorf("hit was sent again on the next tick: %v", delta.DamageEvents)
+	}
+}
+
+func TestCrowsNestVisionRevealsFartherShipsAndBullets(t *testing.T) {
+	// sees sends a viewer at (1000, 1000) its first snapshot with a ship and a
+	// bullet 1600 away and returns how many of each it was sent
+	sees := func(visionLevel int) (ships, bullets int) {
+		w := newTestWorld(t, func(config *WorldConfig) {
+			config.FogOfWar = true
+		})
+		viewer := addTestClient(t, w, 1000, 1000)
+		other := addTestClient(t, w, 2600, 1000)
+		stop := make(chan struct{})
+		defer close(stop)
+		go drainClient(other, stop)
+
+		w.mu.Lock()
+		viewer.Player.AutofireEnabled = false
+		other.Player.AutofireEnabled = false
+		vision := viewer.Player.Upgrades[StatUpgradeVision]
+		vision.Level = visionLevel
+		viewer.Player.Upgrades[StatUpgradeVision] = vision
+		viewer.Player.updateModifiers()
+		w.bullets[w.bulletID] = &Bullet{ID: w.bulletID, X: 1000, Y: 2600, OwnerID: other.ID, CreatedAt: time.Now(), Radius: BulletSize, Damage: 10}
+		w.bulletID++
+		w.mu.Unlock()
+		queuedMessages(viewer)
+
+		w.update()
+		var snapshot Snapshot
+		if !decodeTestMsg(nextSnapshot(t, viewer), &snapshot) || snapshot.Type != MsgTypeSnapshot {
+			t.Fatal("could not decode the full snapshot")
+		}
+		for _, player := range snapshot.Players {
+			if player.ID == other.ID {
+				ships++
+			}
+		}
+		return ships, len(snapshot.Bullets)
+	}
+
+	if ships, bullets := sees(0); ships != 0 || bullets != 0 {
+		t.Fatalf("default vision was sent %d ships and %d bullets 1600 away, want none", ships, bullets)
+	}
+	if ships, bullets := sees(2); ships != 1 || bullets != 1 {
+		t.Errorf("raised vision was sent %d ships and %d bullets 1600 away, want 1 of each", ships, bullets)
+	}
+}
+
+func TestSnapshotBulletsCarryTheWeaponThatFiredThem(t *testing.T) {
+	// kinds fires a broadside from the given side module and returns the
+	// bullet kinds in the owner's next full snapshot
+	kinds := func(side *ShipModule) []BulletKind {
+		w := newTestWorld(t, nil)
+		client := addTestClient(t, w, 2000, 2000)
+
+		w.mu.Lock()
+		client.Player.ShipConfig.SideUpgrade = side
+		client.Player.ShipConfig.TopUpgrade = nil
+		client.Player.ShipConfig.CalculateShipDimensions()
+		client.Player.ShipConfig.UpdateUpgradePositions()
+		client.Player.AutofireEnabled = true
+		w.mu.Unlock()
+		queuedMessages(client)
+
+		w.update()
+		var snapshot Snapshot
+		if !decodeTestMsg(nextSnapshot(t, client), &snapshot) || snapshot.Type != MsgTypeSnapshot {
+			t.Fatal("could not decode the full snapshot")
+		}
+		if len(snapshot.Bullets) == 0 {
+			t.Fatalf("%s fired no bullets", side.Name)
+		}
+		var kinds []BulletKind
+		for _, bullet := range snapshot.Bullets {
+			kinds = append(kinds, bullet.Kind)
+		}
+		return kinds
+	}
+
+	for _, test := range []struct {
+		side *ShipModule
+		want BulletKind
+	}{
+		{NewBasicSideCannons(1), BulletKindCannon},
+		{NewScatterSideCannons(1), BulletKindScatter},
+		{NewIncendiarySideCannons(1), BulletKindIncendiary},
+	} {
+		for _, kind := range kinds(test.side) {
+			if kind != test.want {
+				t.Errorf("%s bullet sent as kind %d, want %d", test.side.Name, kind, test.want)
+			}
+		}
+	}
+}
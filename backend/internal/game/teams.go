@@ -0,0 +1,31 @@
+package game
+
+import "log/slog"
+
+// TeamNames are the sides players are split between in ModeTeams
+var TeamNames = []string{"red", "blue"}
+
+// assignTeam puts a player joining a team-mode world on the side with the
+// fewest members, so late joiners even out the teams. In other modes every
+// ship sails for itself (w.mu must be held).
+func (w *World) assignTeam(player *Player) {
+	if w.config.Mode != ModeTeams {
+		player.Team = ""
+		return
+	}
+
+	members := make(map[string]int, len(TeamNames))
+	for _, other := range w.players {
+		if other != player && other.Team != "" {
+			members[other.Team]++
+		}
+	}
+	team := TeamNames[0]
+	for _, candidate := range TeamNames[1:] {
+		if members[candidate] < members[team] {
+			team = candidate
+		}
+	}
+	player.Team = team
+	slog.Debug("Player joined a team", "player", player.ID, "team", team)
+}
@@ -0,0 +1,58 @@
+package game
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTeamModeSplitsPlayersEvenly(t *testing.T) {
+	w := newTestWorld(t, func(config *WorldConfig) {
+		config.Mode = ModeTeams
+	})
+	members := map[string]int{}
+	for i := range 4 {
+		members[addTestClient(t, w, float64(500+200*i), 500).Player.Team]++
+	}
+	if members["red"] != 2 || members["blue"] != 2 {
+		t.Errorf("teams = %v, want two red and two blue", members)
+	}
+
+	ffa := newTestWorld(t, nil)
+	if team := addTestClient(t, ffa, 500, 500).Player.Team; team != "" {
+		t.Errorf("free-for-all player joined team %q", team)
+	}
+}
+
+func TestTeammatesBumpWithoutDamageWhileEnemiesCollide(t *testing.T) {
+	w := newTestWorld(t, func(config *WorldConfig) {
+		config.Mode = ModeTeams
+	})
+	red := addTestClient(t, w, 1000, 1000).Player
+	blue := addTestClient(t, w, 3000, 3000).Player
+	redMate := addTestClient(t, w, 1010, 1000).Player
+	if red.Team != redMate.Team || red.Team == blue.Team {
+		t.Fatalf("teams: %q, %q and %q", red.Team, redMate.Team, blue.Team)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, player := range []*Player{red, blue, redMate} {
+		player.LastCollisionDamage = time.Time{}
+	}
+
+	gap := math.Hypot(redMate.X-red.X, redMate.Y-red.Y)
+	w.mechanics.handlePlayerCollision(red, redMate)
+	if math.Hypot(redMate.X-red.X, redMate.Y-red.Y) <= gap {
+		t.Error("teammates were not pushed apart")
+	}
+	if red.Health != red.MaxHealth || redMate.Health != redMate.MaxHealth {
+		t.Errorf("teammates took damage: %v and %v", red.Health, redMate.Health)
+	}
+
+	blue.X, blue.Y = red.X-10, red.Y
+	w.mechanics.handlePlayerCollision(red, blue)
+	if red.Health == red.MaxHealth || blue.Health == blue.MaxHealth {
+		t.Errorf("enemies took no damage: %v and %v", red.Health, blue.Health)
+	}
+}
@@ -0,0 +1,89 @@
+package game
+
+import (
+	"math"
+	"time"
+)
+
+// hasTractorBeam reports whether the player has the tractor beam front module
+func (player *Player) hasTractorBeam() bool {
+	return player.ShipConfig.FrontUpgrade != nil && player.ShipConfig.FrontUpgrade.tractorBeam
+}
+
+// activateTractorBeam locks onto the nearest enemy in front of the player.
+// Returns false if the player has no beam or nothing is in range and arc.
+func (w *World) activateTractorBeam(player *Player, now time.Time) bool {
+	if player.State != StateAlive || !player.hasTractorBeam() {
+		return false
+	}
+
+	var target *Player
+	bestDistance := TractorBeamRange
+	for _, candidate := range w.players {
+		if candidate.ID == player.ID || candidate.State != StateAlive {
+			continue
+		}
+
+		dx := candidate.X - player.X
+		dy := candidate.Y - player.Y
+		distance := math.Hypot(dx, dy)
+		if distance > bestDistance {
+			continue
+		}
+
+		angleDiff := math.Abs(normalizeAngle(math.Atan2(dy, dx) - player.Angle))
+		if angleDiff > TractorBeamArc/2 {
+			continue
+		}
+
+		bestDistance = distance
+		target = candidate
+	}
+
+	if target == nil {
+		return false
+	}
+
+	player.TractorTargetID = target.ID
+	player.TractorUntil = now.Add(TractorBeamDuration)
+	return true
+}
+
+// updateTractorBeams pulls each held target toward its captor, releasing it when
+// the beam expires, either ship dies, or the target gets far enough away
+func (w *World) updateTractorBeams(now time.Time) {
+	for _, player := range w.players {
+		if player.TractorTargetID == 0 {
+			continue
+		}
+
+		target := w.players[player.TractorTargetID]
+		if target == nil || target.State != StateAlive || player.State != StateAlive ||
+			!player.hasTractorBeam() || now.After(player.TractorUntil) {
+			player.releaseTractorBeam()
+			continue
+		}
+
+		dx := player.X - target.X
+		dy := player.Y - target.Y
+		distance := math.Hypot(dx, dy)
+		if distance > TractorBeamBreakRange {
+			player.releaseTractorBeam()
+			continue
+		}
+		if distance == 0 {
+			continue
+		}
+
+		pull := TractorBeamPullSpeed * w.config.tickSeconds()
+		target.X += dx / distance * pull
+		target.Y += dy / distance * pull
+		w.keepPlayerInBounds(target)
+	}
+}
+
+// releaseTractorBeam drops the player's current beam target
+func (player *Player) releaseTractorBeam() {
+	player.TractorTargetID = 0
+	player.TractorUntil = time.Time{}
+}
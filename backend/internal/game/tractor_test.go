@@ -0,0 +1,32 @@
+package game
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTractorBeamPullsTargetWithoutTouchingItsVelocity(t *testing.T) {
+	w := newTestWorld(t, nil)
+	captor := addTestClient(t, w, 1000, 1000).Player
+	target := addTestClient(t, w, 1300, 1000).Player
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	captor.Angle = 0
+	captor.ShipConfig.FrontUpgrade = NewTractorBeamUpgrade()
+	target.VelX, target.VelY = 0, 0
+
+	if !w.activateTractorBeam(captor, time.Now()) {
+		t.Fatal("beam did not lock onto a target straight ahead")
+	}
+	w.updateTractorBeams(time.Now())
+
+	want := 1300 - TractorBeamPullSpeed*w.config.tickSeconds()
+	if math.Abs(target.X-want) > 1e-9 {
+		t.Errorf("target X = %v after one tick, want %v", target.X, want)
+	}
+	if target.VelX != 0 || target.VelY != 0 {
+		t.Errorf("target velocity changed to (%v, %v)", target.VelX, target.VelY)
+	}
+}
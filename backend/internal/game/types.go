@@ -1,6 +1,9 @@
 package game
 
 import (
+	"fmt"
+	"math"
+	"math/rand"
 	"regexp"
 	"strings"
 	"sync"
@@ -10,7 +13,8 @@ import (
 	"github.com/gorilla/websocket"
 )
 
-// UpgradeType defines the category of stat upgrade
+// UpgradeType is a stat bought with coins (see Player.BuyUpgrade). Module
+// slots picked on level-up are moduleType instead.
 type UpgradeType string
 
 const (
@@ -22,6 +26,9 @@ const (
 	StatUpgradeMoveSpeed    UpgradeType = "moveSpeed"    // Movement speed
 	StatUpgradeTurnSpeed    UpgradeType = "turnSpeed"    // Turn rate
 	StatUpgradeBodyDamage   UpgradeType = "bodyDamage"   // Collision damage
+	StatUpgradeAccuracy     UpgradeType = "accuracy"     // Tightens per-shot spread
+	StatUpgradeMagnet       UpgradeType = "magnet"       // Pulls nearby items toward the ship
+	StatUpgradeVision       UpgradeType = "vision"       // Crow's nest: extends how far the ship can see
 )
 
 const maxPlayerNameLength = 16
@@ -67,6 +74,17 @@ type InputMsg struct {
 	StartGame        bool   `msgpack:"startGame,omitempty"`
 	PlayerName       string `msgpack:"playerName,omitempty"`
 	PlayerColor      string `msgpack:"playerColor,omitempty"`
+	PlayerFlag       string `msgpack:"playerFlag,omitempty"`
+	ShipClass        string `msgpack:"shipClass,omitempty"`
+	ChatMessage      string `msgpack:"chatMessage,omitempty"`
+	SpectateKiller   bool   `msgpack:"spectateKiller,omitempty"`
+	DuelTarget       uint32 `msgpack:"duelTarget,omitempty"`
+	// Privileged command, only honored for admin connections
+	Admin *AdminCommand `msgpack:"admin,omitempty"`
+	// Client-side prediction (position the client expects after this input)
+	Sequence   uint32   `msgpack:"seq,omitempty"`
+	PredictedX *float64 `msgpack:"predictedX,omitempty"`
+	PredictedY *float64 `msgpack:"predictedY,omitempty"`
 }
 
 // InputAction represents a single-fire action with deduplication
@@ -94,6 +112,10 @@ type DebugInfo struct {
 	RearDPS           float64 `msgpack:"rearDps"`
 	TopDPS            float64 `msgpack:"topDps"`
 	TotalDPS          float64 `msgpack:"totalDps"`
+	FrontRange        float64 `msgpack:"frontRange"` // Longest effective range per category
+	SideRange         float64 `msgpack:"sideRange"`
+	RearRange         float64 `msgpack:"rearRange"`
+	TopRange          float64 `msgpack:"topRange"`
 }
 
 // Player represents a game player
@@ -108,7 +130,9 @@ type Player struct {
 	State       int       `msgpack:"state"`
 	Name        string    `msgpack:"name"`
 	Color       string    `msgpack:"color"`
+	Flag        string    `msgpack:"flag,omitempty"` // Unlocked cosmetic flag (empty = none)
 	IsBot       bool      `msgpack:"isBot"`
+	Team        string    `msgpack:"team,omitempty"` // Team name in team mode (empty = free-for-all)
 	Health      float64   `msgpack:"health"`
 	MaxHealth   float64   `msgpack:"maxHealth"`
 	RespawnTime time.Time `msgpack:"-"` // When the player can respawn (used only for bots)
@@ -129,8 +153,9 @@ type Player struct {
 	LastCollisionDamage time.Time `msgpack:"-"` // Last collision damage time
 	// Autofire toggle state
 	AutofireEnabled bool `msgpack:"autofireEnabled"` // Whether autofire is currently enabled
+	AimedAutofire   bool `msgpack:"aimedAutofire"`   // Autofire only shoots weapons aimed at an enemy
 	// Action processing state (for deduplication)
-	LastProcessedAction uint32               `msgpack:"-"` // Last processed action sequence number
+	LastProcessedAction uint32               `msgpack:"-"` // Last processed action sequence number, acknowledged in the owner's deltas
 	ActionCooldowns     map[string]time.Time `msgpack:"-"` // Cooldowns per action type
 	// Death tracking
 	KilledBy     uint32    `msgpack:"killedBy"`     // ID of player who killed this player (0 if none)
@@ -140,6 +165,75 @@ type Player struct {
 	SurvivalTime float64   `msgpack:"survivalTime"` // How long the player was alive (in seconds)
 	SpawnTime    time.Time `msgpack:"-"`            // When the player spawned
 	DebugInfo    DebugInfo `msgpack:"debugInfo"`    // Calculated debug values for client
+
+	Combat CombatStats `msgpack:"-"` // Shots, hits and damage this life (sent in the death summary)
+
+	// Anti-camping tracking
+	CampWindowStart  time.Time `msgpack:"-"` // Start of the current movement window
+	CampWindowOrigin Position  `msgpack:"-"` // Position at the start of the window
+	CampingSince     time.Time `msgpack:"-"` // When the player started camping (zero if moving)
+
+	// Movement validation
+	LastValidX      float64 `msgpack:"-"` // Position accepted at the end of the last tick
+	LastValidY      float64 `msgpack:"-"`
+	MovementTracked bool    `msgpack:"-"` // False until a position has been recorded since spawning
+
+	// Burning status from incendiary rounds
+	Burning      bool      `msgpack:"burning"` // Whether the ship is currently on fire
+	BurningUntil time.Time `msgpack:"-"`       // When the fire goes out
+	BurnSourceID uint32    `msgpack:"-"`       // Player credited for burn damage
+
+	// Tractor beam state
+	TractorTargetID uint32    `msgpack:"tractorTargetId"` // Player currently held by this ship's beam (0 if none)
+	TractorUntil    time.Time `msgpack:"-"`               // When the beam releases its target
+
+	// Passive income tracking
+	PassiveIncomeEarned  int     `msgpack:"-"` // Passive coins earned this life
+	passiveIncomeBalance float64 // Fractional coins not yet paid out
+
+	// Spawn protection after respawning
+	Protected           bool      `msgpack:"protected"` // Whether the player is currently spawn protected
+	SpawnProtectedUntil time.Time `msgpack:"-"`         // When spawn protection ends
+
+	// Last input sequence applied by the server, echoed for client reconciliation
+	LastInputSequence uint32 `msgpack:"lastInputSeq"`
+
+	// Prestige ranks earned from experience past the level cap
+	Prestige int `msgpack:"prestige"`
+
+	// Players sunk this round, for kill-target rounds and the scoreboard
+	Kills int `msgpack:"kills"`
+
+	// Marked as the top scorer; sinking this ship pays a bonus
+	IsBounty bool `msgpack:"isBounty"`
+
+	// Damage dealt to this ship by other players this life, for assist credit
+	RecentDamagers map[uint32]DamageContribution `msgpack:"-"`
+
+	// Temporary power-ups from collected items
+	ActiveBuffs []ActiveBuff `msgpack:"activeBuffs"`
+
+	// Wreck still broadcast for SinkDuration after death so clients can animate it
+	Sinking     bool  `msgpack:"sinking"`
+	SinkElapsed int64 `msgpack:"sinkElapsed"` // Milliseconds since the ship sank, as of the snapshot
+
+	// Drift from weapon recoil and dashes, added on top of the throttle and faded by drag
+	DriftVelX float64 `msgpack:"-"`
+	DriftVelY float64 `msgpack:"-"`
+
+	// When the dash ability is next usable (zero = ready)
+	DashReadyAt  time.Time `msgpack:"-"`
+	DashCooldown int64     `msgpack:"dashCooldown"` // Milliseconds until the dash is ready, as of the snapshot
+
+	// Hull picked before setting sail; applied on every spawn
+	Class ShipClass `msgpack:"-"`
+
+	// Left the water for the lobby alive; the ship is kept for the next "Set Sail"
+	InLobby bool `msgpack:"-"`
+
+	// Progress through the client's loadout preset this life
+	loadoutModuleStep int
+	loadoutStatStep   int
 }
 
 // Bot wraps an AI-controlled player with simple state required for decision making.
@@ -157,6 +251,13 @@ type Bot struct {
 	OrbitDirection    int
 	TurnIntent        float64
 	DesiredAngle      float64
+	Charge            bool // Drive straight at the target (rammers)
+
+	// Practice dummies hold still at GuardCenter and never shoot or retire
+	Dummy      bool
+	LastHealth float64   // Health at the end of the previous tick, to spot hits
+	FirstHit   time.Time // First hit since the last reset
+	LastHit    time.Time // Latest hit; the hull is restored DummyResetDelay after it
 }
 
 // GameItem represents collectible items in the game
@@ -167,19 +268,49 @@ type GameItem struct {
 	Type  string  `msgpack:"type"`
 	Coins int     `msgpack:"coins"`
 	XP    int     `msgpack:"xp"`
+
+	// Loot ownership: only the owner may collect the item until OwnedUntil
+	OwnerID    uint32    `msgpack:"-"` // Player the loot is reserved for (0 = anyone)
+	OwnedUntil time.Time `msgpack:"-"` // When the item becomes free-for-all
+}
+
+// collectibleBy reports whether the player may pick up the item right now
+func (item *GameItem) collectibleBy(playerID uint32, now time.Time) bool {
+	return item.OwnerID == 0 || item.OwnerID == playerID || !now.Before(item.OwnedUntil)
 }
 
 // Bullet represents a projectile fired from ship cannons
 type Bullet struct {
-	ID        uint32    `msgpack:"id"`
-	X         float64   `msgpack:"x"`
-	Y         float64   `msgpack:"y"`
-	VelX      float64   `msgpack:"velX"`
-	VelY      float64   `msgpack:"velY"`
-	OwnerID   uint32    `msgpack:"-"`
-	CreatedAt time.Time `msgpack:"-"` // Not serialized
-	Radius    float64   `msgpack:"radius"`
-	Damage    float64   `msgpack:"-"`
+	ID          uint32    `msgpack:"id"`
+	X           float64   `msgpack:"x"`
+	Y           float64   `msgpack:"y"`
+	VelX        float64   `msgpack:"velX"`
+	VelY        float64   `msgpack:"velY"`
+	OwnerID     uint32    `msgpack:"-"`
+	CreatedAt   time.Time `msgpack:"-"` // Not serialized
+	Radius      float64   `msgpack:"radius"`
+	Damage      float64   `msgpack:"-"`
+	Interceptor bool      `msgpack:"-"` // Destroys opposing bullets on contact
+	Incendiary  bool      `msgpack:"-"` // Sets the target on fire
+	Ricochet    bool      `msgpack:"-"` // Reflects off the world boundary while it has bounces left
+	Bounces     int       `msgpack:"-"` // Remaining boundary reflections
+
+	// Weapon that fired it, so clients can tell a scatter pellet from a shell
+	Kind BulletKind `msgpack:"kind,omitempty"`
+
+	SplashRadius float64 `msgpack:"-"` // Area damage radius around a hit (0 = none)
+	Range        float64 `msgpack:"-"` // Distance after which the bullet despawns (0 = lifetime only)
+	Traveled     float64 `msgpack:"-"` // Distance covered since it was fired
+	Falloff      float64 `msgpack:"-"` // Fraction of damage lost by the end of its flight (0 = none)
+	FlightRange  float64 `msgpack:"-"` // Distance over which falloff builds up
+
+	// Where the bullet was fired or last bounced, so a client seeing it for the
+	// first time mid-flight can draw the path it has already covered
+	OriginX float64 `msgpack:"originX"`
+	OriginY float64 `msgpack:"originY"`
+
+	Pierce     int      `msgpack:"-"` // Ships the bullet can still pass through
+	HitPlayers []uint32 `msgpack:"-"` // Ships already hit, so a piercing bullet damages each only once
 }
 
 // Snapshot represents the current game state sent to clients
@@ -189,6 +320,18 @@ type Snapshot struct {
 	Items   []GameItem `msgpack:"items"`
 	Bullets []Bullet   `msgpack:"bullets"`
 	Time    int64      `msgpack:"time"`
+	Zone    *Zone      `msgpack:"zone,omitempty"` // Battle-royale safe zone
+
+	DamageEvents []DamageEvent `msgpack:"damageEvents,omitempty"` // Hits landed since the last snapshot
+}
+
+// DamageEvent is one hit, sent once so clients can show a floating damage number
+type DamageEvent struct {
+	TargetID uint32    `msgpack:"targetId"`
+	Amount   float64   `msgpack:"amount"` // Health removed, after shields and multipliers
+	Cause    KillCause `msgpack:"cause"`
+	X        float64   `msgpack:"x"` // Target position when hit
+	Y        float64   `msgpack:"y"`
 }
 
 // DeltaSnapshot represents only the changes in game state since last snapshot
@@ -200,6 +343,11 @@ type DeltaSnapshot struct {
 	ItemsRemoved   []uint32      `msgpack:"itemsRemoved,omitempty"`   // IDs of items that were removed
 	BulletsAdded   []Bullet      `msgpack:"bulletsAdded,omitempty"`   // Bullets that were added
 	BulletsRemoved []uint32      `msgpack:"bulletsRemoved,omitempty"` // IDs of bullets that were removed
+	// Bearings (radians) from the receiving player to nearby off-screen enemies
+	OffscreenEnemies []float64 `msgpack:"offscreenEnemies,omitempty"`
+	Zone             *Zone     `msgpack:"zone,omitempty"` // Battle-royale safe zone
+
+	DamageEvents []DamageEvent `msgpack:"damageEvents,omitempty"` // Hits landed since the last snapshot
 }
 
 // PlayerDelta represents only the changed fields of a player since last snapshot
@@ -214,6 +362,8 @@ type PlayerDelta struct {
 	State             *int                     `msgpack:"state,omitempty"`             // Alive/dead state
 	Name              *string                  `msgpack:"name,omitempty"`              // Changes rarely
 	Color             *string                  `msgpack:"color,omitempty"`             // Changes rarely
+	Flag              *string                  `msgpack:"flag,omitempty"`              // Changes rarely
+	Team              *string                  `msgpack:"team,omitempty"`              // Set on joining in team mode
 	Health            *float64                  `msgpack:"health,omitempty"`            // Changes frequently
 	MaxHealth         *float64                  `msgpack:"maxHealth,omitempty"`         // Changes with upgrades
 	Level             *int                     `msgpack:"level,omitempty"`             // Changes occasionally
@@ -223,10 +373,26 @@ type PlayerDelta struct {
 	Coins             *int                     `msgpack:"coins,omitempty"`             // Changes with items/spending
 	Upgrades          *map[UpgradeType]Upgrade `msgpack:"statUpgrades,omitempty"`      // Changes with stat upgrades
 	AutofireEnabled   *bool                    `msgpack:"autofireEnabled,omitempty"`   // Changes rarely
+	AimedAutofire     *bool                    `msgpack:"aimedAutofire,omitempty"`     // Autofire mode toggle
 	DebugInfo         *DebugInfo               `msgpack:"debugInfo,omitempty"`         // Changes frequently for display
 	ScoreAtDeath      *int                     `msgpack:"scoreAtDeath,omitempty"`      // Score captured on death
 	SurvivalTime      *float64                 `msgpack:"survivalTime,omitempty"`      // Lifetime duration
+	KilledBy          *uint32                  `msgpack:"killedBy,omitempty"`          // Killer ID, for following the killer
 	KilledByName      *string                  `msgpack:"killedByName,omitempty"`      // Killer name tracking
+	Burning           *bool                    `msgpack:"burning,omitempty"`           // On fire from incendiary rounds
+	TractorTargetID   *uint32                  `msgpack:"tractorTargetId,omitempty"`   // Tractor beam target for rendering
+	Protected         *bool                    `msgpack:"protected,omitempty"`         // Spawn protection for rendering
+	LastInputSequence *uint32                  `msgpack:"lastInputSeq,omitempty"`      // Last applied input for reconciliation
+	LastActionSeq     *uint32                  `msgpack:"lastActionSeq,omitempty"`     // Last processed action, acknowledging it
+	Prestige          *int                     `msgpack:"prestige,omitempty"`          // Ranks earned past the level cap
+	Kills             *int                     `msgpack:"kills,omitempty"`             // Kills this round
+	IsBounty          *bool                    `msgpack:"isBounty,omitempty"`          // Top scorer marked for hunting
+	ActiveBuffs       *[]ActiveBuff            `msgpack:"activeBuffs,omitempty"`       // Power-ups for rendering
+
+	Sinking     *bool  `msgpack:"sinking,omitempty"`     // Wreck is sinking after death
+	SinkElapsed *int64 `msgpack:"sinkElapsed,omitempty"` // How far into sinking the wreck is, sent when it sinks
+
+	DashCooldown *int64 `msgpack:"dashCooldown,omitempty"` // Dash cooldown for the ability UI, sent when it starts
 }
 
 // ShipConfigDelta contains only the fields needed by the frontend for rendering
@@ -251,6 +417,10 @@ type CannonDelta struct {
 	Position   Position  `msgpack:"position,omitempty"`   // Relative position for drawing
 	Type       string    `msgpack:"type,omitempty"`       // Cannon type for rendering style
 	RecoilTime time.Time `msgpack:"recoilTime,omitempty"` // For recoil animation
+	FireOrder  int       `msgpack:"fireOrder,omitempty"`  // Position in the last volley
+
+	ReloadProgress float64 `msgpack:"reloadProgress"` // 0 = just fired, 1 = ready
+	Ammo           *int    `msgpack:"ammo,omitempty"` // Shots left in the magazine (omitted without one)
 }
 
 // TurretDelta contains only the fields needed by the frontend for rendering
@@ -260,12 +430,122 @@ type TurretDelta struct {
 	Type            string        `msgpack:"type,omitempty"`     // Turret type for rendering style
 	NextCannonIndex int           `msgpack:"nextCannonIndex"`    // For alternating recoil, cannot omit empty since 0 is valid
 	Cannons         []CannonDelta `msgpack:"cannons,omitempty"`  // Turret cannons (minimal data)
+
+	ReloadProgress float64 `msgpack:"reloadProgress"` // 0 = just fired, 1 = ready to fire again
 }
 
 // WelcomeMsg represents a welcome message sent to a new client
 type WelcomeMsg struct {
 	Type     string `msgpack:"type"`
 	PlayerId uint32 `msgpack:"playerId"`
+	Protocol int    `msgpack:"protocol"` // ProtocolVersion the server speaks
+}
+
+// EmoteMsg broadcasts a predefined signal from a player to nearby clients
+type EmoteMsg struct {
+	Type     string  `msgpack:"type"`
+	PlayerID uint32  `msgpack:"playerId"`
+	Emote    string  `msgpack:"emote"`
+	X        float64 `msgpack:"x"`
+	Y        float64 `msgpack:"y"`
+}
+
+// ChatMsg carries a moderated chat message from a player
+type ChatMsg struct {
+	Type     string `msgpack:"type"`
+	PlayerID uint32 `msgpack:"playerId"`
+	Name     string `msgpack:"name"`
+	Message  string `msgpack:"message"`
+}
+
+// UnlocksMsg lists the cosmetics a client may choose from
+type UnlocksMsg struct {
+	Type   string   `msgpack:"type"`
+	Colors []string `msgpack:"colors"` // Presets plus the account's unlocked colors
+	Flags  []string `msgpack:"flags"`  // Unlocked flags
+	Ranked bool     `msgpack:"ranked"` // Colors outside the list are rejected
+}
+
+// DuelMsg carries a duel invite, its answer, the arena to join, or the result
+type DuelMsg struct {
+	Type       string `msgpack:"type"`
+	Event      string `msgpack:"event"`                // "invite", "declined", "start" or "end"
+	PlayerID   uint32 `msgpack:"playerId,omitempty"`   // The other duelist
+	Name       string `msgpack:"name,omitempty"`       // The other duelist's name
+	Room       string `msgpack:"room,omitempty"`       // Arena room to reconnect to ("start")
+	Ticket     string `msgpack:"ticket,omitempty"`     // Single-use pass into that room ("start")
+	WinnerID   uint32 `msgpack:"winnerId,omitempty"`   // Winner ("end")
+	WinnerName string `msgpack:"winnerName,omitempty"` // Winner's name ("end")
+}
+
+// RoundEndMsg announces the end of a round and its winner; scores reset after it
+type RoundEndMsg struct {
+	Type       string `msgpack:"type"`
+	Round      int    `msgpack:"round"`              // Number of the round that ended
+	Reason     string `msgpack:"reason"`             // RoundEndKills or RoundEndTime
+	WinnerID   uint32 `msgpack:"winnerId,omitempty"` // Zero if nobody was playing
+	WinnerName string `msgpack:"winnerName,omitempty"`
+	Kills      int    `msgpack:"kills"` // Winner's kills
+	Score      int    `msgpack:"score"` // Winner's score
+}
+
+// AdminResultMsg reports the outcome of an admin command to its sender
+type AdminResultMsg struct {
+	Type    string           `msgpack:"type"`
+	Command AdminCommandType `msgpack:"command"`
+	OK      bool             `msgpack:"ok"`
+	Message string           `msgpack:"message"`
+}
+
+// BotDebugMsg exposes a bot's internal decision state to an admin watching it
+type BotDebugMsg struct {
+	Type           string   `msgpack:"type"`
+	BotID          uint32   `msgpack:"botId"`
+	TargetPlayerID uint32   `msgpack:"targetPlayerId"`
+	DesiredAngle   float64  `msgpack:"desiredAngle"`
+	TurnIntent     float64  `msgpack:"turnIntent"`
+	GuardCenter    Position `msgpack:"guardCenter"`
+	GuardRadius    float64  `msgpack:"guardRadius"`
+	Charge         bool     `msgpack:"charge"`
+}
+
+// CorrectionMsg tells a predicting client where the server actually placed its ship
+type CorrectionMsg struct {
+	Type     string  `msgpack:"type"`
+	Sequence uint32  `msgpack:"seq"` // Input sequence the correction applies to
+	X        float64 `msgpack:"x"`
+	Y        float64 `msgpack:"y"`
+	VelX     float64 `msgpack:"velX"`
+	VelY     float64 `msgpack:"velY"`
+	Angle    float64 `msgpack:"angle"`
+}
+
+// MapInfoMsg describes the static world layout, sent once on join
+type MapInfoMsg struct {
+	Type        string  `msgpack:"type"`
+	WorldWidth  float64 `msgpack:"worldWidth"`
+	WorldHeight float64 `msgpack:"worldHeight"`
+
+	// Bonus event multipliers, so the UI can announce e.g. "2x XP"
+	XPMultiplier   float64 `msgpack:"xpMultiplier"`
+	CoinMultiplier float64 `msgpack:"coinMultiplier"`
+
+	// Turn authority curve, so client prediction turns like the server
+	MinTurnFactor     float64 `msgpack:"minTurnFactor"`
+	TurnSpeedExponent float64 `msgpack:"turnSpeedExponent"`
+
+	// Wind and current zones, so the client can draw them and predict drift
+	Currents []Current `msgpack:"currents"`
+
+	SinkDuration int64 `msgpack:"sinkDuration"` // Milliseconds a wreck takes to sink
+}
+
+// ErrorMsg tells the client why it is being rejected or disconnected
+type ErrorMsg struct {
+	Type      string `msgpack:"type"`
+	Code      string `msgpack:"code"`
+	CloseCode int    `msgpack:"closeCode"`
+	Message   string `msgpack:"message"`
 }
 
 // UpgradeInfo represents simplified upgrade information for client
@@ -288,6 +568,18 @@ type GameEventMsg struct {
 	KillerName string `msgpack:"killerName,omitempty"`
 	VictimID   uint32 `msgpack:"victimId,omitempty"`
 	VictimName string `msgpack:"victimName,omitempty"`
+	Time       int64  `msgpack:"time,omitempty"` // When the event happened (Unix ms)
+
+	// Level-up and upgrade events
+	Level             int    `msgpack:"level,omitempty"`
+	AvailableUpgrades int    `msgpack:"availableUpgrades,omitempty"`
+	Upgrade           string `msgpack:"upgrade,omitempty"` // Applied "module:choice"
+
+	// Death summary events
+	Combat *CombatStats `msgpack:"combat,omitempty"`
+
+	// Practice dummy damage reports
+	DummyReport *DummyReport `msgpack:"dummyReport,omitempty"`
 }
 
 // ResetShipConfigMsg represents a message to reset the player's ship configuration
@@ -303,10 +595,39 @@ type Client struct {
 	Player       *Player
 	Input        InputMsg
 	Send         chan []byte
-	LastSeen     time.Time
+	LastSeen     time.Time // Last message of any kind (guarded by mu)
 	LastUpgrade  time.Time // Prevents rapid upgrade applications
 	lastSnapshot Snapshot  // Store the last sent snapshot for delta calculations
 	mu           sync.RWMutex
+
+	OffscreenIndicators bool         // Client opted into off-screen enemy bearings
+	CloseReason         *ClientError // Set before disconnecting so the close frame carries the reason
+
+	LastManualFire time.Time // Throttles manual fire requests
+
+	LastActive time.Time // Last input that steered, aimed, fired or acted (guarded by mu)
+
+	Account *Account // Cosmetic unlocks (nil without an account token; guarded by w.mu)
+
+	Loadout *LoadoutPreset // Build replayed every life (nil = none; guarded by w.mu)
+	Session string         // Token naming the browser session, keying its loadout preset
+
+	SpectateKiller bool // While dead, view the world around the killer instead of the wreck (guarded by w.mu)
+
+	IP string // Remote address the client connected from, for moderation
+
+	IsAdmin    bool   // Connected with the server's admin token; may send admin commands
+	WatchBotID uint32 // Bot whose AI state is streamed to this admin (guarded by w.mu)
+
+	// Chat rate limiting
+	chatWindowStart time.Time
+	chatCount       int
+
+	lastDuelInvite time.Time // Rate limits duel invites (guarded by w.mu)
+
+	droppedFrames int32 // Consecutive snapshots dropped on a full send buffer (atomic)
+	sendClosed    bool  // Send has been closed (guarded by sendMu)
+	sendMu        sync.RWMutex
 }
 
 // World represents the game world and all its entities
@@ -325,6 +646,42 @@ type World struct {
 	tickCounter       uint32 // For performance optimizations
 	snapshotCount     int64  // Total snapshots sent
 	totalSnapshotSize int64  // Total size of all snapshots
+	rejectedUpgrades  int64  // Upgrade requests for modules or stats that don't exist or aren't available
+
+	done     chan struct{} // Closed when the game loop exits
+	config   WorldConfig   // Operator settings
+	recorder *Recorder     // Snapshot recorder (nil when recording is off)
+
+	stop     chan struct{}  // Closed by Stop to end the game loop and background goroutines
+	stopOnce sync.Once      // Guards closing stop
+	workers  sync.WaitGroup // Background goroutines started by Start
+
+	startedAt time.Time // When the game loop started
+	zone      *Zone     // Current battle-royale zone (nil in other modes)
+
+	killFeed []GameEventMsg // Most recent kills, oldest first (guarded by mu)
+	rng      *rand.Rand     // World random source (guarded by mu)
+
+	bulletsByOwner map[uint32]int // Live bullet count per owner, for the per-player cap (guarded by mu)
+
+	duelInvites map[uint32]duelInvite // Pending duel invites by invited player ID (guarded by mu)
+	duel        *duelState            // Outcome of a duel arena (nil in other modes)
+	duelTickets DuelTicketIssuer      // Reserves duel rooms on accept (nil = rooms are unguarded)
+
+	currents []Current // Wind and current zones, fixed for the world's lifetime
+
+	round roundState // Current round when round rules are on (guarded by mu)
+
+	damageEvents []DamageEvent // Hits since the last snapshot, cleared each broadcast (guarded by mu)
+
+	botsSpawned int  // Bots created so far, for naming and loadout rotation (guarded by mu)
+	botsPaused  bool // Bots turned off by an admin; the population isn't topped up (guarded by mu)
+
+	bountyID         uint32    // Player currently marked as the bounty target (0 = none, guarded by mu)
+	nextBountyUpdate time.Time // When the bounty target is next recomputed (guarded by mu)
+
+	accountsMu   sync.Mutex     // Orders account read-modify-writes; taken before mu, never while holding it
+	accountSaves sync.WaitGroup // Account saves running in the background, waited for by Stop
 }
 
 // NewClient creates a new client
@@ -336,12 +693,15 @@ func NewClient(id uint32, conn *websocket.Conn) *Client {
 		Player:   player,
 		Send:     make(chan []byte, 256),
 		LastSeen: time.Now(),
+
+		LastActive: time.Now(),
 	}
 	player.Client = client
 	return client
 }
 
-// NewPlayer creates a new player with default values
+// NewPlayer creates a new player with default values. Players without a
+// requested name or color get random ones from the world when they join.
 func NewPlayer(id uint32) *Player {
 	// Calculate initial shaft length (same logic as updateShipDimensions)
 	shipLength := float64(PlayerSize*1.2) * 0.5 // Base shaft length for 1 cannon
@@ -366,6 +726,8 @@ func NewPlayer(id uint32) *Player {
 		MoveSpeedMultiplier:    1.0,
 		TurnSpeedMultiplier:    1.0,
 		BodyDamageBonus:        1.0,
+		InaccuracyMultiplier:   1.0,
+		VisionRange:            BaseVisionRange,
 	}
 
 	player := &Player{
@@ -376,8 +738,6 @@ func NewPlayer(id uint32) *Player {
 		Health:              100.0,
 		MaxHealth:           100.0,
 		Modifiers:           mods,
-		Color:               generateRandomColor(),
-		Name:                generateRandomName(),
 		Level:               1,
 		Experience:          0,
 		AvailableUpgrades:   0,
@@ -395,14 +755,13 @@ func NewPlayer(id uint32) *Player {
 	return player
 }
 
-func generateRandomColor() string {
-	colors := []string{"#FF6B6B", "#4ECDC4", "#45B7D1", "#96CEB4", "#FFEAA7", "#DDA0DD", "#98D8C8", "#F7DC6F"}
-	return colors[int(time.Now().UnixNano())%len(colors)]
+func generateRandomColor(rng *rand.Rand) string {
+	return PresetColors[rng.Intn(len(PresetColors))]
 }
 
-func generateRandomName() string {
+func generateRandomName(rng *rand.Rand) string {
 	names := []string{"Pirate", "Buccaneer", "Sailor", "Captain", "Admiral", "Navigator", "Corsair", "Raider"}
-	return names[int(time.Now().UnixNano())%len(names)]
+	return names[rng.Intn(len(names))]
 }
 
 // SanitizePlayerName cleans and bounds a requested player name.
@@ -454,6 +813,17 @@ func SanitizePlayerName(input string) string {
 	return result
 }
 
+// disambiguateName appends a " (n)" suffix to name, trimming the base so the
+// result still fits in maxPlayerNameLength
+func disambiguateName(name string, n int) string {
+	suffix := fmt.Sprintf(" (%d)", n)
+	base := []rune(name)
+	if keep := maxPlayerNameLength - len(suffix); len(base) > keep {
+		base = base[:max(keep, 0)]
+	}
+	return strings.TrimSpace(string(base)) + suffix
+}
+
 // SanitizePlayerColor validates and normalises a requested hull colour.
 func SanitizePlayerColor(input string) string {
 	if input == "" {
@@ -476,15 +846,25 @@ func GetExperienceRequiredForLevel(level int) int {
 		return 0
 	}
 
-	totalExp := 0
+	// Sum of the increments 100, 200, ..., (level-1)*100
+	return 50 * level * (level - 1)
+}
 
-	for i := 2; i <= level; i++ {
-		// Level increment increases by 100 each level: 100, 200, 300, 400...
-		levelIncrement := (i - 1) * 100
-		totalExp += levelIncrement
+// levelForExperience returns the highest level the given experience reaches
+func levelForExperience(experience int) int {
+	if experience <= 0 {
+		return 1
 	}
 
-	return totalExp
+	// Invert 50*L*(L-1) <= experience, then correct for floating point error
+	level := int((1 + math.Sqrt(1+float64(experience)/12.5)) / 2)
+	for GetExperienceRequiredForLevel(level+1) <= experience {
+		level++
+	}
+	for level > 1 && GetExperienceRequiredForLevel(level) > experience {
+		level--
+	}
+	return level
 }
 
 // GetExperienceRequiredForNextLevel returns the experience needed to reach the next level
@@ -2,6 +2,7 @@ package game
 
 import (
 	"math"
+	"math/rand"
 	"time"
 )
 
@@ -17,8 +18,41 @@ const (
 	WeaponTypeScatter          WeaponType = "scatter"
 	WeaponTypeRow              WeaponType = "row"
 	WeaponTypeBigTurret        WeaponType = "big_turret"
+	WeaponTypeFlakTurret       WeaponType = "flak_turret"
+	WeaponTypeIncendiary       WeaponType = "incendiary"
+	WeaponTypeRicochet         WeaponType = "ricochet"
+	WeaponTypePiercing         WeaponType = "piercing"
 )
 
+// BulletKind tells clients which kind of weapon fired a bullet so they can
+// draw it distinctly; it's a single byte on the wire
+type BulletKind uint8
+
+const (
+	BulletKindCannon     BulletKind = iota // Plain cannon shot (omitted on the wire)
+	BulletKindTurret                       // Turret round
+	BulletKindMachineGun                   // Small machine gun round
+	BulletKindBigShell                     // Heavy turret shell
+	BulletKindFlak                         // Flak interceptor round
+	BulletKindScatter                      // Scatter pellet
+	BulletKindIncendiary                   // Burning shot
+	BulletKindRicochet                     // Bouncing shot
+	BulletKindPiercing                     // Piercing shot
+)
+
+// bulletKinds maps each firing weapon to the kind its bullets are drawn as
+// (weapons missing here fire plain cannon shots)
+var bulletKinds = map[WeaponType]BulletKind{
+	WeaponTypeTurret:           BulletKindTurret,
+	WeaponTypeMachineGunTurret: BulletKindMachineGun,
+	WeaponTypeBigTurret:        BulletKindBigShell,
+	WeaponTypeFlakTurret:       BulletKindFlak,
+	WeaponTypeScatter:          BulletKindScatter,
+	WeaponTypeIncendiary:       BulletKindIncendiary,
+	WeaponTypeRicochet:         BulletKindRicochet,
+	WeaponTypePiercing:         BulletKindPiercing,
+}
+
 // CannonStats holds the properties of a cannon
 type CannonStats struct {
 	ReloadTime      float64 // Seconds between shots
@@ -28,6 +62,15 @@ type CannonStats struct {
 	SpreadAngle     float64 // Spread angle for multiple bullets (radians)
 	Range           float64 // Maximum effective range (0 = unlimited)
 	Size            float64 // Visual size of the cannon
+	Interceptor     bool    // Bullets destroy opposing bullets on contact
+	Incendiary      bool    // Bullets set targets on fire
+	Bounces         int     // Times bullets reflect off the world boundary (0 = no ricochet)
+	SplashRadius    float64 // Radius of area damage around a hit (0 = direct hit only)
+	Inaccuracy      float64 // Largest random angle added to each shot (radians, 0 = perfectly accurate)
+	DamageFalloff   float64 // Fraction of damage lost at the end of the bullet's flight (0 = none)
+	Pierce          int     // Extra ships a bullet passes through before stopping (0 = stops at the first hit)
+	MagazineSize    int     // Shots fired before the magazine must be reloaded (0 = no magazine)
+	MagazineReload  float64 // Seconds to refill an emptied magazine
 }
 
 // Cannon represents a basic weapon that fires bullets
@@ -39,12 +82,61 @@ type Cannon struct {
 	LastFireTime time.Time   `msgpack:"-"` // Not serialized
 	Type         WeaponType  `msgpack:"type"`
 	RecoilTime   time.Time   `msgpack:"recoilTime"` // When the cannon last fired (for recoil animation)
+	FireOrder    int         `msgpack:"fireOrder"`  // Position in the last volley (0 = first to fire)
+
+	// Magazine state (only used when Stats.MagazineSize > 0). An emptied
+	// magazine is refilled by the first shot after MagazineReadyAt.
+	ShotsRemaining  int       `msgpack:"shotsRemaining"`
+	MagazineReadyAt time.Time `msgpack:"-"`
 }
 
-// CanFire checks if the cannon is ready to fire based on reload time
+// CanFire checks if the cannon is ready to fire based on reload time and,
+// for cannons with a magazine, whether it has a shot left
 func (c *Cannon) CanFire(player *Player, now time.Time) bool {
 	reloadTime := c.Stats.ReloadTime * player.Modifiers.ReloadSpeedMultiplier
-	return float64(now.Sub(c.LastFireTime).Seconds()) >= reloadTime
+	return float64(now.Sub(c.LastFireTime).Seconds()) >= reloadTime && c.magazineReady(now)
+}
+
+// ReloadProgress returns how far the cannon is through its reload, from 0
+// (just fired) to 1 (ready), using the same timing as CanFire. While an
+// emptied magazine refills, that longer reload is reported instead.
+func (c *Cannon) ReloadProgress(player *Player, now time.Time) float64 {
+	if !c.magazineReady(now) {
+		return reloadProgress(now.Sub(c.LastFireTime), c.MagazineReadyAt.Sub(c.LastFireTime).Seconds())
+	}
+	reloadTime := c.Stats.ReloadTime * player.Modifiers.ReloadSpeedMultiplier
+	return reloadProgress(now.Sub(c.LastFireTime), reloadTime)
+}
+
+// magazineReady reports whether the cannon has a shot in its magazine (always
+// true for cannons without one)
+func (c *Cannon) magazineReady(now time.Time) bool {
+	return c.Stats.MagazineSize <= 0 || c.ShotsRemaining > 0 || !now.Before(c.MagazineReadyAt)
+}
+
+// Ammo returns the shots left in the cannon's magazine: 0 while it reloads,
+// full once the reload is over
+func (c *Cannon) Ammo(now time.Time) int {
+	if c.ShotsRemaining <= 0 && !now.Before(c.MagazineReadyAt) {
+		return c.Stats.MagazineSize
+	}
+	return c.ShotsRemaining
+}
+
+// useMagazineShot spends one shot, starting the magazine reload (shortened
+// by reload upgrades) when it runs dry
+func (c *Cannon) useMagazineShot(player *Player, now time.Time) {
+	if c.Stats.MagazineSize <= 0 {
+		return
+	}
+	if c.ShotsRemaining <= 0 {
+		c.ShotsRemaining = c.Stats.MagazineSize
+	}
+	c.ShotsRemaining--
+	if c.ShotsRemaining == 0 {
+		reload := c.Stats.MagazineReload * player.Modifiers.ReloadSpeedMultiplier
+		c.MagazineReadyAt = now.Add(time.Duration(reload * float64(time.Second)))
+	}
 }
 
 // Fire creates bullets from this cannon
@@ -56,14 +148,27 @@ func (c *Cannon) Fire(world *World, player *Player, targetAngle float64, now tim
 }
 
 func (c *Cannon) ForceFire(world *World, player *Player, targetAngle float64, now time.Time) []*Bullet {
-	bullets := make([]*Bullet, 0, c.Stats.BulletCount)
-
 	// Calculate world position of cannon
 	cos := float64(math.Cos(float64(player.Angle)))
 	sin := float64(math.Sin(float64(player.Angle)))
 	worldX := player.X + (c.Position.X*cos - c.Position.Y*sin)
 	worldY := player.Y + (c.Position.X*sin + c.Position.Y*cos)
 
+	return c.fireFrom(world, player, worldX, worldY, targetAngle, now)
+}
+
+// fireFrom creates bullets at the given world position and adds them to the
+// world. A cannon with an empty magazine, or whose whole shot would go past
+// the bullet caps, fires nothing and keeps its reload, ammo and recoil.
+func (c *Cannon) fireFrom(world *World, player *Player, worldX, worldY, targetAngle float64, now time.Time) []*Bullet {
+	if !c.magazineReady(now) || world.bulletRoom(player.ID) < c.Stats.BulletCount {
+		return nil
+	}
+	bullets := make([]*Bullet, 0, c.Stats.BulletCount)
+
+	// Jitter the whole shot once so scatter patterns keep their shape
+	targetAngle += c.shotJitter(player, world.rng)
+
 	// Create bullets
 	for i := 0; i < c.Stats.BulletCount; i++ {
 		// Calculate bullet angle (with spread for multi-bullet cannons)
@@ -84,17 +189,31 @@ func (c *Cannon) ForceFire(world *World, player *Player, targetAngle float64, no
 		baseDamage := float64(BulletDamage) * c.Stats.BulletDamageMod
 		finalDamage := baseDamage * player.Modifiers.BulletDamageMultiplier // Add cannon damage bonus
 		bulletSize := BulletSize * c.Stats.Size
+		bulletX, bulletY := player.clearOfHull(worldX, worldY, bulletAngle, bulletSize)
 
 		bullet := &Bullet{
-			ID:        world.bulletID,
-			X:         worldX,
-			Y:         worldY,
-			VelX:      bulletVelX,
-			VelY:      bulletVelY,
-			OwnerID:   player.ID,
-			CreatedAt: now,
-			Radius:    bulletSize,
-			Damage:    finalDamage,
+			ID:          world.bulletID,
+			X:           bulletX,
+			Y:           bulletY,
+			VelX:        bulletVelX,
+			VelY:        bulletVelY,
+			OwnerID:     player.ID,
+			Kind:        bulletKinds[c.Type],
+			CreatedAt:   now,
+			OriginX:     worldX,
+			OriginY:     worldY,
+			Radius:      bulletSize,
+			Damage:      finalDamage,
+			Interceptor: c.Stats.Interceptor,
+			Incendiary:  c.Stats.Incendiary,
+			Ricochet:    c.Stats.Bounces > 0,
+			Bounces:     c.Stats.Bounces,
+
+			SplashRadius: c.Stats.SplashRadius,
+			Pierce:       c.Stats.Pierce,
+			Range:        c.Stats.Range,
+			Falloff:      c.Stats.DamageFalloff,
+			FlightRange:  cannonRange(player, c.Stats),
 		}
 
 		bullets = append(bullets, bullet)
@@ -103,9 +222,80 @@ func (c *Cannon) ForceFire(world *World, player *Player, targetAngle float64, no
 
 	c.LastFireTime = now
 	c.RecoilTime = now
+	c.FireOrder = 0
+	c.useMagazineShot(player, now)
+	player.Combat.ShotsFired += len(bullets)
+	player.applyRecoil(targetAngle, c.Stats, world.config.RecoilStrength)
+	world.registerBullets(bullets)
 	return bullets
 }
 
+// clearOfHull moves a bullet of the given radius leaving (x, y) along angle to
+// where it first clears the player's hull rectangle, so cannons mounted inside
+// the hull never start a shot overlapping their own ship. The test runs in
+// ship-local coordinates, so a ship turned 45 degrees isn't padded out to its
+// axis-aligned box. Positions already clear are returned unchanged.
+func (player *Player) clearOfHull(x, y, angle, radius float64) (float64, float64) {
+	margin := radius + BulletHullClearance
+	halfLength := player.ShipConfig.ShipLength/2 + margin
+	halfWidth := player.ShipConfig.ShipWidth/2 + margin
+
+	// Rotate the start point and fire direction into the ship's frame
+	cos, sin := math.Cos(player.Angle), math.Sin(player.Angle)
+	dx, dy := x-player.X, y-player.Y
+	localX, localY := dx*cos+dy*sin, -dx*sin+dy*cos
+	if math.Abs(localX) >= halfLength || math.Abs(localY) >= halfWidth {
+		return x, y
+	}
+	dirX, dirY := math.Cos(angle-player.Angle), math.Sin(angle-player.Angle)
+
+	// Distance along the fire direction to the hull edge it crosses; rotation
+	// preserves distance, so it applies unchanged in world coordinates
+	exit := math.Inf(1)
+	if dirX > 0 {
+		exit = math.Min(exit, (halfLength-localX)/dirX)
+	} else if dirX < 0 {
+		exit = math.Min(exit, (-halfLength-localX)/dirX)
+	}
+	if dirY > 0 {
+		exit = math.Min(exit, (halfWidth-localY)/dirY)
+	} else if dirY < 0 {
+		exit = math.Min(exit, (-halfWidth-localY)/dirY)
+	}
+	return x + math.Cos(angle)*exit, y + math.Sin(angle)*exit
+}
+
+// averageShotInterval is the mean time between shots over a whole magazine,
+// counting its reload (just ReloadTime for cannons without a magazine)
+func (stats CannonStats) averageShotInterval() float64 {
+	if stats.MagazineSize <= 0 {
+		return stats.ReloadTime
+	}
+	cycle := float64(stats.MagazineSize-1)*stats.ReloadTime + math.Max(stats.MagazineReload, stats.ReloadTime)
+	return cycle / float64(stats.MagazineSize)
+}
+
+// recoilWeight is how hard a shot kicks the ship; heavy, large cannons kick hardest
+func (stats CannonStats) recoilWeight() float64 {
+	return stats.BulletDamageMod * stats.Size * float64(stats.BulletCount)
+}
+
+// shotJitter returns a random angle within the cannon's inaccuracy, tightened by the player's accuracy
+func (c *Cannon) shotJitter(player *Player, rng *rand.Rand) float64 {
+	spread := c.Stats.Inaccuracy * player.Modifiers.InaccuracyMultiplier
+	if spread <= 0 {
+		return 0
+	}
+	return spread * (rng.Float64()*2 - 1)
+}
+
+// staggerRecoil places the cannon at the given position in a volley, delaying
+// its recoil timestamp so clients animate the volley in firing order
+func (c *Cannon) staggerRecoil(order int) {
+	c.FireOrder = order
+	c.RecoilTime = c.LastFireTime.Add(time.Duration(order) * visualFireStagger[c.Type])
+}
+
 // Turret represents a rotatable weapon system with one or more cannons
 type Turret struct {
 	ID              uint32     `msgpack:"id"`
@@ -115,17 +305,82 @@ type Turret struct {
 	LastFireTime    time.Time  `msgpack:"-"`        // Not serialized
 	Type            WeaponType `msgpack:"type"`
 	NextCannonIndex int        `msgpack:"nextCannonIndex"` // For alternating fire
+	RotationSpeed   float64    `msgpack:"-"`               // Max rotation in radians per second (0 = instant)
 }
 
-// UpdateAiming updates the turret's angle to aim at target position
-func (t *Turret) UpdateAiming(player *Player, targetX, targetY float64) {
+// UpdateAiming rotates the turret toward the target position, limited by its rotation speed
+func (t *Turret) UpdateAiming(player *Player, targetX, targetY float64, dt float64) {
 	// Calculate desired angle to target
 	dx := targetX - player.X
 	dy := targetY - player.Y
 	targetAngle := float64(math.Atan2(float64(dy), float64(dx)))
 
-	// For now, instantly snap to target (can add smooth rotation later)
-	t.Angle = targetAngle
+	if t.RotationSpeed <= 0 {
+		t.Angle = targetAngle
+		return
+	}
+
+	// Rotate along the shortest direction, never past the target
+	angleDiff := normalizeAngle(targetAngle - t.Angle)
+	maxStep := t.RotationSpeed * dt
+	if math.Abs(angleDiff) <= maxStep {
+		t.Angle = targetAngle
+		return
+	}
+
+	if angleDiff > 0 {
+		t.Angle = normalizeAngle(t.Angle + maxStep)
+	} else {
+		t.Angle = normalizeAngle(t.Angle - maxStep)
+	}
+}
+
+// cannonWorldPosition returns where one of the turret's barrels sits in world
+// space. The turret mount is relative to the ship center and turns with the
+// ship; the barrel's Position is a local offset that turns with the turret's aim.
+func (t *Turret) cannonWorldPosition(player *Player, cannon *Cannon) (float64, float64) {
+	shipCos := math.Cos(player.Angle)
+	shipSin := math.Sin(player.Angle)
+	mountX := player.X + (t.Position.X*shipCos - t.Position.Y*shipSin)
+	mountY := player.Y + (t.Position.X*shipSin + t.Position.Y*shipCos)
+
+	aimCos := math.Cos(t.Angle)
+	aimSin := math.Sin(t.Angle)
+	return mountX + (cannon.Position.X*aimCos - cannon.Position.Y*aimSin),
+		mountY + (cannon.Position.X*aimSin + cannon.Position.Y*aimCos)
+}
+
+// ReloadProgress returns how far the turret is from firing again, from 0 to 1.
+// Twin turrets share one reload; other turrets report their slowest barrel.
+func (t *Turret) ReloadProgress(player *Player, now time.Time) float64 {
+	if len(t.Cannons) == 0 {
+		return 1
+	}
+
+	if t.Type == WeaponTypeMachineGunTurret && len(t.Cannons) > 1 {
+		cannon := &t.Cannons[t.NextCannonIndex%len(t.Cannons)]
+		if !cannon.magazineReady(now) {
+			return cannon.ReloadProgress(player, now)
+		}
+		reloadTime := cannon.Stats.ReloadTime * player.Modifiers.ReloadSpeedMultiplier
+		return reloadProgress(now.Sub(t.LastFireTime), reloadTime)
+	}
+
+	progress := 1.0
+	for i := range t.Cannons {
+		progress = math.Min(progress, t.Cannons[i].ReloadProgress(player, now))
+	}
+	return progress
+}
+
+// reloadProgress converts time since firing into a 0..1 fraction of the
+// reload time, rounded to ReloadProgressStep so it changes less often
+func reloadProgress(sinceFire time.Duration, reloadTime float64) float64 {
+	if reloadTime <= 0 {
+		return 1
+	}
+	progress := math.Max(0, math.Min(sinceFire.Seconds()/reloadTime, 1))
+	return math.Round(progress/ReloadProgressStep) * ReloadProgressStep
 }
 
 // Fire makes all cannons in the turret fire (simultaneously or alternating based on type)
@@ -142,19 +397,32 @@ func (t *Turret) Fire(world *World, player *Player, now time.Time) []*Bullet {
 		cannon := &t.Cannons[t.NextCannonIndex]
 		reloadTime := float64(cannon.Stats.ReloadTime) * float64(player.Modifiers.ReloadSpeedMultiplier)
 
-		if now.Sub(t.LastFireTime).Seconds() >= reloadTime {
-			bullets := cannon.ForceFire(world, player, t.Angle, now)
-			allBullets = append(allBullets, bullets...)
+		if now.Sub(t.LastFireTime).Seconds() >= reloadTime && cannon.magazineReady(now) {
+			x, y := t.cannonWorldPosition(player, cannon)
+			bullets := cannon.fireFrom(world, player, x, y, t.Angle, now)
+			if len(bullets) > 0 {
+				allBullets = append(allBullets, bullets...)
 
-			// Move to next cannon for alternating fire
-			t.NextCannonIndex = (t.NextCannonIndex + 1) % len(t.Cannons)
-			t.LastFireTime = now
+				// Move to next cannon for alternating fire
+				t.NextCannonIndex = (t.NextCannonIndex + 1) % len(t.Cannons)
+				t.LastFireTime = now
+			}
 		}
 	} else {
 		// Regular turret: fire all cannons simultaneously
+		volley := 0
 		for i := range t.Cannons {
 			cannon := &t.Cannons[i]
-			bullets := cannon.Fire(world, player, t.Angle, now)
+			if !cannon.CanFire(player, now) {
+				continue
+			}
+			x, y := t.cannonWorldPosition(player, cannon)
+			bullets := cannon.fireFrom(world, player, x, y, t.Angle, now)
+			if len(bullets) == 0 {
+				continue
+			}
+			cannon.staggerRecoil(volley)
+			volley++
 			allBullets = append(allBullets, bullets...)
 		}
 
@@ -176,6 +444,8 @@ func NewBasicCannon() CannonStats {
 		SpreadAngle:     0,   // No spread
 		Range:           0,   // Unlimited range
 		Size:            1.0, // Normal size
+		Inaccuracy:      0.03,
+		DamageFalloff:   0.2,
 	}
 }
 
@@ -186,8 +456,10 @@ func NewScatterCannon() CannonStats {
 		BulletDamageMod: 0.6,
 		BulletCount:     3,   // Fires 3 bullets
 		SpreadAngle:     0.5, // ~30 degree spread
-		Range:           0,   // Limited range
+		Range:           400, // Short range
 		Size:            0.7,
+		Inaccuracy:      0.05,
+		DamageFalloff:   0.5,
 	}
 }
 
@@ -200,18 +472,24 @@ func NewTurretCannon() CannonStats {
 		SpreadAngle:     0,
 		Range:           0,
 		Size:            1.0,
+		Inaccuracy:      0.03,
+		DamageFalloff:   0.2,
 	}
 }
 
 func NewMachineGunCannon() CannonStats {
 	return CannonStats{
-		ReloadTime:      0.3,
+		ReloadTime:      0.2, // Fast bursts...
+		MagazineSize:    15,
+		MagazineReload:  1.8, // ...then a long pause to reload
 		BulletSpeedMod:  0.7,
 		BulletDamageMod: 0.4,
 		BulletCount:     1,
 		SpreadAngle:     0,
-		Range:           0,
+		Range:           450,
 		Size:            0.7,
+		Inaccuracy:      0.08,
+		DamageFalloff:   0.3,
 	}
 }
 
@@ -224,6 +502,8 @@ func NewChaseCannon() CannonStats {
 		SpreadAngle:     0,
 		Range:           0,
 		Size:            0.7,
+		Inaccuracy:      0.02,
+		DamageFalloff:   0.15,
 	}
 }
 
@@ -236,6 +516,69 @@ func NewBigCannon() CannonStats {
 		SpreadAngle:     0,
 		Range:           0,
 		Size:            1.5,
+		SplashRadius:    80,
+		Inaccuracy:      0.01,
+		DamageFalloff:   0.05,
+	}
+}
+
+func NewFlakCannon() CannonStats {
+	return CannonStats{
+		ReloadTime:      0.5,
+		BulletSpeedMod:  1.1,
+		BulletDamageMod: 0.3,
+		BulletCount:     1,
+		SpreadAngle:     0,
+		Range:           0,
+		Size:            0.8,
+		Interceptor:     true, // Shoots down incoming bullets
+		Inaccuracy:      0.06,
+		DamageFalloff:   0.3,
+	}
+}
+
+func NewIncendiaryCannon() CannonStats {
+	return CannonStats{
+		ReloadTime:      1.2,
+		BulletSpeedMod:  0.9,
+		BulletDamageMod: 0.6, // Lower impact damage, the burn makes up the difference
+		BulletCount:     1,
+		SpreadAngle:     0,
+		Range:           0,
+		Size:            1.0,
+		Incendiary:      true,
+		Inaccuracy:      0.04,
+		DamageFalloff:   0.2,
+	}
+}
+
+func NewRicochetCannon() CannonStats {
+	return CannonStats{
+		ReloadTime:      1.1,
+		BulletSpeedMod:  1.1,
+		BulletDamageMod: 0.4,
+		BulletCount:     1,
+		SpreadAngle:     0,
+		Range:           0,
+		Size:            0.8,
+		Bounces:         2,
+		Inaccuracy:      0.03,
+		DamageFalloff:   0.1,
+	}
+}
+
+func NewPiercingCannon() CannonStats {
+	return CannonStats{
+		ReloadTime:      1.6,
+		BulletSpeedMod:  1.3, // Fast, flat shot
+		BulletDamageMod: 0.8,
+		BulletCount:     1,
+		SpreadAngle:     0,
+		Range:           0,
+		Size:            0.9,
+		Pierce:          2, // Passes through up to two ships and stops in a third
+		Inaccuracy:      0.01,
+		DamageFalloff:   0.1,
 	}
 }
 
@@ -0,0 +1,566 @@
+package game
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestShotsClearTheRotatedHullNotItsBoundingBox(t *testing.T) {
+	player := NewPlayer(1)
+	player.X, player.Y = 1000, 1000
+	player.Angle = math.Pi / 4
+	player.ShipConfig.ShipLength = 100
+	player.ShipConfig.ShipWidth = 40
+	const radius = 5.0
+	margin := radius + BulletHullClearance
+
+	// toWorld maps a ship-local offset to world coordinates
+	toWorld := func(localX, localY float64) (float64, float64) {
+		cos, sin := math.Cos(player.Angle), math.Sin(player.Angle)
+		return player.X + localX*cos - localY*sin, player.Y + localX*sin + localY*cos
+	}
+
+	// A broadside from the hull's edge only needs to move out by the margin
+	x, y := toWorld(0, 20)
+	broadside := player.Angle + math.Pi/2
+	clearX, clearY := player.clearOfHull(x, y, broadside, radius)
+	if moved := math.Hypot(clearX-x, clearY-y); math.Abs(moved-margin) > 1e-9 {
+		t.Errorf("broadside moved %v, want the margin %v", moved, margin)
+	}
+
+	// Off the hull but inside its axis-aligned box: already clear
+	x, y = toWorld(-50-margin-1, 0)
+	if clearX, clearY = player.clearOfHull(x, y, broadside, radius); clearX != x || clearY != y {
+		t.Errorf("shot clear of the hull moved from (%v, %v) to (%v, %v)", x, y, clearX, clearY)
+	}
+}
+
+func TestTurretSwingsAroundOverSeveralTicksWithoutOvershooting(t *testing.T) {
+	player := NewPlayer(1)
+	player.X, player.Y = 1000, 1000
+	// Just short of straight behind, so the shortest way round is counterclockwise
+	targetX, targetY := player.X-100, player.Y+1
+	targetAngle := math.Atan2(targetY-player.Y, targetX-player.X)
+	const dt = 1.0 / DefaultTickRate
+
+	ticksToAim := func(turret *Turret) int {
+		remaining := normalizeAngle(targetAngle - turret.Angle)
+		for tick := 1; tick <= 10*DefaultTickRate; tick++ {
+			turret.UpdateAiming(player, targetX, targetY, dt)
+			next := normalizeAngle(targetAngle - turret.Angle)
+			if next < 0 || next >= remaining && next != 0 {
+				t.Fatalf("%v went from %v to %v short of the target on tick %d", turret.Type, remaining, next, tick)
+			}
+			if next == 0 {
+				return tick
+			}
+			remaining = next
+		}
+		t.Fatalf("%v never reached the target", turret.Type)
+		return 0
+	}
+
+	basic := ticksToAim(NewBasicTurrets(1).Turrets[0])
+	if basic < 2 {
+		t.Errorf("basic turret swung 180 degrees in %d tick", basic)
+	}
+	if big := ticksToAim(NewBigTurrets(1).Turrets[0]); big <= basic {
+		t.Errorf("big turret took %d ticks, want more than the basic turret's %d", big, basic)
+	}
+}
+
+func TestInterceptorShootsDownIncomingBulletsButShellsPassThrough(t *testing.T) {
+	w := newTestWorld(t, nil)
+	now := time.Now()
+	flak := NewFlakCannon()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// Each pair of bullets overlaps, heading toward each other
+	w.bullets = map[uint32]*Bullet{
+		1: {ID: 1, X: 1000, Y: 1000, VelX: 100, OwnerID: 10, CreatedAt: now, Radius: 4, Interceptor: flak.Interceptor},
+		2: {ID: 2, X: 1006, Y: 1000, VelX: -100, OwnerID: 20, CreatedAt: now, Radius: 4},
+		3: {ID: 3, X: 2000, Y: 2000, VelX: 100, OwnerID: 10, CreatedAt: now, Radius: 4},
+		4: {ID: 4, X: 2006, Y: 2000, VelX: -100, OwnerID: 20, CreatedAt: now, Radius: 4},
+	}
+	w.updateBullets()
+
+	for _, id := range []uint32{1, 2} {
+		if _, ok := w.bullets[id]; ok {
+			t.Errorf("bullet %d survived meeting an interceptor", id)
+		}
+	}
+	for _, id := range []uint32{3, 4} {
+		if _, ok := w.bullets[id]; !ok {
+			t.Errorf("bullet %d was removed by a plain shell", id)
+		}
+	}
+}
+
+func TestMagazineFiresItsBurstThenReloads(t *testing.T) {
+	w := newTestWorld(t, nil)
+	player := NewPlayer(1)
+	player.X, player.Y = 1000, 1000
+	cannon := &Cannon{Stats: NewMachineGunCannon(), Type: WeaponTypeCannon}
+	shotGap := time.Duration(cannon.Stats.ReloadTime * float64(time.Second))
+
+	now := time.Now()
+	var lastShot time.Time
+	for shot := range cannon.Stats.MagazineSize {
+		if len(cannon.Fire(w, player, 0, now)) == 0 {
+			t.Fatalf("shot %d of the burst did not fire", shot+1)
+		}
+		lastShot = now
+		now = now.Add(shotGap)
+	}
+
+	// The burst's cadence no longer applies once the magazine is empty
+	if cannon.Ammo(now) != 0 || cannon.CanFire(player, now) || len(cannon.Fire(w, player, 0, now)) != 0 {
+		t.Fatal("emptied magazine kept firing at the burst cadence")
+	}
+	reloaded := lastShot.Add(time.Duration(cannon.Stats.MagazineReload * float64(time.Second)))
+	if cannon.CanFire(player, reloaded.Add(-time.Millisecond)) {
+		t.Error("magazine ready before its reload finished")
+	}
+
+	if cannon.Ammo(reloaded) != cannon.Stats.MagazineSize || len(cannon.Fire(w, player, 0, reloaded)) == 0 {
+		t.Fatal("magazine did not refill after its reload")
+	}
+	if ammo := cannon.Ammo(reloaded); ammo != cannon.Stats.MagazineSize-1 {
+		t.Errorf("ammo after the first shot of a new magazine = %d, want %d", ammo, cannon.Stats.MagazineSize-1)
+	}
+}
+
+func TestCappedCannonKeepsItsReloadAndAmmo(t *testing.T) {
+	w := newTestWorld(t, func(config *WorldConfig) {
+		config.MaxBulletsPerPlayer = 1
+	})
+	player := addTestClient(t, w, 1000, 1000).Player
+	cannon := &Cannon{Stats: NewMachineGunCannon(), Type: WeaponTypeCannon}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	if len(cannon.Fire(w, player, 0, now)) != 1 || len(w.bullets) != 1 {
+		t.Fatal("first shot was not fired into the world")
+	}
+	lastFire, ammo, shots := cannon.LastFireTime, cannon.ShotsRemaining, player.Combat.ShotsFired
+	driftX, driftY := player.DriftVelX, player.DriftVelY
+
+	later := now.Add(time.Second)
+	if bullets := cannon.Fire(w, player, 0, later); len(bullets) != 0 {
+		t.Fatalf("capped cannon fired %d bullets", len(bullets))
+	}
+	if cannon.LastFireTime != lastFire || cannon.ShotsRemaining != ammo {
+		t.Error("capped cannon spent its reload or ammo")
+	}
+	if player.Combat.ShotsFired != shots || player.DriftVelX != driftX || player.DriftVelY != driftY {
+		t.Error("capped cannon counted a shot or pushed the ship")
+	}
+
+	// Once the first bullet is gone the cannon fires again
+	for id := range w.bullets {
+		w.removeBullet(id)
+	}
+	if len(cannon.Fire(w, player, 0, later)) != 1 {
+		t.Error("cannon did not fire after the cap freed up")
+	}
+}
+
+func TestTurretTurnsAtMostItsRotationSpeedEachTick(t *testing.T) {
+	player := NewPlayer(1)
+	player.X, player.Y = 1000, 1000
+	turret := NewBasicTurrets(1).Turrets[0]
+	const dt = 1.0 / DefaultTickRate
+	maxStep := turret.RotationSpeed * dt
+
+	// Aim straight down from facing right: a quarter turn, several ticks away
+	turret.UpdateAiming(player, player.X, player.Y+100, dt)
+	if math.Abs(turret.Angle-maxStep) > 1e-9 {
+		t.Errorf("first tick turned %v, want the full step %v", turret.Angle, maxStep)
+	}
+
+	// A target within one step is reached exactly
+	turret.Angle = math.Pi/2 - maxStep/2
+	turret.UpdateAiming(player, player.X, player.Y+100, dt)
+	if turret.Angle != math.Atan2(100, 0) {
+		t.Errorf("turret stopped at %v, want the target %v", turret.Angle, math.Pi/2)
+	}
+}
+
+func TestVolleyCannonsCarryDistinctRecoilTimes(t *testing.T) {
+	w := newTestWorld(t, nil)
+	client := addTestClient(t, w, 2000, 2000)
+	stop := make(chan struct{})
+	defer close(stop)
+	go drainClient(client, stop)
+
+	w.mu.Lock()
+	client.Player.ShipConfig.SideUpgrade = NewBasicSideCannons(3)
+	client.Player.ShipConfig.CalculateShipDimensions()
+	client.Player.ShipConfig.UpdateUpgradePositions()
+	client.Player.AutofireEnabled = true
+	w.mu.Unlock()
+	w.update()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	cannons := client.Player.ShipConfig.SideUpgrade.Cannons
+	seen := make(map[time.Time]bool)
+	for _, cannon := range cannons {
+		if cannon.LastFireTime.IsZero() {
+			t.Fatalf("cannon %d did not fire", cannon.ID)
+		}
+		if seen[cannon.RecoilTime] {
+			t.Errorf("cannon %d recoils at the same time as another cannon", cannon.ID)
+		}
+		seen[cannon.RecoilTime] = true
+		want := cannon.LastFireTime.Add(time.Duration(cannon.FireOrder) * visualFireStagger[cannon.Type])
+		if !cannon.RecoilTime.Equal(want) {
+			t.Errorf("cannon %d (order %d) recoils at %v, want %v", cannon.ID, cannon.FireOrder, cannon.RecoilTime, want)
+		}
+	}
+}
+
+func TestOffCenterMachineGunFiresFromItsOwnBarrels(t *testing.T) {
+	w := newTestWorld(t, nil)
+	player := addTestClient(t, w, 1000, 1000).Player
+	turret := NewMachineGunTurret(1).Turrets[0]
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	player.Angle = math.Pi / 2
+	turret.Position = Position{X: 30, Y: 0} // Mounted 30 ahead of the ship's center
+	turret.Angle = math.Pi / 2
+
+	// The barrels sit 7 either side of the mount, across the turret's aim
+	want := []Position{{X: 1007, Y: 1030}, {X: 993, Y: 1030}}
+	now := time.Now()
+	for i, barrel := range want {
+		bullets := turret.Fire(w, player, now.Add(time.Duration(i)*time.Second))
+		if len(bullets) != 1 {
+			t.Fatalf("shot %d fired %d bullets, want 1", i, len(bullets))
+		}
+		if got := bullets[0]; math.Hypot(got.OriginX-barrel.X, got.OriginY-barrel.Y) > 1e-6 {
+			t.Errorf("barrel %d fired from (%v, %v), want (%v, %v)", i, got.OriginX, got.OriginY, barrel.X, barrel.Y)
+		}
+	}
+}
+
+func TestShotJitterStaysWithinInaccuracyAndAccuracyTightensIt(t *testing.T) {
+	w := newTestWorld(t, nil)
+	player := addTestClient(t, w, 2000, 2000).Player
+	cannon := &Cannon{Stats: NewMachineGunCannon(), Type: WeaponTypeCannon}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// widestJitter fires a burst straight right and returns the largest angle
+	// any shot strayed from its aim
+	now := time.Now()
+	widestJitter := func() float64 {
+		widest := 0.0
+		for range 200 {
+			now = now.Add(time.Minute)
+			bullets := cannon.Fire(w, player, 0, now)
+			if len(bullets) != 1 {
+				t.Fatalf("fired %d bullets, want 1", len(bullets))
+			}
+			widest = math.Max(widest, math.Abs(math.Atan2(bullets[0].VelY, bullets[0].VelX)))
+			w.removeBullet(bullets[0].ID)
+		}
+		return widest
+	}
+
+	player.InitializeStatUpgrades()
+	player.updateModifiers()
+	inaccuracy := cannon.Stats.Inaccuracy
+	if widest := widestJitter(); widest > inaccuracy || widest < inaccuracy/2 {
+		t.Errorf("untrained shots strayed up to %v, want spread across the %v inaccuracy", widest, inaccuracy)
+	}
+
+	ForceStatUpgrades(player, map[UpgradeType]int{StatUpgradeAccuracy: 5})
+	tightened := inaccuracy * player.Modifiers.InaccuracyMultiplier
+	if tightened >= inaccuracy {
+		t.Fatalf("accuracy upgrades left the multiplier at %v", player.Modifiers.InaccuracyMultiplier)
+	}
+	if widest := widestJitter(); widest > tightened {
+		t.Errorf("trained shots strayed up to %v, want at most %v", widest, tightened)
+	}
+}
+
+func TestLimitedRangeBulletsDespawnAtTheirRange(t *testing.T) {
+	if NewScatterCannon().Range <= 0 || NewMachineGunCannon().Range <= 0 {
+		t.Fatal("scatter and machine gun cannons should have a finite range")
+	}
+
+	w := newTestWorld(t, nil)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	const speed, limit = 300.0, 100.0
+	limited := &Bullet{ID: 1, X: 1000, Y: 1000, VelX: speed, Range: limit, CreatedAt: time.Now()}
+	unlimited := &Bullet{ID: 2, X: 1000, Y: 2000, VelX: speed, CreatedAt: time.Now()}
+	w.bullets[1], w.bullets[2] = limited, unlimited
+
+	ticks := int(math.Ceil(limit / (speed * w.config.tickSeconds())))
+	for range ticks - 1 {
+		w.updateBullets()
+	}
+	if _, exists := w.bullets[1]; !exists {
+		t.Fatalf("limited bullet despawned after %v of its %v range", limited.Traveled, limit)
+	}
+	w.updateBullets()
+	if _, exists := w.bullets[1]; exists {
+		t.Errorf("limited bullet still flying after %v", limited.Traveled)
+	}
+	if math.Abs(limited.X-1000-limit) > 1e-9 {
+		t.Errorf("limited bullet despawned %v from where it was fired, want %v", limited.X-1000, limit)
+	}
+
+	// The unlimited bullet flies on until its lifetime runs out
+	if _, exists := w.bullets[2]; !exists {
+		t.Fatal("unlimited bullet despawned early")
+	}
+	unlimited.CreatedAt = unlimited.CreatedAt.Add(-BulletLifetime * time.Second)
+	w.updateBullets()
+	if _, exists := w.bullets[2]; exists {
+		t.Error("unlimited bullet outlived its lifetime")
+	}
+}
+
+func TestFiringABigCannonPushesTheShipAwayFromTheShot(t *testing.T) {
+	// kick fires one shot straight down (+Y) from a ship holding still and
+	// returns how far the next tick carried it along Y
+	kick := func(stats CannonStats) float64 {
+		w := newTestWorld(t, nil)
+		client := addTestClient(t, w, 2000, 2000)
+		stop := make(chan struct{})
+		defer close(stop)
+		go drainClient(client, stop)
+		player := client.Player
+
+		w.mu.Lock()
+		player.Angle = 0
+		player.Modifiers.MoveSpeedMultiplier = 0 // Only recoil moves the ship
+		stats.Inaccuracy = 0
+		cannon := &Cannon{Stats: stats, Type: WeaponTypeCannon}
+		if len(cannon.Fire(w, player, math.Pi/2, time.Now())) == 0 {
+			w.mu.Unlock()
+			t.Fatal("cannon did not fire")
+		}
+		y := player.Y
+		w.mu.Unlock()
+
+		w.update()
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if math.Abs(player.VelX) > 1e-9 {
+			t.Errorf("recoil from a shot along Y moved the ship along X by %v", player.VelX)
+		}
+		return player.Y - y
+	}
+
+	big, basic := kick(NewBigCannon()), kick(NewBasicCannon())
+	if big >= 0 {
+		t.Fatalf("big cannon moved the ship %v along the shot, want backward", big)
+	}
+	if big >= basic {
+		t.Errorf("big cannon kicked %v, basic cannon %v; want the big cannon to kick harder", big, basic)
+	}
+}
+
+func TestPiercingBulletHitsEveryShipInLineOnce(t *testing.T) {
+	w := newTestWorld(t, nil)
+	shooter := addTestClient(t, w, 1000, 2000).Player
+	var line []*Player
+	for i := range 4 {
+		line = append(line, addTestClient(t, w, 1200+float64(i)*150, 2000).Player)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	stats := NewPiercingCannon()
+	stats.Inaccuracy = 0
+	cannon := &Cannon{Stats: stats, Type: WeaponTypePiercing}
+	if len(cannon.Fire(w, shooter, 0, time.Now())) != 1 {
+		t.Fatal("piercing cannon did not fire")
+	}
+
+	hits := make([]int, len(line))
+	for range 300 {
+		if len(w.bullets) == 0 {
+			break
+		}
+		before := make([]float64, len(line))
+		for i, target := range line {
+			before[i] = target.Health
+		}
+		w.updateBullets()
+		for i, target := range line {
+			if target.Health < before[i] {
+				hits[i]++
+			}
+		}
+	}
+
+	// Pierce 2 passes through two ships and stops in the third
+	if len(w.bullets) != 0 {
+		t.Error("piercing bullet flew on past the third ship")
+	}
+	if want := []int{1, 1, 1, 0}; !slices.Equal(hits, want) {
+		t.Errorf("hits along the line = %v, want %v", hits, want)
+	}
+}
+
+func TestScatterDPSCountsEachBulletAtTheSpreadHitFactor(t *testing.T) {
+	// frontDPS reports the front DPS of a ship carrying a single front cannon
+	frontDPS := func(stats CannonStats, spreadHitFactor float64) float64 {
+		w := newTestWorld(t, func(config *WorldConfig) { config.SpreadHitFactor = spreadHitFactor })
+		player := addTestClient(t, w, 1000, 1000).Player
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		player.ShipConfig.FrontUpgrade = &ShipModule{
+			Type:    UpgradeTypeFront,
+			Count:   1,
+			Cannons: []*Cannon{{ID: 1, Stats: stats, Type: WeaponTypeScatter}},
+		}
+		return w.calculateDebugInfo(player).FrontDPS
+	}
+
+	scatter := NewScatterCannon()
+	if scatter.BulletCount != 3 {
+		t.Fatalf("scatter cannon fires %d bullets, want 3", scatter.BulletCount)
+	}
+	single := scatter
+	single.BulletCount = 1
+
+	for _, factor := range []float64{1, 0.5} {
+		want := frontDPS(single, factor) * (1 + 2*factor)
+		if got := frontDPS(scatter, factor); math.Abs(got-want) > 0.2 {
+			t.Errorf("scatter DPS with hit factor %v = %v, want %v", factor, got, want)
+		}
+	}
+}
+
+func TestTargetModeSideCannonsHoldFireUntilAnEnemyIsInTheArc(t *testing.T) {
+	// volley autofires side cannons for a tick from a ship facing +X with an
+	// enemy at the given offset and reports whether any cannon fired
+	volley := func(mode SideCannonMode, dx, dy float64) bool {
+		w := newTestWorld(t, func(config *WorldConfig) {
+			config.SideCannonMode = mode
+		})
+		client := addTestClient(t, w, 2000, 2000)
+		enemy := addTestClient(t, w, 2000+dx, 2000+dy)
+		stop := make(chan struct{})
+		defer close(stop)
+		go drainClient(client, stop)
+		go drainClient(enemy, stop)
+
+		w.mu.Lock()
+		player := client.Player
+		player.Angle = 0
+		player.ShipConfig.SideUpgrade = NewBasicSideCannons(1)
+		player.ShipConfig.CalculateShipDimensions()
+		player.ShipConfig.UpdateUpgradePositions()
+		player.AutofireEnabled = true
+		w.mu.Unlock()
+		w.update()
+
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		for _, cannon := range player.ShipConfig.SideUpgrade.Cannons {
+			if !cannon.LastFireTime.IsZero() {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !volley(SideCannonsBroadside, 300, 0) {
+		t.Fatal("broadside cannons held fire, want them to fire regardless of targets")
+	}
+	if volley(SideCannonsTarget, 300, 0) {
+		t.Error("target-mode cannons fired at an enemy dead ahead, outside their arc")
+	}
+	if !volley(SideCannonsTarget, 0, 300) {
+		t.Error("target-mode cannons held fire with an enemy abeam")
+	}
+}
+
+func TestRippleFireWalksTheBroadsideOnePairPerShot(t *testing.T) {
+	w := newTestWorld(t, func(config *WorldConfig) {
+		config.SideCannonRipple = time.Nanosecond // Every tick is past the delay
+	})
+	client := addTestClient(t, w, 2000, 2000)
+	stop := make(chan struct{})
+	defer close(stop)
+	go drainClient(client, stop)
+
+	w.mu.Lock()
+	player := client.Player
+	player.ShipConfig.SideUpgrade = NewBasicSideCannons(3)
+	player.ShipConfig.CalculateShipDimensions()
+	player.ShipConfig.UpdateUpgradePositions()
+	player.AutofireEnabled = true
+	cannons := player.ShipConfig.SideUpgrade.Cannons
+	perSide := len(cannons) / 2
+	w.mu.Unlock()
+
+	for tick := range perSide {
+		w.update()
+		w.mu.Lock()
+		for i, cannon := range cannons {
+			fired := !cannon.LastFireTime.IsZero()
+			if want := i%perSide <= tick; fired != want {
+				t.Errorf("after tick %d cannon %d fired = %v, want %v", tick, i, fired, want)
+			}
+		}
+		w.mu.Unlock()
+	}
+}
+
+func TestAimedAutofireHoldsUntilAWeaponPointsAtAnEnemy(t *testing.T) {
+	w := newTestWorld(t, nil)
+	client := addTestClient(t, w, 2000, 2000)
+	enemy := addTestClient(t, w, 4000, 4000)
+	stop := make(chan struct{})
+	defer close(stop)
+	go drainClient(client, stop)
+	go drainClient(enemy, stop)
+
+	w.mu.Lock()
+	player := client.Player
+	player.Angle = 0
+	player.Modifiers.MoveSpeedMultiplier = 0
+	player.ShipConfig.SideUpgrade = NewBasicSideCannons(1)
+	player.ShipConfig.TopUpgrade = nil
+	player.ShipConfig.CalculateShipDimensions()
+	player.ShipConfig.UpdateUpgradePositions()
+	player.AutofireEnabled = true
+	enemy.Player.AutofireEnabled = false
+	w.mu.Unlock()
+
+	w.HandleInput(client.ID, InputMsg{Type: "input", Actions: []InputAction{{Type: "toggleAimedAutofire", Sequence: 1}}})
+	for range 5 {
+		w.update()
+	}
+
+	w.mu.Lock()
+	if !player.AimedAutofire {
+		w.mu.Unlock()
+		t.Fatal("toggle action did not turn aimed autofire on")
+	}
+	if player.Combat.ShotsFired != 0 {
+		t.Errorf("aimed autofire fired %d shots with no enemy in sight", player.Combat.ShotsFired)
+	}
+	enemy.Player.X, enemy.Player.Y = player.X, player.Y+300 // Abeam to starboard
+	w.mu.Unlock()
+
+	w.update()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if player.Combat.ShotsFired == 0 {
+		t.Error("aimed autofire held fire with an enemy abeam")
+	}
+}
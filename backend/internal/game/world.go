@@ -1,13 +1,22 @@
 package game
 
 import (
-	"log"
+	"log/slog"
 	"math"
+	"math/rand"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
-// NewWorld creates a new game world
+// NewWorld creates a new game world with the default configuration
 func NewWorld() *World {
+	return NewWorldWithConfig(DefaultWorldConfig())
+}
+
+// NewWorldWithConfig creates a new game world with the given configuration
+func NewWorldWithConfig(config WorldConfig) *World {
 	world := &World{
 		clients:      make(map[uint32]*Client),
 		players:      make(map[uint32]*Player),
@@ -18,44 +27,129 @@ func NewWorld() *World {
 		itemID:       1,
 		bulletID:     1,
 		running:      false,
+		done:         make(chan struct{}),
+		stop:         make(chan struct{}),
+		config:       config,
+		rng:          newWorldRNG(config.Seed),
+
+		bulletsByOwner: make(map[uint32]int),
+		duelInvites:    make(map[uint32]duelInvite),
 	}
 	world.mechanics = NewGameMechanics(world)
+	world.currents = generateCurrents(world.rng, config.CurrentCount, config.CurrentStrength)
+	if config.Mode == ModeDuel {
+		world.duel = &duelState{}
+	}
 	return world
 }
 
+// newWorldRNG returns the world's random source, seeded from the clock when seed is 0
+func newWorldRNG(seed int64) *rand.Rand {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return rand.New(rand.NewSource(seed))
+}
+
 // Start begins the game loop
 func (w *World) Start() {
 	w.mu.Lock()
-	if w.running {
+	if w.running || w.stopping() {
 		w.mu.Unlock()
 		return
 	}
 	w.running = true
+	w.startedAt = time.Now()
 	w.mu.Unlock()
+	defer close(w.done)
+
+	// Recording is opt-in so production isn't slowed
+	if w.config.RecordPath != "" {
+		recorder, err := NewRecorder(w.config.RecordPath)
+		if err != nil {
+			slog.Error("Could not start recorder", "err", err)
+		} else {
+			w.mu.Lock()
+			w.recorder = recorder
+			w.mu.Unlock()
+		}
+	}
 
 	// Spawn persistent bots before the game loop begins
 	w.spawnInitialBots()
 
 	// Spawn initial items
-	go w.spawnItems()
+	if !w.config.DisableItems {
+		w.workers.Add(1)
+		go w.spawnItems()
+	}
+
+	// Drop clients that went silent or AFK
+	w.workers.Add(1)
+	go w.sweepIdleClients()
 
 	// Main game loop
-	ticker := time.NewTicker(time.Second / TickRate)
+	ticker := time.NewTicker(time.Duration(w.config.tickSeconds() * float64(time.Second)))
 	defer ticker.Stop()
 
-	log.Println("Game world started")
-	for w.running {
-		<-ticker.C
-		w.update()
+	slog.Info("Game world started")
+loop:
+	for {
+		select {
+		case <-w.stop:
+			break loop
+		case <-ticker.C:
+			w.update()
+		}
 	}
+	w.workers.Wait()
 
+	w.mu.Lock()
+	if w.recorder != nil {
+		if err := w.recorder.Close(); err != nil {
+			slog.Error("Error closing recorder", "err", err)
+		}
+		w.recorder = nil
+	}
+	w.mu.Unlock()
+
+	slog.Info("Game world stopped")
 }
 
-// Stop stops the game world
+// Stop stops the game world and, if it was running, waits for the game loop
+// and its background goroutines to exit. It must not be called with w.mu held.
 func (w *World) Stop() {
 	w.mu.Lock()
+	wasRunning := w.running
+	neverStarted := !wasRunning && w.startedAt.IsZero()
 	w.running = false
+	w.stopOnce.Do(func() {
+		close(w.stop)
+		if neverStarted {
+			close(w.done) // Start will now refuse to run, so nothing else closes it
+		}
+	})
 	w.mu.Unlock()
+
+	if wasRunning {
+		<-w.done
+	}
+	w.accountSaves.Wait()
+}
+
+// Done returns a channel that is closed once the game loop has exited
+func (w *World) Done() <-chan struct{} {
+	return w.done
+}
+
+// stopping reports whether Stop has been called
+func (w *World) stopping() bool {
+	select {
+	case <-w.stop:
+		return true
+	default:
+		return false
+	}
 }
 
 // AddClient adds a new client to the world with connection limits
@@ -64,8 +158,9 @@ func (w *World) AddClient(client *Client) bool {
 	defer w.mu.Unlock()
 
 	// Check player limit for performance
-	if len(w.clients) >= MaxPlayers {
-		log.Printf("Server full: rejecting new player (limit: %d)", MaxPlayers)
+	limit := w.maxClients()
+	if len(w.clients) >= limit {
+		slog.Warn("Server full, rejecting new player", "limit", limit)
 		return false
 	}
 
@@ -73,8 +168,16 @@ func (w *World) AddClient(client *Client) bool {
 	client.Player.ID = w.nextPlayerID
 	w.nextPlayerID++
 
+	if client.Player.Name == "" {
+		client.Player.Name = generateRandomName(w.rng)
+	}
+	if client.Player.Color == "" {
+		client.Player.Color = generateRandomColor(w.rng)
+	}
+	client.Player.Name = w.uniquePlayerName(client.Player.Name, client.ID)
 	w.clients[client.ID] = client
 	w.players[client.ID] = client.Player
+	w.assignTeam(client.Player)
 
 	// Keep player in dead state until they press "Set Sail"
 	client.Player.State = StateDead
@@ -85,26 +188,113 @@ func (w *World) AddClient(client *Client) bool {
 	// Send welcome message to the new client with their player ID
 	client.sendWelcomeMessage()
 
+	// Send world dimensions so the client can draw bounds and the minimap
+	client.sendMapInfo(w.config, w.currents)
+
 	// Send available upgrades
 	client.sendAvailableUpgrades()
 
-	log.Printf("Player %d (%s) joined the lobby (%d/%d players)", client.ID, client.Player.Name, len(w.clients), MaxPlayers)
+	// Send the colors and flags the player may choose from
+	client.sendUnlocks(w.config.RankedCosmetics)
+
+	// Catch the joiner up on recent kills
+	for _, event := range w.killFeed {
+		client.sendGameEvent(event)
+	}
+
+	if w.duel != nil {
+		w.duel.joined++
+	}
+
+	slog.Info("Player joined the lobby", "player", client.ID, "name", client.Player.Name, "players", len(w.clients), "limit", limit)
 	return true
 }
 
+// maxClients returns how many connections the world accepts
+func (w *World) maxClients() int {
+	if w.config.MaxClients <= 0 || w.config.MaxClients > MaxPlayers {
+		return MaxPlayers
+	}
+	return w.config.MaxClients
+}
+
+// uniquePlayerName returns name, or a numbered variant of it when another
+// player already uses it; caller must hold w.mu
+func (w *World) uniquePlayerName(name string, playerID uint32) string {
+	if !w.config.UniqueNames {
+		return name
+	}
+
+	taken := func(candidate string) bool {
+		for id, player := range w.players {
+			if id != playerID && strings.EqualFold(player.Name, candidate) {
+				return true
+			}
+		}
+		return false
+	}
+
+	candidate := name
+	for n := 2; taken(candidate); n++ {
+		candidate = disambiguateName(name, n)
+	}
+	return candidate
+}
+
 // RemoveClient removes a client from the world
 func (w *World) RemoveClient(clientID uint32) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
 	if client, exists := w.clients[clientID]; exists {
-		log.Printf("Player %d (%s) left the game", clientID, client.Player.Name)
-		close(client.Send)
+		slog.Info("Player left the game", "player", clientID, "name", client.Player.Name)
+		w.checkDuelOver(client.Player)
+		delete(w.duelInvites, clientID)
+		client.closeSend()
 		delete(w.clients, clientID)
 		delete(w.players, clientID)
 	}
 }
 
+// DisconnectClient sends the client a structured error and removes it from the world.
+// The close frame matching the error is written once the queued messages drain.
+func (w *World) DisconnectClient(clientID uint32, code ErrorCode) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if client, exists := w.clients[clientID]; exists {
+		w.disconnectClient(client, code)
+	}
+}
+
+// DisconnectAll sends every connected client a structured error and removes them
+func (w *World) DisconnectAll(code ErrorCode) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, client := range w.clients {
+		w.disconnectClient(client, code)
+	}
+}
+
+// disconnectClient removes a client after queueing its error; caller must hold w.mu
+func (w *World) disconnectClient(client *Client, code ErrorCode) {
+	client.sendError(code)
+	slog.Info("Player disconnected", "player", client.ID, "name", client.Player.Name, "code", code)
+	w.checkDuelOver(client.Player)
+	delete(w.duelInvites, client.ID)
+	client.closeSend()
+	delete(w.clients, client.ID)
+	delete(w.players, client.ID)
+}
+
+// ClientCount returns the number of connected clients
+func (w *World) ClientCount() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return len(w.clients)
+}
+
 // GetClient returns a client by ID
 func (w *World) GetClient(id uint32) (*Client, bool) {
 	client, exists := w.clients[id]
@@ -128,16 +318,47 @@ func (w *World) update() {
 
 	// Update bot-controlled ships using AI inputs
 	w.updateBots()
+	w.sendBotDebug()
+
+	// Catch any NaN/Inf before it reaches collisions and snapshots
+	w.sanitizePlayerStates()
 
 	// Update bullets
 	w.updateBullets()
 
+	// Apply burn damage from incendiary rounds
+	w.updateBurning(time.Now())
+
+	// Remove power-ups that have run out
+	w.expireBuffs(time.Now())
+
+	// Hurt and push back ships that hug the world edge
+	w.applyBorderHazard(time.Now())
+
+	// Shrink the battle-royale zone and hurt ships outside it
+	w.updateZone(time.Now())
+
+	// Pull tractor beam targets toward their captors
+	w.updateTractorBeams(time.Now())
+
+	// Draw nearby items toward ships with the magnet stat
+	w.applyItemMagnet(time.Now())
+
 	// Check collisions
 	w.checkCollisions()
 
 	// Handle player vs player collisions
 	w.mechanics.HandlePlayerCollisions()
 
+	// End the round once someone hits the kill target or time runs out
+	w.updateRound(time.Now())
+
+	// Mark the top scorer as the bounty target
+	w.updateBounty(time.Now())
+
+	// Pushes and pulls from this tick are legitimate moves
+	w.recordValidPositions()
+
 	// Send snapshot to all clients (only every other tick for performance)
 	w.tickCounter++
 	if w.tickCounter%1 == 0 {
@@ -145,23 +366,62 @@ func (w *World) update() {
 	}
 }
 
+// sanitizePlayerStates logs and resets any player whose physics state became NaN or infinite
+func (w *World) sanitizePlayerStates() {
+	for _, player := range w.players {
+		if player.sanitizeState() {
+			slog.Warn("Player had a non-finite physics state, reset it",
+				"player", player.ID, "name", player.Name, "shipLength", player.ShipConfig.ShipLength)
+		}
+	}
+}
+
+// rejectUpgrade counts and logs an upgrade request naming a module or stat the
+// player can't take, so tampered clients show up in the logs and stats
+func (w *World) rejectUpgrade(player *Player, kind, choice, reason string) {
+	total := atomic.AddInt64(&w.rejectedUpgrades, 1)
+	slog.Debug("Rejected upgrade request",
+		"player", player.ID, "kind", truncateString(kind, MaxUpgradeChoiceLength),
+		"choice", truncateString(choice, MaxUpgradeChoiceLength), "reason", reason, "rejected", total)
+}
+
+// GetRejectedUpgrades returns how many upgrade requests the world has rejected
+func (w *World) GetRejectedUpgrades() int64 {
+	return atomic.LoadInt64(&w.rejectedUpgrades)
+}
+
+// truncateString shortens s to at most limit bytes
+func truncateString(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	return s[:limit]
+}
+
 // processPlayerActions handles event-based actions with deduplication and cooldowns
 func (w *World) processPlayerActions(player *Player, input *InputMsg) {
 	now := time.Now()
 
 	// Define cooldowns for each action type
 	actionCooldowns := map[string]time.Duration{
-		"statUpgrade":    100 * time.Millisecond,
-		"toggleAutofire": 400 * time.Millisecond,
+		"statUpgrade":         100 * time.Millisecond,
+		"toggleAutofire":      400 * time.Millisecond,
+		"toggleAimedAutofire": 400 * time.Millisecond,
+		"emote":               EmoteCooldown,
+		"tractorBeam":         TractorBeamCooldown,
+		"respecStats":         RespecCooldown,
+		"dash":                w.config.DashCooldown,
+		"saveLoadout":         time.Second,
+		"returnToLobby":       time.Second,
 	}
 
 	for _, action := range input.Actions {
 		// Skip if this action was already processed (deduplication)
 		if action.Sequence <= player.LastProcessedAction {
-			log.Printf("Player %d skipping already processed action seq %d (last: %d)",
-				player.ID, action.Sequence, player.LastProcessedAction)
-			// Update last processed to prevent reprocessing this sequence
-			player.LastProcessedAction = action.Sequence
+			// Clients resend actions until they see them acknowledged, so
+			// repeats are expected; never move the acknowledgment backwards
+			slog.Debug("Skipping already processed action",
+				"player", player.ID, "seq", action.Sequence, "lastSeq", player.LastProcessedAction)
 			continue
 		}
 
@@ -170,8 +430,8 @@ func (w *World) processPlayerActions(player *Player, input *InputMsg) {
 			cooldown := actionCooldowns[action.Type]
 			elapsed := now.Sub(lastTime)
 			if elapsed < cooldown {
-				log.Printf("Player %d action %s on cooldown (elapsed: %dms, need: %dms), skipping seq %d",
-					player.ID, action.Type, elapsed.Milliseconds(), cooldown.Milliseconds(), action.Sequence)
+				slog.Debug("Action on cooldown, skipping",
+					"player", player.ID, "action", action.Type, "elapsedMs", elapsed.Milliseconds(), "cooldownMs", cooldown.Milliseconds(), "seq", action.Sequence)
 				// Still update last processed to avoid reprocessing
 				player.LastProcessedAction = action.Sequence
 				continue
@@ -183,19 +443,71 @@ func (w *World) processPlayerActions(player *Player, input *InputMsg) {
 		switch action.Type {
 		case "statUpgrade":
 			statUpgradeType := UpgradeType(action.Data)
+			if _, known := player.Upgrades[statUpgradeType]; !known {
+				w.rejectUpgrade(player, "stat", action.Data, "unknown stat")
+				break
+			}
 			if player.BuyUpgrade(statUpgradeType) {
-				log.Printf("Player %d upgraded %s to level %d, coins remaining: %d (seq: %d)",
-					player.ID, statUpgradeType, player.Upgrades[statUpgradeType].Level, player.Coins, action.Sequence)
+				slog.Debug("Player upgraded stat",
+					"player", player.ID, "stat", statUpgradeType, "level", player.Upgrades[statUpgradeType].Level, "coins", player.Coins, "seq", action.Sequence)
 				handled = true
 			} else {
-				log.Printf("Player %d failed to upgrade %s (seq: %d)", player.ID, statUpgradeType, action.Sequence)
+				slog.Debug("Player failed to upgrade stat", "player", player.ID, "stat", statUpgradeType, "seq", action.Sequence)
 			}
 
+		case "saveLoadout":
+			client, exists := w.clients[player.ID]
+			if !exists {
+				break
+			}
+			if err := w.saveLoadout(client, action.Data); err != nil {
+				slog.Debug("Player sent invalid loadout", "player", player.ID, "err", err, "seq", action.Sequence)
+				client.sendGameEvent(GameEventMsg{EventType: "loadoutRejected", Time: now.UnixMilli()})
+				break
+			}
+			client.sendGameEvent(GameEventMsg{EventType: "loadoutSaved", Time: now.UnixMilli()})
+			handled = true
+
+		case "toggleAimedAutofire":
+			player.AimedAutofire = !player.AimedAutofire
+			slog.Debug("Player toggled aimed autofire", "player", player.ID, "enabled", player.AimedAutofire, "seq", action.Sequence)
+			handled = true
+
 		case "toggleAutofire":
 			player.AutofireEnabled = !player.AutofireEnabled
-			log.Printf("Player %d toggled autofire %s (seq: %d)", player.ID,
-				map[bool]string{true: "ON", false: "OFF"}[player.AutofireEnabled], action.Sequence)
+			slog.Debug("Player toggled autofire", "player", player.ID, "enabled", player.AutofireEnabled, "seq", action.Sequence)
 			handled = true
+
+		case "emote":
+			if !allowedEmotes[action.Data] || player.State != StateAlive {
+				slog.Debug("Player sent invalid emote", "player", player.ID, "emote", action.Data, "seq", action.Sequence)
+				break
+			}
+			w.broadcastEmote(player, action.Data)
+			handled = true
+
+		case "tractorBeam":
+			handled = w.activateTractorBeam(player, now)
+
+		case "respecStats":
+			if player.State != StateAlive {
+				slog.Debug("Player cannot respec while dead", "player", player.ID, "seq", action.Sequence)
+				break
+			}
+			refund := player.RespecStats(w.config)
+			slog.Info("Player respecced stats", "player", player.ID, "refund", refund, "seq", action.Sequence)
+			handled = true
+
+		case "dash":
+			if player.State != StateAlive || w.config.DashImpulse <= 0 {
+				break
+			}
+			player.dash(w.config.DashImpulse)
+			player.DashReadyAt = now.Add(w.config.DashCooldown)
+			handled = true
+
+		case "returnToLobby":
+			handled = w.returnToLobby(player, now)
 		}
 
 		// Always update last processed sequence to avoid reprocessing
@@ -208,11 +520,99 @@ func (w *World) processPlayerActions(player *Player, input *InputMsg) {
 	}
 }
 
+// returnToLobby takes a living player off the water as if they had never set
+// sail: no killer, no rewards paid out and no wreck, and they keep their
+// progress for the next "Set Sail". Players hit within LobbyCombatLock can't
+// leave, so it is no escape from a losing fight (w.mu must be held).
+func (w *World) returnToLobby(player *Player, now time.Time) bool {
+	client, exists := w.clients[player.ID]
+	if !exists || player.State != StateAlive {
+		return false
+	}
+	for _, contribution := range player.RecentDamagers {
+		if now.Sub(contribution.LastHit) < LobbyCombatLock {
+			slog.Debug("Player cannot leave for the lobby while in combat", "player", player.ID)
+			client.sendGameEvent(GameEventMsg{EventType: "lobbyBlocked", Time: now.UnixMilli()})
+			return false
+		}
+	}
+
+	player.State = StateDead
+	player.InLobby = true
+	player.VelX, player.VelY = 0, 0
+	player.DriftVelX, player.DriftVelY = 0, 0
+	player.KilledBy = 0
+	player.KilledByName = ""
+	player.RecentDamagers = nil
+	player.clearBurning()
+	player.releaseTractorBeam()
+	player.clearBuffs()
+	w.clearBounty(player)
+	w.checkDuelOver(player)
+
+	slog.Info("Player returned to the lobby", "player", player.ID, "name", player.Name, "score", player.Score)
+	client.sendGameEvent(GameEventMsg{EventType: "returnedToLobby", Time: now.UnixMilli()})
+	return true
+}
+
+// broadcastKill records a kill in the feed and sends it to every client
+// (w.mu must be held)
+func (w *World) broadcastKill(event GameEventMsg) {
+	w.killFeed = append(w.killFeed, event)
+	if len(w.killFeed) > KillFeedSize {
+		w.killFeed = slices.Clone(w.killFeed[len(w.killFeed)-KillFeedSize:])
+	}
+
+	for _, client := range w.clients {
+		client.sendGameEvent(event)
+	}
+}
+
+// broadcastEmote sends an emote to every client within range of the sender
+func (w *World) broadcastEmote(sender *Player, emote string) {
+	msg := EmoteMsg{
+		PlayerID: sender.ID,
+		Emote:    emote,
+		X:        sender.X,
+		Y:        sender.Y,
+	}
+
+	for _, client := range w.clients {
+		dx := client.Player.X - sender.X
+		dy := client.Player.Y - sender.Y
+		if dx*dx+dy*dy <= EmoteRange*EmoteRange {
+			client.sendEmote(msg)
+		}
+	}
+}
+
+// moduleUpgraded spends an upgrade point on a module the ship config has just
+// applied and tells the client (w.mu must be held)
+func (w *World) moduleUpgraded(client *Client, player *Player, upgradeType moduleType, choice string, now time.Time) {
+	player.updateModifiers()
+	player.AvailableUpgrades--
+	client.LastUpgrade = now // Update last upgrade time
+	slog.Info("Player applied upgrade",
+		"player", player.ID, "type", upgradeType, "choice", choice, "remaining", player.AvailableUpgrades)
+	// Send updated available upgrades to client
+	client.sendAvailableUpgrades()
+	client.sendGameEvent(GameEventMsg{
+		EventType:         "upgradeApplied",
+		Upgrade:           string(upgradeType) + ":" + choice,
+		Level:             player.Level,
+		AvailableUpgrades: player.AvailableUpgrades,
+		Time:              now.UnixMilli(),
+	})
+}
+
 // updatePlayer updates a single player's state with realistic ship physics
 func (w *World) updatePlayer(player *Player, input *InputMsg) {
 	// Handle respawn request if player is dead
-	if player.State == StateDead && input.RequestRespawn {
-		player.respawn()
+	if player.State == StateDead && input.RequestRespawn && w.config.Mode != ModeDuel {
+		// Only search for a spawn point once the respawn delay has passed
+		if player.canRespawn(time.Now()) {
+			player.respawn(w.config, w.chooseSafeSpawn(player))
+		}
 		return
 	}
 
@@ -222,15 +622,17 @@ func (w *World) updatePlayer(player *Player, input *InputMsg) {
 	// Handle legacy inputs for backward compatibility
 	if input.ToggleAutofire {
 		player.AutofireEnabled = !player.AutofireEnabled
-		log.Printf("Player %d toggled autofire %s", player.ID, map[bool]string{true: "ON", false: "OFF"}[player.AutofireEnabled])
+		slog.Debug("Player toggled autofire", "player", player.ID, "enabled", player.AutofireEnabled)
 		input.ToggleAutofire = false
 	}
 
 	if input.StatUpgradeType != "" {
 		statUpgradeType := UpgradeType(input.StatUpgradeType)
-		if player.BuyUpgrade(statUpgradeType) {
-			log.Printf("Player %d upgraded %s to level %d, coins remaining: %d",
-				player.ID, statUpgradeType, player.Upgrades[statUpgradeType].Level, player.Coins)
+		if _, known := player.Upgrades[statUpgradeType]; !known {
+			w.rejectUpgrade(player, "stat", input.StatUpgradeType, "unknown stat")
+		} else if player.BuyUpgrade(statUpgradeType) {
+			slog.Debug("Player upgraded stat",
+				"player", player.ID, "stat", statUpgradeType, "level", player.Upgrades[statUpgradeType].Level, "coins", player.Coins)
 		}
 		input.StatUpgradeType = ""
 	}
@@ -239,22 +641,27 @@ func (w *World) updatePlayer(player *Player, input *InputMsg) {
 		return
 	}
 
+	dt := w.config.tickSeconds()
+
 	// Calculate max speed with move speed upgrade and hull strength reduction
 	maxSpeed := (BaseShipMaxSpeed * player.Modifiers.MoveSpeedMultiplier)
-	// Ships always move forward automatically - players can only turn (A/D keys)
-	player.VelX = float64(math.Cos(float64(player.Angle))) * maxSpeed
-	player.VelY = float64(math.Sin(float64(player.Angle))) * maxSpeed
+	// Ships always move forward automatically unless reversing (S key) - players turn with A/D
+	throttle := 1.0
+	if input.Down {
+		throttle = -ReverseSpeedFactor
+	}
+	player.VelX = float64(math.Cos(float64(player.Angle))) * maxSpeed * throttle
+	player.VelY = float64(math.Sin(float64(player.Angle))) * maxSpeed * throttle
 	speed := min(float64(math.Sqrt(float64(player.VelX*player.VelX+player.VelY*player.VelY))), maxSpeed)
 
 	// Scale turn speed based on current speed and ship length
-	// Example: turn faster at low speed, slower at high speed
+	// Slow ships keep a minimum turn authority so they can still maneuver
 	// Longer ships turn slower (more realistic naval physics)
-	turnFactor := speed / BaseShipMaxSpeed
+	turnFactor := w.config.turnFactor(speed)
 
 	// Calculate length factor - longer ships turn slower
 	// Base length for comparison (1 cannon = standard ship)
-	baseShipLength := float64(PlayerSize * 1.2)                   // 1 cannon ship has no length multiplier
-	lengthFactor := baseShipLength / player.ShipConfig.ShipLength // Longer ships get smaller factor
+	lengthFactor := shipLengthFactor(player)
 
 	// Apply turn speed upgrade
 	baseTurnSpeed := BaseShipTurnSpeed * player.Modifiers.TurnSpeedMultiplier
@@ -262,13 +669,14 @@ func (w *World) updatePlayer(player *Player, input *InputMsg) {
 
 	// Handle turning (A/D keys) and track angular velocity
 	if input.Left {
-		player.Angle -= scaledTurnSpeed
+		player.Angle -= scaledTurnSpeed * dt
 	}
 	if input.Right {
-		player.Angle += scaledTurnSpeed
+		player.Angle += scaledTurnSpeed * dt
 	}
 
-	// Apply drag/deceleration
+	// Apply drag/deceleration (the throttle velocity is rebuilt every tick, so
+	// this is a flat cruise factor rather than a per-tick decay)
 	player.VelX *= ShipDeceleration
 	player.VelY *= ShipDeceleration
 
@@ -280,19 +688,36 @@ func (w *World) updatePlayer(player *Player, input *InputMsg) {
 		player.VelY *= speedRatio
 	}
 
+	// Recoil and dash drift isn't capped by the throttle; it fades with drag instead
+	driftSpeed := math.Hypot(player.DriftVelX, player.DriftVelY)
+	player.VelX += player.DriftVelX
+	player.VelY += player.DriftVelY
+	driftDecay := math.Pow(ShipDriftRetention, dt)
+	player.DriftVelX *= driftDecay
+	player.DriftVelY *= driftDecay
+
+	// Currents push the ship along whatever it's doing, even with no input
+	currentX, currentY := w.currentAt(player.X, player.Y)
+	player.VelX += currentX
+	player.VelY += currentY
+
 	// Update position
-	player.X += player.VelX
-	player.Y += player.VelY
+	player.X += player.VelX * dt
+	player.Y += player.VelY * dt
+
+	// Reject impossible jumps before anything uses the new position. Throttle,
+	// drift and current are all the ship's own physics can carry it this tick.
+	w.validateMovement(player, (maxSpeed+driftSpeed+math.Hypot(currentX, currentY))*dt)
+	w.reconcilePrediction(player, input)
 
 	// Update turret aiming and firing using modular system
 	now := time.Now()
-	w.updateModularTurretAiming(player, input)
+	player.Protected = player.isSpawnProtected(now)
+	player.updateCampingState(now)
+	w.updateModularTurretAiming(player, input, dt)
 	w.fireModularUpgrades(player, input, now)
 
-	for player.Experience >= player.GetExperienceRequiredForNextLevel() {
-		player.Level++
-		player.AvailableUpgrades++
-	}
+	w.notifyLevelUp(player, player.applyLevelUps(w.config.MaxLevel))
 
 	if DEV {
 		if input.UpgradeCannons {
@@ -318,7 +743,7 @@ func (w *World) updatePlayer(player *Player, input *InputMsg) {
 
 		// Handle leveling system
 		if input.DebugLevelUp {
-			player.DebugLevelUp()
+			w.notifyLevelUp(player, player.DebugLevelUp(w.config.MaxLevel))
 			// Send updated available upgrades to client
 			if client, exists := w.GetClient(player.ID); exists {
 				client.sendAvailableUpgrades()
@@ -332,38 +757,24 @@ func (w *World) updatePlayer(player *Player, input *InputMsg) {
 		if client, exists := w.GetClient(player.ID); exists {
 			now := time.Now()
 
-			// Enforce upgrade cooldown (500ms between upgrades)
-			if now.Sub(client.LastUpgrade) < 500*time.Millisecond {
+			// Enforce upgrade cooldown between upgrades
+			if now.Sub(client.LastUpgrade) < UpgradeCooldown {
 				// Clear input and skip processing
 				input.SelectUpgrade = ""
 				input.UpgradeChoice = ""
 				return
 			}
 
-			var upgradeType moduleType
-			switch input.SelectUpgrade {
-			case "side":
-				upgradeType = UpgradeTypeSide
-			case "top":
-				upgradeType = UpgradeTypeTop
-			case "front":
-				upgradeType = UpgradeTypeFront
-			case "rear":
-				upgradeType = UpgradeTypeRear
+			upgradeType := moduleTypeForSlot(input.SelectUpgrade)
+			switch {
+			case upgradeType == "":
+				w.rejectUpgrade(player, input.SelectUpgrade, input.UpgradeChoice, "unknown module type")
+			case len(input.UpgradeChoice) > MaxUpgradeChoiceLength:
+				w.rejectUpgrade(player, input.SelectUpgrade, input.UpgradeChoice, "choice too long")
+			case !player.ShipConfig.ApplyModule(upgradeType, input.UpgradeChoice):
+				w.rejectUpgrade(player, input.SelectUpgrade, input.UpgradeChoice, "not available")
 			default:
-				upgradeType = ""
-			}
-
-			if upgradeType != "" {
-				if player.ShipConfig.ApplyModule(upgradeType, input.UpgradeChoice) {
-					player.updateModifiers()
-					player.AvailableUpgrades--
-					client.LastUpgrade = now // Update last upgrade time
-					log.Printf("Player %d applied upgrade %s:%s, remaining upgrades: %d",
-						player.ID, upgradeType, input.UpgradeChoice, player.AvailableUpgrades)
-					// Send updated available upgrades to client
-					client.sendAvailableUpgrades()
-				}
+				w.moduleUpgraded(client, player, upgradeType, input.UpgradeChoice, now)
 			}
 		}
 
@@ -372,9 +783,16 @@ func (w *World) updatePlayer(player *Player, input *InputMsg) {
 		input.UpgradeChoice = ""
 	}
 
-	// Handle health regeneration from auto repairs upgrade
-	// Regenerate health based on time elapsed
-	elapsedSeconds := 1.0 / float64(TickRate)
+	// Follow the player's saved build as points and coins come in
+	if client, exists := w.clients[player.ID]; exists {
+		w.applyLoadout(client, player, time.Now())
+	}
+
+	elapsedSeconds := w.config.tickSeconds()
+	w.applyPassiveIncome(player, elapsedSeconds)
+
+	// Handle health regeneration from auto repairs upgrade. The tick's length
+	// in seconds (not the tick count) drives it, so HealthRegenPerSec holds at any tick rate.
 	healthToRegen := elapsedSeconds * player.Modifiers.HealthRegenPerSec
 	if healthToRegen > 0 && player.Health < player.MaxHealth {
 		player.Health += healthToRegen
@@ -387,6 +805,27 @@ func (w *World) updatePlayer(player *Player, input *InputMsg) {
 	w.keepPlayerInBounds(player)
 }
 
+// applyPassiveIncome trickles coins to living human players up to the per-life cap
+func (w *World) applyPassiveIncome(player *Player, elapsedSeconds float64) {
+	if w.config.PassiveIncomePerSecond <= 0 || player.IsBot || player.State != StateAlive {
+		return
+	}
+	if player.PassiveIncomeEarned >= w.config.PassiveIncomeCap {
+		return
+	}
+
+	player.passiveIncomeBalance += w.config.PassiveIncomePerSecond * elapsedSeconds
+	coins := int(player.passiveIncomeBalance)
+	if coins <= 0 {
+		return
+	}
+
+	coins = min(coins, w.config.PassiveIncomeCap-player.PassiveIncomeEarned)
+	player.passiveIncomeBalance -= float64(coins)
+	player.PassiveIncomeEarned += coins
+	player.addRewards(0, coins, w.config)
+}
+
 // checkCollisions handles player-item collisions (optimized)
 func (w *World) checkCollisions() {
 	// Early exit if no items or players
@@ -396,6 +835,7 @@ func (w *World) checkCollisions() {
 
 	// Pre-allocate slice for items to collect (avoid map iteration during deletion)
 	itemsToCollect := make([]struct{ playerID, itemID uint32 }, 0, 16)
+	now := time.Now()
 
 	for playerID, player := range w.players {
 		if player.State != StateAlive {
@@ -404,6 +844,11 @@ func (w *World) checkCollisions() {
 
 		// Simple distance check first (cheaper than full bounding box)
 		for itemID, item := range w.items {
+			// Loot reserved for another player's kill is skipped
+			if !item.collectibleBy(playerID, now) {
+				continue
+			}
+
 			// Quick distance check (using squares to avoid sqrt)
 			dx := player.X - item.X
 			dy := player.Y - item.Y
@@ -426,6 +871,52 @@ func (w *World) checkCollisions() {
 	}
 }
 
+// applyItemMagnet pulls items within each living player's magnet radius toward the ship
+func (w *World) applyItemMagnet(now time.Time) {
+	for playerID, player := range w.players {
+		radius := player.Modifiers.MagnetRadius
+		if player.State != StateAlive || radius <= 0 {
+			continue
+		}
+
+		for _, item := range w.items {
+			if !item.collectibleBy(playerID, now) {
+				continue
+			}
+
+			dx := player.X - item.X
+			dy := player.Y - item.Y
+			distSq := dx*dx + dy*dy
+			if distSq == 0 || distSq > radius*radius {
+				continue
+			}
+
+			distance := math.Sqrt(distSq)
+			step := math.Min(player.Modifiers.MagnetPull*w.config.tickSeconds(), distance)
+			item.X += dx / distance * step
+			item.Y += dy / distance * step
+		}
+	}
+}
+
+// notifyLevelUp sends a player's client one event for reaching a new level, so
+// the upgrade prompt shows even if the delta carrying it is dropped (w.mu must be held)
+func (w *World) notifyLevelUp(player *Player, levelsGained int) {
+	if levelsGained <= 0 {
+		return
+	}
+	client, exists := w.clients[player.ID]
+	if !exists {
+		return
+	}
+	client.sendGameEvent(GameEventMsg{
+		EventType:         "levelUp",
+		Level:             player.Level,
+		AvailableUpgrades: player.AvailableUpgrades,
+		Time:              time.Now().UnixMilli(),
+	})
+}
+
 // collectItem handles when a player collects an item
 func (w *World) collectItem(playerID, itemID uint32) {
 	player, playerExists := w.players[playerID]
@@ -434,56 +925,88 @@ func (w *World) collectItem(playerID, itemID uint32) {
 		return
 	}
 
-	player.Score += item.XP
-	player.Coins += item.Coins
-	player.AddExperience(item.XP)
+	xp, coins := w.config.scaleRewards(item.XP, item.Coins)
+	player.addRewards(xp, coins, w.config)
+	w.notifyLevelUp(player, player.AddExperience(xp, w.config.MaxLevel))
+
+	// Bots keep their tuned modifiers, so only players get power-ups
+	if buffType, isPowerUp := itemBuffs[item.Type]; isPowerUp && !player.IsBot {
+		player.addBuff(buffType, time.Now())
+	}
 
 	delete(w.items, itemID)
 }
 
-// handleBotRespawns checks for dead players that need to respawn
+// handleBotRespawns respawns sunk bots once their delay has passed, retiring
+// them instead while there are more bots than needed, then adds bots back
+// until the population is met
 func (w *World) handleBotRespawns() {
 	now := time.Now()
-	for _, player := range w.players {
-		if player.IsBot {
-			if player.State == StateDead && now.After(player.RespawnTime) {
-				if bot, exists := w.bots[player.ID]; exists {
-					w.respawnBot(bot, now)
-				}
-				continue
-			}
+	desired := w.desiredBotCount()
+	for id, bot := range w.bots {
+		player := bot.Player
+		if player == nil || player.State != StateDead || now.Before(player.RespawnTime) {
+			continue
 		}
+		if bot.Dummy {
+			w.respawnDummy(bot)
+			continue
+		}
+		if w.fightingBots() > desired {
+			w.retireBot(id)
+			continue
+		}
+		w.respawnBot(bot, now)
 	}
 
+	if w.botsPaused {
+		return
+	}
+	for w.fightingBots() < desired {
+		w.spawnBot(now)
+	}
 }
 
 // spawnItems continuously spawns items in the world (with limits)
 func (w *World) spawnItems() {
-	foodTicker := time.NewTicker(time.Second * 2)     // Spawn food every 2 seconds (reduced frequency)
-	specialTicker := time.NewTicker(time.Second * 10) // Spawn special items every 10 seconds (reduced frequency)
+	foodTicker := time.NewTicker(BaseFoodSpawnInterval)                     // Refill food, faster as players join
+	specialTicker := time.NewTicker(time.Second * SpecialItemSpawnInterval) // Spawn special items on their own cadence
 	defer foodTicker.Stop()
 	defer specialTicker.Stop()
+	defer w.workers.Done()
 
-	for w.running {
+	for {
 		select {
+		case <-w.stop:
+			return
 		case <-foodTicker.C:
 			w.mu.Lock()
-			// Reduced item limit and spawn rate to prevent accumulation
-			if len(w.items) < MaxItems && len(w.players) > 0 { // Only spawn if players present
-				w.mechanics.SpawnFoodItems()
-			}
+			foodTicker.Reset(w.refillFood())
 			w.mu.Unlock()
 		case <-specialTicker.C:
 			w.mu.Lock()
 			// Only spawn special items occasionally
-			if len(w.items) < 75 && len(w.players) > 2 { // Only if multiple players
-				w.mechanics.SpawnFoodItems() // Reuse food spawning for now
+			if len(w.clients) > SpecialItemMinPlayers { // Only if multiple players
+				w.mechanics.SpawnSpecialItems()
 			}
 			w.mu.Unlock()
 		}
 	}
 }
 
+// refillFood tops food up to (or trims it down to) the target for the current
+// human player count and returns when to refill next; caller must hold w.mu
+func (w *World) refillFood() time.Duration {
+	playerCount := len(w.clients)
+	target := desiredItemCount(playerCount)
+	if len(w.items) < target {
+		w.mechanics.SpawnFoodItems(target)
+	} else {
+		w.mechanics.TrimItems(target)
+	}
+	return foodSpawnInterval(playerCount)
+}
+
 // HandleInput processes input from a client
 func (w *World) HandleInput(clientID uint32, input InputMsg) {
 	client, exists := w.GetClient(clientID)
@@ -496,25 +1019,141 @@ func (w *World) HandleInput(clientID uint32, input InputMsg) {
 
 	switch input.Type {
 	case "profile":
-		if sanitizedName := SanitizePlayerName(input.PlayerName); sanitizedName != "" {
-			client.Player.Name = sanitizedName
-		}
-		if sanitizedColor := SanitizePlayerColor(input.PlayerColor); sanitizedColor != "" {
-			client.Player.Color = sanitizedColor
-		}
+		w.applyProfile(client, input)
 	case "startGame":
 		// When player presses "Set Sail", spawn them into the game
-		if client.Player.State == StateDead && input.StartGame {
-			client.Player.spawn()
-			log.Printf("Player %d (%s) set sail and entered the game", client.ID, client.Player.Name)
+		if client.Player.State == StateDead && input.StartGame && w.setSail(client, input) {
+			client.LastActive = time.Now()
+			slog.Info("Player set sail and entered the game", "player", client.ID, "name", client.Player.Name, "class", client.Player.Class)
 		}
+	case "chat":
+		w.handleChat(client, input.ChatMessage, time.Now())
+	case "admin":
+		w.handleAdminCommand(client, input.Admin)
+	case "duelInvite", "duelAccept", "duelDecline":
+		w.handleDuelInput(client, input, time.Now())
 	default:
+		if isActiveInput(&input, &client.Input) {
+			client.LastActive = time.Now()
+		}
 		client.Input = input
 	}
 
 	client.LastSeen = time.Now()
 }
 
+// applyProfile updates the client's name, colour, flag and hull choice.
+// The deferred unlock keeps w.mu from staying held if the read goroutine
+// recovers from a panic in here.
+func (w *World) applyProfile(client *Client, input InputMsg) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if sanitizedName := SanitizePlayerName(input.PlayerName); sanitizedName != "" {
+		client.Player.Name = w.uniquePlayerName(sanitizedName, client.ID)
+	}
+	if sanitizedColor := SanitizePlayerColor(input.PlayerColor); sanitizedColor != "" {
+		client.ChooseColor(sanitizedColor, w.config.RankedCosmetics)
+	}
+	if input.PlayerFlag == "" || client.Account.HasFlag(input.PlayerFlag) {
+		client.Player.Flag = input.PlayerFlag
+	}
+	client.Player.selectShipClass(input.ShipClass)
+	client.SpectateKiller = input.SpectateKiller
+}
+
+// setSail spawns a dead player into the game, returning false while a duel
+// is already under way
+func (w *World) setSail(client *Client, input InputMsg) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.duelOpen() {
+		return false
+	}
+	// A ship brought back from the lobby keeps its modules and hull damage
+	// unless a different class was picked
+	previousClass := client.Player.Class
+	client.Player.selectShipClass(input.ShipClass)
+	if !client.Player.InLobby || client.Player.Class != previousClass {
+		client.Player.applyShipClass()
+	}
+	client.Player.spawn(w.chooseSafeSpawn(client.Player))
+	client.Player.protectSpawn(time.Now(), w.config.SpawnProtection)
+	w.placeInDuelArena(client.Player)
+	client.SpectateKiller = input.SpectateKiller
+	return true
+}
+
+// reconcilePrediction records the newest applied input sequence and corrects
+// the client when its predicted position drifts too far from the server's
+func (w *World) reconcilePrediction(player *Player, input *InputMsg) {
+	if input.Sequence <= player.LastInputSequence {
+		return
+	}
+	player.LastInputSequence = input.Sequence
+
+	threshold := w.config.PredictionCorrectionThreshold
+	if threshold <= 0 || input.PredictedX == nil || input.PredictedY == nil {
+		return
+	}
+
+	dx := *input.PredictedX - player.X
+	dy := *input.PredictedY - player.Y
+	if dx*dx+dy*dy <= threshold*threshold {
+		return
+	}
+
+	if client, exists := w.clients[player.ID]; exists {
+		client.sendCorrection(CorrectionMsg{
+			Sequence: input.Sequence,
+			X:        player.X,
+			Y:        player.Y,
+			VelX:     player.VelX,
+			VelY:     player.VelY,
+			Angle:    player.Angle,
+		})
+	}
+}
+
+// validateMovement clamps a player's displacement since the last tick to what
+// one step at their speed allows (maxStep), guarding against teleports from bugs or untrusted input
+func (w *World) validateMovement(player *Player, maxStep float64) {
+	if !player.MovementTracked {
+		player.LastValidX = player.X
+		player.LastValidY = player.Y
+		player.MovementTracked = true
+		return
+	}
+
+	dx := player.X - player.LastValidX
+	dy := player.Y - player.LastValidY
+	distance := math.Sqrt(dx*dx + dy*dy)
+	maxDistance := maxStep * MaxMovementSpeedFactor
+
+	if distance > maxDistance {
+		slog.Warn("Player moved too far in one tick, clamping", "player", player.ID, "distance", distance, "max", maxDistance)
+		scale := maxDistance / distance
+		player.X = player.LastValidX + dx*scale
+		player.Y = player.LastValidY + dy*scale
+	}
+
+	player.LastValidX = player.X
+	player.LastValidY = player.Y
+}
+
+// recordValidPositions remembers where each ship ended the tick, so the next
+// movement check measures only its own motion and not collision pushes,
+// tractor beams or border push-back
+func (w *World) recordValidPositions() {
+	for _, player := range w.players {
+		if player.MovementTracked {
+			player.LastValidX = player.X
+			player.LastValidY = player.Y
+		}
+	}
+}
+
 // keepPlayerInBounds ensures a player stays within the world boundaries
 func (w *World) keepPlayerInBounds(player *Player) {
 	player.X = float64(math.Max(0, math.Min(WorldWidth, player.X)))
@@ -528,7 +1167,9 @@ func (w *World) updateBullets() {
 	}
 
 	now := time.Now()
+	dt := w.config.tickSeconds()
 	bulletsToDelete := make([]uint32, 0, 32) // Pre-allocate for common case
+	bulletsBounced := make([]uint32, 0, 8)
 
 	for id, bullet := range w.bullets {
 		// Check if bullet has expired
@@ -538,8 +1179,24 @@ func (w *World) updateBullets() {
 		}
 
 		// Update bullet position
-		bullet.X += bullet.VelX
-		bullet.Y += bullet.VelY
+		bullet.X += bullet.VelX * dt
+		bullet.Y += bullet.VelY * dt
+
+		// Limited-range bullets despawn once they have flown their range
+		bullet.Traveled += math.Hypot(bullet.VelX, bullet.VelY) * dt
+		if bullet.Range > 0 && bullet.Traveled >= bullet.Range {
+			bulletsToDelete = append(bulletsToDelete, id)
+			continue
+		}
+
+		// Ricochet bullets reflect off the world edge until they run out of bounces
+		if bullet.Ricochet && (bullet.X < 0 || bullet.X > WorldWidth || bullet.Y < 0 || bullet.Y > WorldHeight) {
+			if !bullet.reflectOffBounds() {
+				bulletsToDelete = append(bulletsToDelete, id)
+				continue
+			}
+			bulletsBounced = append(bulletsBounced, id)
+		}
 
 		// skip out of bounds bullets
 		if bullet.X < -100 || bullet.X > WorldWidth+100 || bullet.Y < -100 || bullet.Y > WorldHeight+100 {
@@ -552,8 +1209,12 @@ func (w *World) updateBullets() {
 			attacker = shooter
 		}
 		for playerID, player := range w.players {
-			// Skip if bullet owner or player is dead
-			if bullet.OwnerID == playerID || player.State != StateAlive {
+			// Skip if bullet owner, player is dead, or a piercing bullet already hit them
+			if bullet.OwnerID == playerID || player.State != StateAlive || slices.Contains(bullet.HitPlayers, playerID) {
+				continue
+			}
+			// Shots pass through teammates
+			if attacker != nil && isTeammate(attacker, player) {
 				continue
 			}
 
@@ -564,13 +1225,35 @@ func (w *World) updateBullets() {
 
 			// Only do expensive collision check if close enough (player size + some margin)
 			if distSq < 10000 && w.checkBulletPlayerCollision(bullet, player) { // 100^2 = 10000
-				// Apply damage through mechanics system (handles death + rewards)
-				damage := bullet.Damage * attacker.Modifiers.BulletDamageMultiplier
+				// Apply damage through mechanics system (handles death + rewards).
+				// The shooter may have left with the bullet still in flight.
+				damage := bullet.Damage
+				if attacker != nil {
+					damage *= attacker.Modifiers.BulletDamageMultiplier
+				}
 				if damage == 0 {
 					damage = float64(BulletDamage)
-					log.Printf("Bullet damage calculated as 0 for player %d, defaulting to %d", attacker.ID, BulletDamage)
+					slog.Warn("Bullet damage calculated as 0, using default", "player", bullet.OwnerID, "default", BulletDamage)
+				}
+				damage *= bullet.falloffMultiplier()
+				damage *= player.armorMultiplier(bullet.VelX, bullet.VelY)
+				if attacker != nil && len(bullet.HitPlayers) == 0 {
+					attacker.Combat.Hits++
 				}
 				w.mechanics.ApplyDamage(player, damage, attacker, KillCauseBullet, now)
+				if bullet.Incendiary && player.State == StateAlive {
+					player.ignite(bullet.OwnerID, now)
+				}
+				if bullet.SplashRadius > 0 {
+					w.applySplashDamage(bullet, player, attacker, damage, now)
+				}
+
+				// Piercing bullets carry on through the ship
+				if bullet.Pierce > 0 {
+					bullet.Pierce--
+					bullet.HitPlayers = append(bullet.HitPlayers, playerID)
+					continue
+				}
 
 				// Mark bullet for deletion
 				bulletsToDelete = append(bulletsToDelete, id)
@@ -580,9 +1263,192 @@ func (w *World) updateBullets() {
 		}
 	}
 
+	// Second pass: interceptor bullets shoot down opposing bullets
+	bulletsToDelete = w.interceptBullets(bulletsToDelete)
+
 	// Delete bullets in batch (avoid map modification during iteration)
 	for _, bulletID := range bulletsToDelete {
+		w.removeBullet(bulletID)
+	}
+
+	// Bounced bullets get a fresh ID so clients see the new trajectory as a new bullet
+	for _, bulletID := range bulletsBounced {
+		bullet, exists := w.bullets[bulletID]
+		if !exists {
+			continue
+		}
 		delete(w.bullets, bulletID)
+		bullet.ID = w.bulletID
+		w.bulletID++
+		w.bullets[bullet.ID] = bullet
+	}
+}
+
+// falloffMultiplier scales damage down linearly with the share of its flight the bullet has covered
+func (bullet *Bullet) falloffMultiplier() float64 {
+	if bullet.Falloff <= 0 || bullet.FlightRange <= 0 {
+		return 1
+	}
+	return 1 - bullet.Falloff*math.Min(bullet.Traveled/bullet.FlightRange, 1)
+}
+
+// reflectOffBounds mirrors a bullet back into the world, consuming one bounce per
+// wall hit. Returns false if the bullet has no bounces left and should expire.
+func (bullet *Bullet) reflectOffBounds() bool {
+	if bullet.X < 0 || bullet.X > WorldWidth {
+		if bullet.Bounces <= 0 {
+			return false
+		}
+		bullet.Bounces--
+		bullet.VelX = -bullet.VelX
+		bullet.X = math.Max(0, math.Min(WorldWidth, bullet.X))
+	}
+
+	if bullet.Y < 0 || bullet.Y > WorldHeight {
+		if bullet.Bounces <= 0 {
+			return false
+		}
+		bullet.Bounces--
+		bullet.VelY = -bullet.VelY
+		bullet.Y = math.Max(0, math.Min(WorldHeight, bullet.Y))
+	}
+
+	// The trail restarts at the wall
+	bullet.OriginX, bullet.OriginY = bullet.X, bullet.Y
+	return true
+}
+
+// interceptBullets removes interceptor bullets and the opposing bullets they overlap
+func (w *World) interceptBullets(bulletsToDelete []uint32) []uint32 {
+	removed := make(map[uint32]bool, len(bulletsToDelete))
+	for _, id := range bulletsToDelete {
+		removed[id] = true
+	}
+
+	for id, interceptor := range w.bullets {
+		if !interceptor.Interceptor || removed[id] {
+			continue
+		}
+
+		for otherID, other := range w.bullets {
+			// Only opposing bullets that are still in flight
+			if otherID == id || other.OwnerID == interceptor.OwnerID || removed[otherID] {
+				continue
+			}
+
+			dx := interceptor.X - other.X
+			dy := interceptor.Y - other.Y
+			radii := interceptor.Radius + other.Radius
+			if dx*dx+dy*dy <= radii*radii {
+				removed[id] = true
+				removed[otherID] = true
+				bulletsToDelete = append(bulletsToDelete, id, otherID)
+				break
+			}
+		}
+	}
+
+	return bulletsToDelete
+}
+
+// applySplashDamage damages every other ship near a hit, scaling linearly from
+// full damage at the impact point to zero at the edge of the splash radius
+func (w *World) applySplashDamage(bullet *Bullet, directTarget *Player, attacker *Player, damage float64, now time.Time) {
+	radiusSq := bullet.SplashRadius * bullet.SplashRadius
+
+	for playerID, player := range w.players {
+		if player == directTarget || playerID == bullet.OwnerID || player.State != StateAlive {
+			continue
+		}
+		if attacker != nil && isTeammate(attacker, player) {
+			continue
+		}
+
+		dx := player.X - bullet.X
+		dy := player.Y - bullet.Y
+		distSq := dx*dx + dy*dy
+		if distSq >= radiusSq {
+			continue
+		}
+
+		falloff := 1.0 - math.Sqrt(distSq)/bullet.SplashRadius
+		w.mechanics.ApplyDamage(player, damage*falloff, attacker, KillCauseBullet, now)
+	}
+}
+
+// borderDepth returns how far into the border strip a position is, from 0 at
+// the inner edge of the strip to 1 at the world edge, and the unit direction
+// pointing back toward the playable area
+func (w *World) borderDepth(x, y float64) (depth, pushX, pushY float64) {
+	margin := w.config.BorderMargin
+	if margin <= 0 {
+		return 0, 0, 0
+	}
+
+	if d := (margin - x) / margin; d > depth {
+		depth, pushX, pushY = d, 1, 0
+	}
+	if d := (x - (WorldWidth - margin)) / margin; d > depth {
+		depth, pushX, pushY = d, -1, 0
+	}
+	if d := (margin - y) / margin; d > depth {
+		depth, pushX, pushY = d, 0, 1
+	}
+	if d := (y - (WorldHeight - margin)) / margin; d > depth {
+		depth, pushX, pushY = d, 0, -1
+	}
+
+	return math.Min(depth, 1), pushX, pushY
+}
+
+// applyBorderHazard damages ships inside the border strip, scaling with how
+// deep they are, and nudges them back toward the middle of the map
+func (w *World) applyBorderHazard(now time.Time) {
+	if w.config.BorderDamagePerSec <= 0 && w.config.BorderPushForce <= 0 {
+		return
+	}
+
+	for _, player := range w.players {
+		if player.State != StateAlive {
+			continue
+		}
+
+		depth, pushX, pushY := w.borderDepth(player.X, player.Y)
+		if depth <= 0 {
+			continue
+		}
+
+		// Move the ship directly; velocity is recomputed from the throttle next tick
+		if w.config.BorderPushForce > 0 {
+			step := w.config.BorderPushForce * depth * w.config.tickSeconds()
+			player.X += pushX * step
+			player.Y += pushY * step
+		}
+
+		if w.config.BorderDamagePerSec > 0 {
+			damage := w.config.BorderDamagePerSec * depth * w.config.tickSeconds()
+			w.mechanics.ApplyDamage(player, damage, nil, KillCauseBorder, now)
+		}
+	}
+}
+
+// updateBurning deals periodic burn damage to players that are on fire
+func (w *World) updateBurning(now time.Time) {
+	burnDamage := BurnDamagePerSecond * w.config.tickSeconds()
+
+	for _, player := range w.players {
+		if !player.Burning {
+			continue
+		}
+
+		if player.State != StateAlive || now.After(player.BurningUntil) {
+			player.clearBurning()
+			continue
+		}
+
+		// Attacker may have left the game; the burn still applies without credit
+		attacker := w.players[player.BurnSourceID]
+		w.mechanics.ApplyDamage(player, burnDamage, attacker, KillCauseBullet, now)
 	}
 }
 
@@ -631,64 +1497,161 @@ func (w *World) fireModularUpgrades(player *Player, input *InputMsg, now time.Ti
 		return
 	}
 
+	// Aimed autofire holds each weapon until it points at an enemy; a manual
+	// shot always fires everything
+	onTarget := player.AimedAutofire && !input.ManualFire
+
 	// Clear manual fire flag after processing
 	if input.ManualFire {
 		input.ManualFire = false
+
+		// Throttle manual fire requests independently of cannon reloads
+		if client, exists := w.clients[player.ID]; exists && !player.AutofireEnabled {
+			if now.Sub(client.LastManualFire) < ManualFireCooldown {
+				return
+			}
+			client.LastManualFire = now
+		}
 	}
 
-	w.fireSideUpgrade(player, now)
-	w.fireTopUpgrade(player, now)
-	w.fireFrontUpgrade(player, now)
-	w.fireRearUpgrade(player, now)
+	w.fireSideUpgrade(player, Position{X: input.Mouse.X, Y: input.Mouse.Y}, onTarget, now)
+	w.fireTopUpgrade(player, onTarget, now)
+	w.fireFrontUpgrade(player, onTarget, now)
+	w.fireRearUpgrade(player, onTarget, now)
+}
+
+// bulletRoom returns how many more bullets the owner may put in the world
+// before hitting the per-owner or world-wide cap. Cannons check it before
+// firing, so a capped ship holds its fire until older bullets expire.
+func (w *World) bulletRoom(ownerID uint32) int {
+	room := math.MaxInt
+	if w.config.MaxBulletsPerPlayer > 0 {
+		room = min(room, w.config.MaxBulletsPerPlayer-w.bulletsByOwner[ownerID])
+	}
+	if w.config.MaxBullets > 0 {
+		room = min(room, w.config.MaxBullets-len(w.bullets))
+	}
+	return max(room, 0)
 }
 
-// registerBullets adds the emitted bullets to the world map in one place.
+// registerBullets adds fired bullets to the world map in one place
 func (w *World) registerBullets(bullets []*Bullet) {
 	for _, bullet := range bullets {
 		w.bullets[bullet.ID] = bullet
+		w.bulletsByOwner[bullet.OwnerID]++
+	}
+}
+
+// removeBullet deletes a bullet and releases its slot under its owner's cap
+func (w *World) removeBullet(bulletID uint32) {
+	bullet, exists := w.bullets[bulletID]
+	if !exists {
+		return
+	}
+	delete(w.bullets, bulletID)
+	if w.bulletsByOwner[bullet.OwnerID] <= 1 {
+		delete(w.bulletsByOwner, bullet.OwnerID)
+	} else {
+		w.bulletsByOwner[bullet.OwnerID]--
 	}
 }
 
-// fireCannons iterates a list of cannons and fires them using their configured angles.
-func (w *World) fireCannons(player *Player, cannons []*Cannon, now time.Time) bool {
+// cannonAim picks the world angle a cannon fires at, or false to hold its fire
+type cannonAim func(cannon *Cannon) (angle float64, ok bool)
+
+// fixedAim fires every cannon along its configured angle
+func fixedAim(player *Player) cannonAim {
+	return func(cannon *Cannon) (float64, bool) {
+		return player.Angle + cannon.Angle, true
+	}
+}
+
+// onTargetAim wraps aim so a cannon only fires when its shot would head
+// toward an enemy within its range
+func (w *World) onTargetAim(player *Player, aim cannonAim) cannonAim {
+	return func(cannon *Cannon) (float64, bool) {
+		angle, ok := aim(cannon)
+		if !ok || !w.enemyAlong(player, angle, cannonRange(player, cannon.Stats), AimedAutofireArc) {
+			return 0, false
+		}
+		return angle, true
+	}
+}
+
+// enemyAlong reports whether a live enemy within reach lies within arc of a
+// world angle from the player
+func (w *World) enemyAlong(player *Player, angle, reach, arc float64) bool {
+	for _, other := range w.players {
+		if other == player || other.State != StateAlive || isTeammate(player, other) {
+			continue
+		}
+		dx, dy := other.X-player.X, other.Y-player.Y
+		if math.Hypot(dx, dy) > reach {
+			continue
+		}
+		if math.Abs(normalizeAngle(math.Atan2(dy, dx)-angle)) <= arc {
+			return true
+		}
+	}
+	return false
+}
+
+// fireCannons iterates a list of cannons and fires each at the angle aim picks.
+func (w *World) fireCannons(player *Player, cannons []*Cannon, aim cannonAim, now time.Time) bool {
 	fired := false
+	volley := 0
 	for _, cannon := range cannons {
 		// Skip non-firing equipment such as oars
 		if cannon.Type == WeaponTypeRow {
 			continue
 		}
 
-		angle := player.Angle + cannon.Angle
+		angle, ok := aim(cannon)
+		if !ok {
+			continue
+		}
 		bullets := cannon.Fire(w, player, angle, now)
 		if len(bullets) == 0 {
 			continue
 		}
 
-		w.registerBullets(bullets)
+		cannon.staggerRecoil(volley)
+		volley++
 		fired = true
 	}
 
 	return fired
 }
 
-// fireTurrets iterates a list of turrets and registers emitted bullets.
-func (w *World) fireTurrets(player *Player, turrets []*Turret, now time.Time) bool {
+// fireTurrets fires each turret in a list and reports whether any did. With
+// onTarget, turrets not currently aimed at an enemy in range hold their fire.
+func (w *World) fireTurrets(player *Player, turrets []*Turret, onTarget bool, now time.Time) bool {
 	fired := false
 	for i := range turrets {
-		bullets := turrets[i].Fire(w, player, now)
-		if len(bullets) == 0 {
+		if onTarget && !w.turretOnTarget(player, turrets[i]) {
 			continue
 		}
-
-		w.registerBullets(bullets)
-		fired = true
+		if len(turrets[i].Fire(w, player, now)) > 0 {
+			fired = true
+		}
 	}
 
 	return fired
 }
 
-// fireSideUpgrade fires side-mounted cannons from the single side upgrade
-func (w *World) fireSideUpgrade(player *Player, now time.Time) bool {
+// turretOnTarget reports whether a turret points at an enemy its longest
+// reaching cannon can hit
+func (w *World) turretOnTarget(player *Player, turret *Turret) bool {
+	reach := 0.0
+	for _, cannon := range turret.Cannons {
+		reach = math.Max(reach, cannonRange(player, cannon.Stats))
+	}
+	return w.enemyAlong(player, turret.Angle, reach, AimedAutofireArc)
+}
+
+// fireSideUpgrade fires side-mounted cannons from the single side upgrade,
+// aimed according to the configured side cannon mode
+func (w *World) fireSideUpgrade(player *Player, mouse Position, onTarget bool, now time.Time) bool {
 	if player.ShipConfig.SideUpgrade == nil {
 		return false
 	}
@@ -703,67 +1666,148 @@ func (w *World) fireSideUpgrade(player *Player, now time.Time) bool {
 		return false
 	}
 
-	return w.fireCannons(player, upgrade.Cannons, now)
+	aim := fixedAim(player)
+	switch w.config.SideCannonMode {
+	case SideCannonsTarget:
+		aim = w.broadsideTargetAim(player)
+	case SideCannonsAim:
+		aim = mouseArcAim(player, mouse, w.config.SideCannonArc)
+	}
+	if onTarget {
+		aim = w.onTargetAim(player, aim)
+	}
+	if w.config.SideCannonRipple > 0 {
+		return w.rippleFire(player, upgrade, aim, now)
+	}
+	return w.fireCannons(player, upgrade.Cannons, aim, now)
 }
 
-// fireTopUpgrade fires top-mounted turrets from the single top upgrade
-func (w *World) fireTopUpgrade(player *Player, now time.Time) bool {
-	if player.ShipConfig.TopUpgrade == nil || player.ShipConfig.TopUpgrade.Type != UpgradeTypeTop {
+// rippleFire fires the next pair of side cannons (one per side) in the
+// module's sequence once the ripple delay has passed since the previous pair.
+// A pair that isn't ready holds up the sequence so the order never changes.
+func (w *World) rippleFire(player *Player, upgrade *ShipModule, aim cannonAim, now time.Time) bool {
+	if now.Before(upgrade.nextRippleShot) {
+		return false
+	}
+
+	cannonCount := len(upgrade.Cannons) / 2
+	index := upgrade.rippleIndex % cannonCount
+	pair := []*Cannon{upgrade.Cannons[index], upgrade.Cannons[cannonCount+index]}
+	if !w.fireCannons(player, pair, aim, now) {
 		return false
 	}
 
-	upgrade := player.ShipConfig.TopUpgrade
-	return w.fireTurrets(player, upgrade.Turrets, now)
+	upgrade.rippleIndex = (index + 1) % cannonCount
+	upgrade.nextRippleShot = now.Add(w.config.SideCannonRipple)
+	return true
+}
+
+// broadsideTargetAim holds a side cannon's fire until a live enemy is within
+// its firing arc and range, then fires a straight broadside
+func (w *World) broadsideTargetAim(player *Player) cannonAim {
+	return func(cannon *Cannon) (float64, bool) {
+		broadside := player.Angle + cannon.Angle
+		if w.enemyAlong(player, broadside, cannonRange(player, cannon.Stats), w.config.SideCannonArc) {
+			return broadside, true
+		}
+		return 0, false
+	}
+}
+
+// mouseArcAim turns each side cannon toward the mouse, but no further than
+// arc from its broadside. Cannons on the side facing away keep a straight broadside.
+func mouseArcAim(player *Player, mouse Position, arc float64) cannonAim {
+	toMouse := math.Atan2(mouse.Y-player.Y, mouse.X-player.X)
+	return func(cannon *Cannon) (float64, bool) {
+		broadside := player.Angle + cannon.Angle
+		offset := normalizeAngle(toMouse - broadside)
+		if math.Abs(offset) > math.Pi/2 {
+			return broadside, true
+		}
+		return broadside + clampfloat64(offset, -arc, arc), true
+	}
+}
+
+// fireTopUpgrade fires top-mounted turrets from every turret slot
+func (w *World) fireTopUpgrade(player *Player, onTarget bool, now time.Time) bool {
+	fired := false
+	for _, upgrade := range player.ShipConfig.TopModules() {
+		if upgrade.Type != UpgradeTypeTop {
+			continue
+		}
+		if w.fireTurrets(player, upgrade.Turrets, onTarget, now) {
+			fired = true
+		}
+	}
+	return fired
 }
 
 // fireFrontUpgrade fires front-mounted weapons from the single front upgrade
-func (w *World) fireFrontUpgrade(player *Player, now time.Time) bool {
+func (w *World) fireFrontUpgrade(player *Player, onTarget bool, now time.Time) bool {
 	if player.ShipConfig.FrontUpgrade == nil || player.ShipConfig.FrontUpgrade.Type != UpgradeTypeFront {
 		return false
 	}
 
 	upgrade := player.ShipConfig.FrontUpgrade
-	firedCannons := w.fireCannons(player, upgrade.Cannons, now)
-	firedTurrets := w.fireTurrets(player, upgrade.Turrets, now)
+	aim := fixedAim(player)
+	if onTarget {
+		aim = w.onTargetAim(player, aim)
+	}
+	firedCannons := w.fireCannons(player, upgrade.Cannons, aim, now)
+	firedTurrets := w.fireTurrets(player, upgrade.Turrets, onTarget, now)
 
 	return firedCannons || firedTurrets
 }
 
 // fireRearUpgrade fires rear-mounted weapons from the single rear upgrade
-func (w *World) fireRearUpgrade(player *Player, now time.Time) bool {
+func (w *World) fireRearUpgrade(player *Player, onTarget bool, now time.Time) bool {
 	if player.ShipConfig.RearUpgrade == nil || player.ShipConfig.RearUpgrade.Type != UpgradeTypeRear {
 		return false
 	}
 
 	upgrade := player.ShipConfig.RearUpgrade
-	firedCannons := w.fireCannons(player, upgrade.Cannons, now)
-	firedTurrets := w.fireTurrets(player, upgrade.Turrets, now)
+	aim := fixedAim(player)
+	if onTarget {
+		aim = w.onTargetAim(player, aim)
+	}
+	firedCannons := w.fireCannons(player, upgrade.Cannons, aim, now)
+	firedTurrets := w.fireTurrets(player, upgrade.Turrets, onTarget, now)
 
 	return firedCannons || firedTurrets
 }
 
 // updateModularTurretAiming updates turret aiming using the new modular system
-func (w *World) updateModularTurretAiming(player *Player, input *InputMsg) {
+func (w *World) updateModularTurretAiming(player *Player, input *InputMsg, dt float64) {
 	mouseWorldX := input.Mouse.X
 	mouseWorldY := input.Mouse.Y
 
 	// Update turrets in all upgrade categories
-	upgrades := []*ShipModule{player.ShipConfig.TopUpgrade, player.ShipConfig.FrontUpgrade, player.ShipConfig.RearUpgrade}
+	upgrades := []*ShipModule{player.ShipConfig.TopUpgrade, player.ShipConfig.ExtraTop, player.ShipConfig.FrontUpgrade, player.ShipConfig.RearUpgrade}
 
 	for _, upgrade := range upgrades {
 		if upgrade != nil {
 			for i := range upgrade.Turrets {
 				turret := upgrade.Turrets[i]
-				turret.UpdateAiming(player, mouseWorldX, mouseWorldY)
+				turret.UpdateAiming(player, mouseWorldX, mouseWorldY, dt)
 			}
 		}
 	}
 }
 
+// shipLengthFactor scales turning down for longer ships; a ship with no valid
+// length (zero, negative or NaN) turns like a base ship instead of dividing by zero
+func shipLengthFactor(player *Player) float64 {
+	baseShipLength := float64(PlayerSize * 1.2) // 1 cannon ship has no length multiplier
+	shipLength := player.ShipConfig.ShipLength
+	if !(shipLength > 0) || math.IsInf(shipLength, 0) {
+		return 1
+	}
+	return baseShipLength / shipLength // Longer ships get smaller factor
+}
+
 // calculateDebugInfo computes debug values for client display
 func (w *World) calculateDebugInfo(player *Player) DebugInfo {
-	baseShipLength := float64(PlayerSize * 1.2)                   // 1 cannon ship has no length multiplier
-	lengthFactor := baseShipLength / player.ShipConfig.ShipLength // Longer ships get smaller factor
+	lengthFactor := shipLengthFactor(player)
 	debugInfo := DebugInfo{
 		Health:            player.MaxHealth,
 		RegenRate:         player.Modifiers.HealthRegenPerSec,
@@ -777,64 +1821,88 @@ func (w *World) calculateDebugInfo(player *Player) DebugInfo {
 		TotalDPS:          0,
 	}
 
-	// Calculate DPS from all cannons
-	cannonDamageMod := player.Modifiers.BulletDamageMultiplier
-	reloadSpeedMod := player.Modifiers.ReloadSpeedMultiplier
-
-	// Calculate DPS for each upgrade type
+	// Calculate DPS and range for each upgrade type
 	if player.ShipConfig.FrontUpgrade != nil {
 		for _, cannon := range player.ShipConfig.FrontUpgrade.Cannons {
-			damage := float64(cannon.Stats.BulletDamageMod * BulletDamage)
-			reloadRate := cannon.Stats.ReloadTime
-			effectiveDamage := damage * (cannonDamageMod)
-			effectiveReloadRate := reloadRate * (reloadSpeedMod)
-			if effectiveReloadRate > 0 {
-				debugInfo.FrontDPS += effectiveDamage * 1 / effectiveReloadRate
-			}
+			debugInfo.FrontDPS += cannonDPS(player, cannon.Stats, w.config.SpreadHitFactor)
+			debugInfo.FrontRange = math.Max(debugInfo.FrontRange, cannonRange(player, cannon.Stats))
 		}
 	}
 
 	if player.ShipConfig.SideUpgrade != nil {
 		for _, cannon := range player.ShipConfig.SideUpgrade.Cannons {
-			damage := float64(cannon.Stats.BulletDamageMod * BulletDamage)
-			reloadRate := cannon.Stats.ReloadTime
-			effectiveDamage := damage * (cannonDamageMod)
-			effectiveReloadRate := reloadRate * (reloadSpeedMod)
-			if effectiveReloadRate > 0 {
-				debugInfo.SideDPS += effectiveDamage * 1 / effectiveReloadRate
-			}
+			debugInfo.SideDPS += cannonDPS(player, cannon.Stats, w.config.SpreadHitFactor)
+			debugInfo.SideRange = math.Max(debugInfo.SideRange, cannonRange(player, cannon.Stats))
 		}
 	}
 
 	if player.ShipConfig.RearUpgrade != nil {
 		for _, cannon := range player.ShipConfig.RearUpgrade.Cannons {
-			damage := float64(cannon.Stats.BulletDamageMod * BulletDamage)
-			reloadRate := cannon.Stats.ReloadTime
-			effectiveDamage := damage * (cannonDamageMod)
-			effectiveReloadRate := reloadRate * (reloadSpeedMod)
-			if effectiveReloadRate > 0 {
-				debugInfo.RearDPS += effectiveDamage * 1 / effectiveReloadRate
-			}
+			debugInfo.RearDPS += cannonDPS(player, cannon.Stats, w.config.SpreadHitFactor)
+			debugInfo.RearRange = math.Max(debugInfo.RearRange, cannonRange(player, cannon.Stats))
 		}
 	}
 
-	if player.ShipConfig.TopUpgrade != nil {
-		for _, turret := range player.ShipConfig.TopUpgrade.Turrets {
+	for _, upgrade := range player.ShipConfig.TopModules() {
+		for _, turret := range upgrade.Turrets {
 			// only calculated based on first cannon
 			// machine gun dual cannon shares reload
 			turretCannon := turret.Cannons[0]
 
-			damage := float64(turretCannon.Stats.BulletDamageMod * BulletDamage)
-			reloadRate := turretCannon.Stats.ReloadTime
-			effectiveDamage := damage * (cannonDamageMod)
-			effectiveReloadRate := reloadRate * (reloadSpeedMod)
-			if effectiveReloadRate > 0 {
-				debugInfo.TopDPS += effectiveDamage * 1 / effectiveReloadRate
-			}
+			debugInfo.TopDPS += cannonDPS(player, turretCannon.Stats, w.config.SpreadHitFactor)
+			debugInfo.TopRange = math.Max(debugInfo.TopRange, cannonRange(player, turretCannon.Stats))
 		}
 	}
 
 	debugInfo.TotalDPS = debugInfo.FrontDPS + debugInfo.SideDPS + debugInfo.RearDPS + debugInfo.TopDPS
 
+	debugInfo.FrontDPS = roundDebugValue(debugInfo.FrontDPS)
+	debugInfo.SideDPS = roundDebugValue(debugInfo.SideDPS)
+	debugInfo.RearDPS = roundDebugValue(debugInfo.RearDPS)
+	debugInfo.TopDPS = roundDebugValue(debugInfo.TopDPS)
+	debugInfo.TotalDPS = roundDebugValue(debugInfo.TotalDPS)
+	debugInfo.FrontRange = roundDebugValue(debugInfo.FrontRange)
+	debugInfo.SideRange = roundDebugValue(debugInfo.SideRange)
+	debugInfo.RearRange = roundDebugValue(debugInfo.RearRange)
+	debugInfo.TopRange = roundDebugValue(debugInfo.TopRange)
+
 	return debugInfo
 }
+
+// cannonDPS returns a cannon's damage per second including every bullet it fires per shot.
+// Spread shots count their extra bullets at spreadHitFactor, the chance each one hits.
+func cannonDPS(player *Player, stats CannonStats, spreadHitFactor float64) float64 {
+	damage := float64(stats.BulletDamageMod*BulletDamage) * expectedHits(stats, spreadHitFactor)
+	effectiveDamage := damage * player.Modifiers.BulletDamageMultiplier
+	effectiveReloadRate := stats.averageShotInterval() * player.Modifiers.ReloadSpeedMultiplier
+	if effectiveReloadRate <= 0 {
+		return 0
+	}
+	return effectiveDamage / effectiveReloadRate
+}
+
+// expectedHits returns how many of a shot's bullets are expected to land: the
+// center bullet always counts, spread bullets count at spreadHitFactor
+func expectedHits(stats CannonStats, spreadHitFactor float64) float64 {
+	if stats.BulletCount <= 1 || stats.SpreadAngle <= 0 {
+		return float64(stats.BulletCount)
+	}
+	factor := math.Max(0, math.Min(spreadHitFactor, 1))
+	return 1 + float64(stats.BulletCount-1)*factor
+}
+
+// cannonRange returns how far a cannon's bullets travel before expiring
+func cannonRange(player *Player, stats CannonStats) float64 {
+	bulletSpeed := BulletSpeed * stats.BulletSpeedMod * player.Modifiers.BulletSpeedMultiplier
+	travel := bulletSpeed * BulletLifetime
+	if stats.Range > 0 {
+		return math.Min(travel, stats.Range)
+	}
+	return travel
+}
+
+// roundDebugValue rounds a debug stat to DebugInfoDecimals places
+func roundDebugValue(value float64) float64 {
+	scale := math.Pow(10, DebugInfoDecimals)
+	return math.Round(value*scale) / scale
+}
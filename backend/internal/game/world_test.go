@@ -0,0 +1,806 @@
+package game
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReturnToLobbyThenStartGameKeepsProgress(t *testing.T) {
+	w := newTestWorld(t, nil)
+	client := addTestClient(t, w, 1000, 1000)
+	player := client.Player
+
+	w.mu.Lock()
+	turret := NewBasicTurrets(1).Name
+	if !player.ShipConfig.ApplyModule(UpgradeTypeTop, turret) {
+		w.mu.Unlock()
+		t.Fatal("could not fit a turret")
+	}
+	player.updateShipGeometry()
+	player.Score = 1234
+	player.Health = player.MaxHealth / 2
+	health := player.Health
+
+	if !w.returnToLobby(player, time.Now()) {
+		w.mu.Unlock()
+		t.Fatal("returnToLobby refused a player out of combat")
+	}
+	w.mu.Unlock()
+
+	if player.State != StateDead || !player.InLobby {
+		t.Fatalf("after returnToLobby: state %d, inLobby %v", player.State, player.InLobby)
+	}
+
+	w.HandleInput(client.ID, InputMsg{Type: "startGame", StartGame: true})
+
+	if player.State != StateAlive {
+		t.Fatalf("state after startGame = %d, want alive", player.State)
+	}
+	if player.ShipConfig.TopUpgrade == nil || player.ShipConfig.TopUpgrade.Name != turret {
+		t.Errorf("top module after startGame = %v, want %q", player.ShipConfig.TopUpgrade, turret)
+	}
+	if player.Health != health {
+		t.Errorf("health after startGame = %v, want %v", player.Health, health)
+	}
+	if player.Score != 1234 {
+		t.Errorf("score after startGame = %d, want 1234", player.Score)
+	}
+}
+
+func TestReturnToLobbyBlockedUnderFire(t *testing.T) {
+	w := newTestWorld(t, nil)
+	client := addTestClient(t, w, 1000, 1000)
+	now := time.Now()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	client.Player.RecentDamagers = map[uint32]DamageContribution{99: {Amount: 10, LastHit: now}}
+	if w.returnToLobby(client.Player, now.Add(LobbyCombatLock/2)) {
+		t.Fatal("returnToLobby allowed while under fire")
+	}
+	if !w.returnToLobby(client.Player, now.Add(LobbyCombatLock)) {
+		t.Fatal("returnToLobby refused once the combat lock expired")
+	}
+}
+
+func TestBorderPushMovesShipInward(t *testing.T) {
+	w := newTestWorld(t, func(config *WorldConfig) {
+		config.BorderMargin = 200
+		config.BorderPushForce = 300
+	})
+	client := addTestClient(t, w, 50, 1000)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.applyBorderHazard(time.Now())
+	if client.Player.X <= 50 {
+		t.Fatalf("X after border push = %v, want > 50", client.Player.X)
+	}
+}
+
+func TestFirstSpawnIsProtected(t *testing.T) {
+	w := newTestWorld(t, nil)
+	client := NewClient(0, nil)
+	if !w.AddClient(client) {
+		t.Fatal("AddClient refused the client")
+	}
+
+	w.HandleInput(client.ID, InputMsg{Type: "startGame", StartGame: true})
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !client.Player.Protected || !client.Player.isSpawnProtected(time.Now()) {
+		t.Error("player is not spawn protected after setting sail")
+	}
+}
+
+func TestWorldsWithTheSameSeedMatch(t *testing.T) {
+	build := func() (*World, *Player) {
+		w := newTestWorld(t, func(config *WorldConfig) {
+			config.Seed = 42
+		})
+		client := NewClient(0, nil)
+		if !w.AddClient(client) {
+			t.Fatal("AddClient refused the client")
+		}
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		w.mechanics.SpawnFoodItems(50)
+		return w, client.Player
+	}
+	first, firstPlayer := build()
+	second, secondPlayer := build()
+
+	if firstPlayer.Name != secondPlayer.Name || firstPlayer.Color != secondPlayer.Color {
+		t.Errorf("players differ: %q %s vs %q %s",
+			firstPlayer.Name, firstPlayer.Color, secondPlayer.Name, secondPlayer.Color)
+	}
+	if len(first.items) == 0 || len(first.items) != len(second.items) {
+		t.Fatalf("spawned %d and %d items", len(first.items), len(second.items))
+	}
+	for id, item := range first.items {
+		other := second.items[id]
+		if other == nil || other.Type != item.Type || other.X != item.X || other.Y != item.Y {
+			t.Fatalf("item %d differs: %+v vs %+v", id, item, other)
+		}
+	}
+}
+
+func TestBouncedBulletTrailStartsAtTheWall(t *testing.T) {
+	bullet := &Bullet{X: WorldWidth + 5, Y: 300, VelX: 400, OriginX: WorldWidth - 200, OriginY: 300, Ricochet: true, Bounces: 1}
+	if !bullet.reflectOffBounds() {
+		t.Fatal("bullet with a bounce left expired")
+	}
+	if bullet.VelX != -400 || bullet.OriginX != WorldWidth || bullet.OriginY != 300 {
+		t.Errorf("after bounce: velX %v, origin (%v, %v); want -400 from (%v, 300)",
+			bullet.VelX, bullet.OriginX, bullet.OriginY, WorldWidth)
+	}
+}
+
+func TestSpecialSpawnDropsRareItemsUpToItsCap(t *testing.T) {
+	w := newTestWorld(t, nil)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.items = make(map[uint32]*GameItem)
+
+	w.mechanics.SpawnSpecialItems()
+	if len(w.items) != SpecialItemsPerSpawn {
+		t.Fatalf("special spawn made %d items, want %d", len(w.items), SpecialItemsPerSpawn)
+	}
+	for _, item := range w.items {
+		if item.Type != ItemTypeBlueDiamond && item.Type != ItemTypeGoldStar {
+			t.Errorf("special spawn made a %q", item.Type)
+		}
+	}
+
+	for range MaxSpecialItems {
+		w.mechanics.SpawnSpecialItems()
+	}
+	if len(w.items) != MaxSpecialItems {
+		t.Errorf("%d special items after repeated spawns, want the cap of %d", len(w.items), MaxSpecialItems)
+	}
+
+	// Gold stars only come from the special spawn
+	w.items = make(map[uint32]*GameItem)
+	w.mechanics.SpawnFoodItems(MaxItems)
+	for _, item := range w.items {
+		if item.Type == ItemTypeGoldStar {
+			t.Fatal("food spawn made a gold star")
+		}
+	}
+}
+
+func TestWorldStartsAndStopsRepeatedly(t *testing.T) {
+	for i := range 20 {
+		w := newTestWorld(t, nil)
+		addTestClient(t, w, 1000, 1000)
+		go w.Start()
+		// Alternate between stopping at once, racing Start, and after a few ticks
+		if i%2 == 1 {
+			time.Sleep(time.Duration(i) * time.Millisecond)
+		}
+		w.Stop()
+		w.Stop()
+
+		select {
+		case <-w.Done():
+		case <-time.After(time.Second):
+			t.Fatalf("world %d still running after Stop", i)
+		}
+		w.mu.Lock()
+		running := w.running
+		w.mu.Unlock()
+		if running {
+			t.Errorf("world %d reports running after Stop", i)
+		}
+	}
+
+	// A world stopped before it starts never runs
+	w := newTestWorld(t, nil)
+	w.Stop()
+	w.Start()
+	<-w.Done()
+}
+
+func TestShipsCoverTheSameGroundAtAnyTickRate(t *testing.T) {
+	// oneSecond steers a ship for one second of simulation at the given rate
+	oneSecond := func(tickRate int, input InputMsg) (distance, turned float64) {
+		w := newTestWorld(t, func(config *WorldConfig) {
+			config.TickRate = tickRate
+		})
+		player := addTestClient(t, w, 2000, 2000).Player
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		startX, startY, startAngle := player.X, player.Y, player.Angle
+		for range tickRate {
+			w.updatePlayer(player, &input)
+		}
+		return math.Hypot(player.X-startX, player.Y-startY), player.Angle - startAngle
+	}
+
+	slowDistance, _ := oneSecond(30, InputMsg{})
+	fastDistance, _ := oneSecond(60, InputMsg{})
+	if slowDistance == 0 || math.Abs(slowDistance-fastDistance) > 1e-6 {
+		t.Errorf("ship sailed %v at 30 TPS and %v at 60 TPS", slowDistance, fastDistance)
+	}
+
+	_, slowTurn := oneSecond(30, InputMsg{Left: true})
+	_, fastTurn := oneSecond(60, InputMsg{Left: true})
+	if slowTurn == 0 || math.Abs(slowTurn-fastTurn) > 1e-6 {
+		t.Errorf("ship turned %v at 30 TPS and %v at 60 TPS", slowTurn, fastTurn)
+	}
+}
+
+func TestAutoRepairsRegenerateTheirRateEachSecond(t *testing.T) {
+	const repairLevel = 5
+	wantPerSecond := 1.0 + repairLevel*0.6
+
+	for _, tickRate := range []int{30, 60} {
+		w := newTestWorld(t, func(config *WorldConfig) {
+			config.TickRate = tickRate
+		})
+		player := addTestClient(t, w, 2000, 2000).Player
+		w.mu.Lock()
+		upgrade := player.Upgrades[StatUpgradeAutoRepairs]
+		upgrade.Level = repairLevel
+		player.Upgrades[StatUpgradeAutoRepairs] = upgrade
+		player.updateModifiers()
+		player.Health = player.MaxHealth / 2
+		before := player.Health
+		for range tickRate {
+			w.updatePlayer(player, &InputMsg{})
+		}
+		regenerated := player.Health - before
+		w.mu.Unlock()
+
+		if math.Abs(regenerated-wantPerSecond) > 1e-6 {
+			t.Errorf("at %d TPS regenerated %v HP in a second, want %v", tickRate, regenerated, wantPerSecond)
+		}
+	}
+}
+
+func TestTeleportIsClampedWhileSailingAndPushesPass(t *testing.T) {
+	w := newTestWorld(t, nil)
+	client := addTestClient(t, w, 2000, 2000)
+	player := client.Player
+	stop := make(chan struct{})
+	defer close(stop)
+	go drainClient(client, stop)
+	w.update()
+
+	w.mu.Lock()
+	maxStep := BaseShipMaxSpeed * player.Modifiers.MoveSpeedMultiplier * w.config.tickSeconds()
+	step := maxStep * ShipDeceleration // Ships cruise a little under top speed
+	w.mu.Unlock()
+
+	// moved runs a tick after change and returns how far the ship got from
+	// where it ended the previous tick
+	moved := func(change func()) float64 {
+		w.mu.Lock()
+		startX, startY := player.X, player.Y
+		change()
+		w.mu.Unlock()
+		w.update()
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if player.LastValidX != player.X || player.LastValidY != player.Y {
+			t.Errorf("last valid position (%v, %v) isn't where the ship ended (%v, %v)",
+				player.LastValidX, player.LastValidY, player.X, player.Y)
+		}
+		return math.Hypot(player.X-startX, player.Y-startY)
+	}
+
+	if distance := moved(func() {}); math.Abs(distance-step) > 1e-9 {
+		t.Errorf("normal sailing moved %v, want %v", distance, step)
+	}
+	if distance := moved(func() { player.X += 500 }); distance > maxStep*MaxMovementSpeedFactor+1e-9 {
+		t.Errorf("teleport of 500 moved the ship %v, want at most %v", distance, maxStep*MaxMovementSpeedFactor)
+	}
+
+	// A server-side push between ticks counts as where the ship really is
+	w.mu.Lock()
+	player.X += 40
+	w.recordValidPositions()
+	w.mu.Unlock()
+	if distance := moved(func() {}); math.Abs(distance-step) > 1e-9 {
+		t.Errorf("sailing after a push moved %v, want %v", distance, step)
+	}
+}
+
+func TestEmotesReachNearbyClientsAndUnknownOnesAreDropped(t *testing.T) {
+	w := newTestWorld(t, nil)
+	sender := addTestClient(t, w, 1000, 1000)
+	near := addTestClient(t, w, 1000+EmoteRange/2, 1000)
+	far := addTestClient(t, w, 1000+EmoteRange*2, 1000)
+
+	// emotes empties each client's buffer and returns the emotes it received
+	emotes := func(client *Client) []EmoteMsg {
+		var received []EmoteMsg
+		for _, data := range queuedMessages(client) {
+			var emote EmoteMsg
+			if decodeTestMsg(data, &emote) && emote.Type == MsgTypeEmote {
+				received = append(received, emote)
+			}
+		}
+		return received
+	}
+	send := func(sequence uint32, emote string) {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		w.processPlayerActions(sender.Player, &InputMsg{Actions: []InputAction{{Type: "emote", Sequence: sequence, Data: emote}}})
+	}
+
+	send(1, "dance")
+	for _, client := range []*Client{sender, near, far} {
+		if got := emotes(client); len(got) != 0 {
+			t.Errorf("client %d received an unknown emote: %+v", client.ID, got)
+		}
+	}
+
+	send(2, "help")
+	for _, client := range []*Client{sender, near} {
+		got := emotes(client)
+		if len(got) != 1 || got[0].Emote != "help" || got[0].PlayerID != sender.ID {
+			t.Errorf("client %d received %+v, want the sender's help emote", client.ID, got)
+		}
+	}
+	if got := emotes(far); len(got) != 0 {
+		t.Errorf("out-of-range client received %+v", got)
+	}
+
+	// A second emote within the cooldown is dropped
+	send(3, "attack")
+	if got := emotes(near); len(got) != 0 {
+		t.Errorf("emote within the cooldown was broadcast: %+v", got)
+	}
+}
+
+func TestScatterDPSCountsEveryBullet(t *testing.T) {
+	w := newTestWorld(t, func(config *WorldConfig) {
+		config.SpreadHitFactor = 1
+	})
+	player := NewPlayer(1)
+	player.ShipConfig.SideUpgrade = NewScatterSideCannons(2)
+
+	stats := NewScatterCannon()
+	oneBullet := stats.BulletDamageMod * BulletDamage * player.Modifiers.BulletDamageMultiplier /
+		(stats.ReloadTime * player.Modifiers.ReloadSpeedMultiplier)
+	cannons := len(player.ShipConfig.SideUpgrade.Cannons)
+	want := roundDebugValue(oneBullet * float64(stats.BulletCount*cannons))
+
+	info := w.calculateDebugInfo(player)
+	if info.SideDPS != want {
+		t.Errorf("scatter side DPS = %v, want %v for %d cannons of %d bullets", info.SideDPS, want, cannons, stats.BulletCount)
+	}
+	if info.SideRange != roundDebugValue(cannonRange(player, stats)) || info.SideRange <= 0 {
+		t.Errorf("scatter side range = %v, want %v", info.SideRange, cannonRange(player, stats))
+	}
+}
+
+func TestRicochetBouncesOffTheLeftAndTopWalls(t *testing.T) {
+	w := newTestWorld(t, nil)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	bullet := &Bullet{
+		ID: 1, X: 2, Y: 1000, VelX: -300, CreatedAt: time.Now(), Radius: BulletSize,
+		Ricochet: true, Bounces: NewRicochetCannon().Bounces,
+	}
+	if bullet.Bounces != 2 {
+		t.Fatalf("ricochet cannon gives %d bounces, test expects 2", bullet.Bounces)
+	}
+	w.bullets[bullet.ID] = bullet
+
+	w.updateBullets()
+	if bullet.VelX != 300 || bullet.Bounces != 1 || bullet.X < 0 {
+		t.Fatalf("after the left wall: x %v, velX %v, bounces %d; want back inside heading right with 1 bounce",
+			bullet.X, bullet.VelX, bullet.Bounces)
+	}
+
+	bullet.Y, bullet.VelX, bullet.VelY = 2, 0, -300
+	w.updateBullets()
+	if bullet.VelY != 300 || bullet.Bounces != 0 || bullet.Y < 0 {
+		t.Fatalf("after the top wall: y %v, velY %v, bounces %d; want back inside heading down with no bounces",
+			bullet.Y, bullet.VelY, bullet.Bounces)
+	}
+
+	bullet.VelY = -300
+	w.updateBullets()
+	w.updateBullets()
+	if _, exists := w.bullets[bullet.ID]; exists {
+		t.Error("bullet out of bounces survived the wall")
+	}
+}
+
+func TestPassiveIncomeAccruesWhileAliveUpToTheCap(t *testing.T) {
+	w := newTestWorld(t, func(config *WorldConfig) {
+		config.PassiveIncomePerSecond = 3
+		config.PassiveIncomeCap = 10
+	})
+	player := addTestClient(t, w, 2000, 2000).Player
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	start := player.Coins
+
+	// sail runs the player for the given number of seconds
+	sail := func(seconds int) {
+		for range seconds * DefaultTickRate {
+			w.updatePlayer(player, &InputMsg{})
+		}
+	}
+
+	sail(2)
+	if earned := player.Coins - start; earned < 5 || earned > 6 {
+		t.Errorf("earned %d coins in 2 seconds at 3 per second", earned)
+	}
+	sail(10)
+	if earned := player.Coins - start; earned != 10 {
+		t.Errorf("earned %d coins in 12 seconds, want the cap of 10", earned)
+	}
+
+	off := newTestWorld(t, nil)
+	idle := addTestClient(t, off, 2000, 2000).Player
+	coins := idle.Coins
+	for range 2 * DefaultTickRate {
+		off.updatePlayer(idle, &InputMsg{})
+	}
+	if idle.Coins != coins {
+		t.Errorf("passive income paid %d coins with the default config", idle.Coins-coins)
+	}
+}
+
+func TestSpeedBoostPickupWearsOffAfterItsDuration(t *testing.T) {
+	w := newTestWorld(t, nil)
+	player := addTestClient(t, w, 1000, 1000).Player
+
+	w.mu.Lock()
+	player.updateModifiers()
+	base := player.Modifiers.MoveSpeedMultiplier
+	w.items[w.itemID] = &GameItem{ID: w.itemID, X: player.X, Y: player.Y, Type: ItemTypeSpeedBoost}
+	w.itemID++
+	w.mu.Unlock()
+	w.update()
+
+	w.mu.Lock()
+	if got := player.Modifiers.MoveSpeedMultiplier; math.Abs(got-base*SpeedBoostMultiplier) > 1e-9 || !player.hasBuff(BuffSpeedBoost) {
+		t.Errorf("after pickup: speed multiplier %v with buffs %v, want %v", got, player.ActiveBuffs, base*SpeedBoostMultiplier)
+	}
+	// Wind the clock on to when the boost runs out
+	player.ActiveBuffs = []ActiveBuff{{Type: BuffSpeedBoost, ExpiresAt: time.Now()}}
+	w.mu.Unlock()
+	w.update()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if got := player.Modifiers.MoveSpeedMultiplier; got != base || player.ActiveBuffs != nil {
+		t.Errorf("after expiry: speed multiplier %v with buffs %v, want %v and none", got, player.ActiveBuffs, base)
+	}
+}
+
+func TestRapidManualFireIsThrottled(t *testing.T) {
+	w := newTestWorld(t, nil)
+	client := addTestClient(t, w, 2000, 2000)
+	player := client.Player
+	stop := make(chan struct{})
+	defer close(stop)
+	go drainClient(client, stop)
+
+	// fire sends a manual fire input with every cannon reloaded, so only the
+	// throttle can hold it back, and reports whether anything fired
+	fire := func() bool {
+		w.mu.Lock()
+		for _, cannon := range player.ShipConfig.SideUpgrade.Cannons {
+			cannon.LastFireTime = time.Time{}
+		}
+		shots := player.Combat.ShotsFired
+		w.mu.Unlock()
+		w.HandleInput(client.ID, InputMsg{Type: "input", ManualFire: true})
+		w.update()
+		w.mu.RLock()
+		defer w.mu.RUnlock()
+		return player.Combat.ShotsFired > shots
+	}
+
+	if !fire() {
+		t.Fatal("first manual fire did not fire")
+	}
+	for i := range 3 {
+		if fire() {
+			t.Errorf("manual fire %d inside the cooldown fired", i+2)
+		}
+	}
+
+	w.mu.Lock()
+	client.LastManualFire = client.LastManualFire.Add(-ManualFireCooldown)
+	w.mu.Unlock()
+	if !fire() {
+		t.Error("manual fire after the cooldown did not fire")
+	}
+}
+
+func TestMagnetPullsNearbyItemsInUntilTheyAreCollected(t *testing.T) {
+	w := newTestWorld(t, nil)
+	magnetClient := addTestClient(t, w, 1000, 1000)
+	plainClient := addTestClient(t, w, 3000, 3000)
+	magnet, plain := magnetClient.Player, plainClient.Player
+	stop := make(chan struct{})
+	defer close(stop)
+	go drainClient(magnetClient, stop)
+	go drainClient(plainClient, stop)
+
+	w.mu.Lock()
+	ForceStatUpgrades(magnet, map[UpgradeType]int{StatUpgradeMagnet: 3})
+	ForceStatUpgrades(plain, map[UpgradeType]int{})
+	offset := magnet.Modifiers.MagnetRadius - 10
+	var items []*GameItem
+	for _, player := range []*Player{magnet, plain} {
+		player.Modifiers.MoveSpeedMultiplier = 0 // Hold still so only the item moves
+		item := &GameItem{ID: w.itemID, X: player.X, Y: player.Y + offset, Type: ItemTypeYellowCircle, XP: 10}
+		w.items[item.ID] = item
+		w.itemID++
+		items = append(items, item)
+	}
+	w.mu.Unlock()
+
+	w.update()
+	w.mu.Lock()
+	if items[0].Y >= magnet.Y+offset {
+		t.Errorf("item in magnet range stayed %v away", items[0].Y-magnet.Y)
+	}
+	if items[1].Y != plain.Y+offset {
+		t.Errorf("item near a ship without a magnet moved to %v away", items[1].Y-plain.Y)
+	}
+	w.mu.Unlock()
+
+	for range 2 * DefaultTickRate {
+		w.update()
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, exists := w.items[items[0].ID]; exists {
+		t.Errorf("magnetized item was never collected, still %v away", items[0].Y-magnet.Y)
+	}
+	if _, exists := w.items[items[1].ID]; !exists {
+		t.Error("item out of reach of a ship without a magnet was collected")
+	}
+}
+
+func TestFoodTargetScalesWithPlayersAndStaysUnderTheCap(t *testing.T) {
+	w := newTestWorld(t, nil)
+	first := addTestClient(t, w, 1000, 1000)
+
+	w.mu.Lock()
+	interval := w.refillFood()
+	count := len(w.items)
+	w.mu.Unlock()
+	if count != desiredItemCount(1) {
+		t.Fatalf("items with one player = %d, want %d", count, desiredItemCount(1))
+	}
+
+	var others []*Client
+	for range 20 {
+		others = append(others, addTestClient(t, w, 2000, 2000))
+	}
+	w.mu.Lock()
+	busyInterval := w.refillFood()
+	count = len(w.items)
+	w.mu.Unlock()
+	if count <= desiredItemCount(1) || count > MaxItems {
+		t.Fatalf("items with 21 players = %d, want more than %d and at most %d", count, desiredItemCount(1), MaxItems)
+	}
+	if busyInterval >= interval || busyInterval < MinFoodSpawnInterval {
+		t.Errorf("refill interval with 21 players = %v, want faster than %v and no faster than %v", busyInterval, interval, MinFoodSpawnInterval)
+	}
+
+	// Once the crowd leaves, unreserved food is trimmed back but kill loot stays
+	for _, client := range others {
+		w.RemoveClient(client.ID)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	loot := w.mechanics.SpawnLoot(1000, 1000, ItemTypeGoldStar, 100, 100, first.ID, time.Now())
+	w.refillFood()
+	if len(w.items) != desiredItemCount(1) {
+		t.Errorf("items after players left = %d, want %d", len(w.items), desiredItemCount(1))
+	}
+	if _, exists := w.items[loot.ID]; !exists {
+		t.Error("reserved kill loot was trimmed")
+	}
+}
+
+func TestNonFinitePhysicsNeverReachesTheSnapshot(t *testing.T) {
+	w := newTestWorld(t, nil)
+	zeroLength := addTestClient(t, w, 1000, 1000)
+	corrupted := addTestClient(t, w, 1010, 1000)
+	stop := make(chan struct{})
+	defer close(stop)
+	go drainClient(zeroLength, stop)
+	go drainClient(corrupted, stop)
+
+	w.mu.Lock()
+	zeroLength.Player.ShipConfig.ShipLength = 0
+	corrupted.Player.X = math.NaN()
+	corrupted.Player.VelY = math.Inf(1)
+	w.mu.Unlock()
+
+	for range 30 {
+		w.HandleInput(zeroLength.ID, InputMsg{Type: "input", Right: true})
+		w.update()
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, player := range []*Player{zeroLength.Player, corrupted.Player} {
+		for name, value := range map[string]float64{
+			"X": player.X, "Y": player.Y, "VelX": player.VelX, "VelY": player.VelY, "Angle": player.Angle,
+		} {
+			if !isFinite(value) {
+				t.Errorf("player %d %s = %v after 30 ticks", player.ID, name, value)
+			}
+		}
+	}
+	if zeroLength.Player.Angle == 0 {
+		t.Error("a ship with zero length never turned")
+	}
+}
+
+func TestHoldingDownReversesUnderTheReverseCap(t *testing.T) {
+	w := newTestWorld(t, nil)
+	client := addTestClient(t, w, 2000, 2000)
+	stop := make(chan struct{})
+	defer close(stop)
+	go drainClient(client, stop)
+
+	player := client.Player
+	w.mu.Lock()
+	player.Angle = 0
+	startAngle := player.Angle
+	w.mu.Unlock()
+
+	for range 10 {
+		w.mu.Lock()
+		x, y, angle := player.X, player.Y, player.Angle
+		w.mu.Unlock()
+
+		w.HandleInput(client.ID, InputMsg{Type: "input", Down: true, Right: true})
+		w.update()
+
+		w.mu.Lock()
+		dx, dy := player.X-x, player.Y-y
+		speed := math.Hypot(player.VelX, player.VelY)
+		maxReverse := BaseShipMaxSpeed * player.Modifiers.MoveSpeedMultiplier * ReverseSpeedFactor
+		w.mu.Unlock()
+
+		if forward := dx*math.Cos(angle) + dy*math.Sin(angle); forward >= 0 {
+			t.Fatalf("ship moved %v along its heading while reversing, want backward", forward)
+		}
+		if speed > maxReverse+1e-9 {
+			t.Fatalf("reverse speed = %v, want at most %v", speed, maxReverse)
+		}
+	}
+	if player.Angle == startAngle {
+		t.Error("ship could not turn while reversing")
+	}
+}
+
+func TestTakenNamesAreNumberedUntilTheyAreFreed(t *testing.T) {
+	w := newTestWorld(t, nil)
+	first := addTestClient(t, w, 1000, 1000)
+	second := addTestClient(t, w, 2000, 1000)
+	third := addTestClient(t, w, 3000, 1000)
+	name := func(client *Client) string {
+		w.mu.RLock()
+		defer w.mu.RUnlock()
+		return client.Player.Name
+	}
+
+	w.HandleInput(first.ID, InputMsg{Type: "profile", PlayerName: "Pirate"})
+	w.HandleInput(second.ID, InputMsg{Type: "profile", PlayerName: "pirate"})
+	if got := name(second); got != "pirate (2)" {
+		t.Errorf("second Pirate was named %q, want %q", got, "pirate (2)")
+	}
+
+	long := strings.Repeat("x", maxPlayerNameLength)
+	w.HandleInput(first.ID, InputMsg{Type: "profile", PlayerName: long})
+	w.HandleInput(third.ID, InputMsg{Type: "profile", PlayerName: long})
+	if got := name(third); got == long || !strings.HasSuffix(got, " (2)") || len([]rune(got)) > maxPlayerNameLength {
+		t.Errorf("duplicate of a full-length name became %q, want a numbered name within %d characters", got, maxPlayerNameLength)
+	}
+
+	// Once the original leaves, the name is free again
+	w.RemoveClient(first.ID)
+	w.HandleInput(third.ID, InputMsg{Type: "profile", PlayerName: long})
+	if got := name(third); got != long {
+		t.Errorf("name after its owner left = %q, want %q", got, long)
+	}
+}
+
+func TestStoppedShipsKeepTheMinimumTurnRate(t *testing.T) {
+	// turn returns how far a ship turns in one tick of holding Right
+	turn := func(moveSpeed float64, sideCannons int) (float64, float64) {
+		w := newTestWorld(t, func(config *WorldConfig) { config.MinTurnFactor = 0.3 })
+		client := addTestClient(t, w, 2000, 2000)
+		stop := make(chan struct{})
+		defer close(stop)
+		go drainClient(client, stop)
+		player := client.Player
+
+		w.mu.Lock()
+		if sideCannons > 1 {
+			player.ShipConfig.SideUpgrade = NewBasicSideCannons(sideCannons)
+			player.updateShipGeometry()
+		}
+		player.Modifiers.MoveSpeedMultiplier = moveSpeed
+		player.Modifiers.TurnSpeedMultiplier = 1
+		player.Angle = 0
+		player.VelX = BaseShipMaxSpeed * moveSpeed
+		w.mu.Unlock()
+
+		w.HandleInput(client.ID, InputMsg{Type: "input", Right: true})
+		w.update()
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return player.Angle, shipLengthFactor(player)
+	}
+
+	stopped, length := turn(0, 1)
+	full, _ := turn(1, 1)
+	if math.Abs(stopped/full-0.3) > 1e-9 {
+		t.Errorf("stopped ship turned %v against %v at full speed, want 30%%", stopped, full)
+	}
+
+	long, longLength := turn(1, 4)
+	if longLength >= length {
+		t.Fatalf("length factor with four cannons a side = %v, want below %v", longLength, length)
+	}
+	if want := full * longLength / length; math.Abs(long-want) > 1e-9 {
+		t.Errorf("long ship turned %v at full speed, want %v", long, want)
+	}
+}
+
+func TestUnavailableUpgradeRequestsAreRejectedAndCounted(t *testing.T) {
+	w := newTestWorld(t, nil)
+	client := addTestClient(t, w, 2000, 2000)
+	stop := make(chan struct{})
+	defer close(stop)
+	go drainClient(client, stop)
+
+	w.mu.Lock()
+	client.Player.AvailableUpgrades = 3
+	side := client.Player.ShipConfig.SideUpgrade.Name
+	valid := client.Player.ShipConfig.GetAvailableModules(UpgradeTypeSide)[0].Name
+	w.mu.Unlock()
+
+	// send submits one input and reports the rejection count after the tick
+	send := func(input InputMsg) int64 {
+		input.Type = "input"
+		w.HandleInput(client.ID, input)
+		w.update()
+		return w.GetRejectedUpgrades()
+	}
+
+	if got := send(InputMsg{SelectUpgrade: "side", UpgradeChoice: "Hundred Gun Broadside"}); got != 1 {
+		t.Errorf("rejected upgrades after an unknown module = %d, want 1", got)
+	}
+	if got := send(InputMsg{SelectUpgrade: "keel", UpgradeChoice: valid}); got != 2 {
+		t.Errorf("rejected upgrades after an unknown slot = %d, want 2", got)
+	}
+	if got := send(InputMsg{StatUpgradeType: "godMode"}); got != 3 {
+		t.Errorf("rejected upgrades after an unknown stat = %d, want 3", got)
+	}
+	w.mu.Lock()
+	if name := client.Player.ShipConfig.SideUpgrade.Name; name != side {
+		t.Errorf("rejected requests changed the side upgrade to %q", name)
+	}
+	w.mu.Unlock()
+
+	if got := send(InputMsg{SelectUpgrade: "side", UpgradeChoice: valid}); got != 3 {
+		t.Errorf("rejected upgrades after a valid choice = %d, want it to stay 3", got)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if name := client.Player.ShipConfig.SideUpgrade.Name; name != valid {
+		t.Errorf("side upgrade = %q after choosing %q", name, valid)
+	}
+}
@@ -0,0 +1,111 @@
+package game
+
+import (
+	"math"
+	"time"
+)
+
+// GameMode selects the rule set a world runs
+type GameMode string
+
+const (
+	ModeFreeForAll   GameMode = "ffa"
+	ModeBattleRoyale GameMode = "battleRoyale"
+	ModeDuel         GameMode = "duel"  // Private 1v1 arena, see DuelConfig
+	ModeTeams        GameMode = "teams" // Free-for-all map split into TeamNames; teammates can't hurt each other
+)
+
+// ZonePhase is one step of the battle-royale schedule: the zone holds for
+// Wait, then shrinks to Radius over Shrink
+type ZonePhase struct {
+	Wait         time.Duration
+	Shrink       time.Duration
+	Radius       float64 // Radius at the end of the phase
+	DamagePerSec float64 // Damage to ships outside the zone during this phase
+}
+
+// ZoneConfig describes the battle-royale safe zone schedule
+type ZoneConfig struct {
+	Center        Position
+	InitialRadius float64
+	Phases        []ZonePhase
+}
+
+// Zone is the current safe area, broadcast so clients can draw it
+type Zone struct {
+	X      float64 `msgpack:"x"`
+	Y      float64 `msgpack:"y"`
+	Radius float64 `msgpack:"radius"`
+}
+
+// DefaultZoneConfig starts with a zone covering the whole map and closes in
+// over four phases, each hurting more than the last
+func DefaultZoneConfig() ZoneConfig {
+	return ZoneConfig{
+		Center:        Position{X: WorldWidth / 2, Y: WorldHeight / 2},
+		InitialRadius: math.Hypot(WorldWidth, WorldHeight) / 2,
+		Phases: []ZonePhase{
+			{Wait: 60 * time.Second, Shrink: 60 * time.Second, Radius: 1800, DamagePerSec: 2},
+			{Wait: 45 * time.Second, Shrink: 45 * time.Second, Radius: 1000, DamagePerSec: 5},
+			{Wait: 30 * time.Second, Shrink: 30 * time.Second, Radius: 450, DamagePerSec: 10},
+			{Wait: 20 * time.Second, Shrink: 20 * time.Second, Radius: 150, DamagePerSec: 20},
+		},
+	}
+}
+
+// zoneAt returns the zone radius and out-of-zone damage rate at the given
+// time since the world started
+func (zc ZoneConfig) zoneAt(elapsed time.Duration) (radius, damagePerSec float64) {
+	radius = zc.InitialRadius
+	for _, phase := range zc.Phases {
+		damagePerSec = phase.DamagePerSec
+		if elapsed < phase.Wait {
+			return radius, damagePerSec
+		}
+		elapsed -= phase.Wait
+
+		if elapsed < phase.Shrink {
+			progress := float64(elapsed) / float64(phase.Shrink)
+			return radius + (phase.Radius-radius)*progress, damagePerSec
+		}
+		elapsed -= phase.Shrink
+		radius = phase.Radius
+	}
+	return radius, damagePerSec
+}
+
+// outsideZone reports whether a position is outside the battle-royale zone
+func (w *World) outsideZone(x, y float64) bool {
+	if w.zone == nil {
+		return false
+	}
+	dx := x - w.zone.X
+	dy := y - w.zone.Y
+	return dx*dx+dy*dy > w.zone.Radius*w.zone.Radius
+}
+
+// updateZone shrinks the battle-royale zone on schedule (or holds the duel
+// arena) and damages every ship caught outside it
+func (w *World) updateZone(now time.Time) {
+	if w.config.Mode != ModeBattleRoyale && w.config.Mode != ModeDuel {
+		return
+	}
+
+	radius, damagePerSec := w.config.Zone.zoneAt(now.Sub(w.startedAt))
+	w.zone = &Zone{
+		X:      w.config.Zone.Center.X,
+		Y:      w.config.Zone.Center.Y,
+		Radius: radius,
+	}
+
+	if damagePerSec <= 0 {
+		return
+	}
+
+	damage := damagePerSec * w.config.tickSeconds()
+	for _, player := range w.players {
+		if player.State == StateAlive && w.outsideZone(player.X, player.Y) {
+			w.mechanics.ApplyDamage(player, damage, nil, KillCauseZone, now)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package game
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestZoneShrinksOnScheduleAndHurtsShipsOutsideIt(t *testing.T) {
+	w := newTestWorld(t, func(config *WorldConfig) {
+		config.Mode = ModeBattleRoyale
+		config.Zone = ZoneConfig{
+			Center:        Position{X: 2000, Y: 2000},
+			InitialRadius: 1000,
+			Phases: []ZonePhase{
+				{Wait: 10 * time.Second, Shrink: 10 * time.Second, Radius: 200, DamagePerSec: 30},
+			},
+		}
+	})
+	inside := addTestClient(t, w, 2000, 2000)
+	outside := addTestClient(t, w, 2500, 2000)
+	stop := make(chan struct{})
+	defer close(stop)
+	go drainClient(inside, stop)
+	go drainClient(outside, stop)
+
+	// radiusAfter runs a tick as if the world had been running for elapsed
+	// and returns the zone radius it settled on
+	radiusAfter := func(elapsed time.Duration) float64 {
+		w.mu.Lock()
+		w.startedAt = time.Now().Add(-elapsed)
+		w.mu.Unlock()
+		w.update()
+		w.mu.RLock()
+		defer w.mu.RUnlock()
+		return w.zone.Radius
+	}
+
+	if radius := radiusAfter(5 * time.Second); radius != 1000 {
+		t.Errorf("zone radius during the wait = %v, want 1000", radius)
+	}
+	if outside.Player.Health != outside.Player.MaxHealth {
+		t.Errorf("ship inside the unshrunk zone took damage")
+	}
+	if radius := radiusAfter(15 * time.Second); math.Abs(radius-600) > 5 {
+		t.Errorf("zone radius halfway through the shrink = %v, want about 600", radius)
+	}
+	if radius := radiusAfter(25 * time.Second); radius != 200 {
+		t.Errorf("zone radius after the shrink = %v, want 200", radius)
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	tickDamage := 30 * w.config.tickSeconds()
+	if got := outside.Player.MaxHealth - outside.Player.Health; math.Abs(got-tickDamage) > 1e-9 {
+		t.Errorf("ship outside the zone lost %v health in a tick, want %v", got, tickDamage)
+	}
+	if inside.Player.Health != inside.Player.MaxHealth {
+		t.Errorf("ship inside the zone lost health")
+	}
+}
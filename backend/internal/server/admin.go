@@ -0,0 +1,84 @@
+package server
+
+import (
+	"encoding/json"
+	"goblons/internal/game"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// playerListing is one connected player as reported by /players
+type playerListing struct {
+	Room string `json:"room"`
+	game.PlayerInfo
+}
+
+// requireAdmin rejects requests that don't carry the admin token
+func (s *Server) requireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.isAdminToken(r.Header.Get("X-Admin-Token")) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// handleListPlayers returns every connected player across all rooms as JSON
+func (s *Server) handleListPlayers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	listings := []playerListing{}
+	for room, world := range s.hub.Rooms() {
+		for _, player := range world.ListPlayers() {
+			listings = append(listings, playerListing{Room: room, PlayerInfo: player})
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(listings); err != nil {
+		slog.Warn("Could not write player list", "err", err)
+	}
+}
+
+// handleKick disconnects the player with the given id; player IDs are per
+// room, so room defaults to the main room
+func (s *Server) handleKick(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.ParseUint(r.URL.Query().Get("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid player id", http.StatusBadRequest)
+		return
+	}
+	room := r.URL.Query().Get("room")
+	if room == "" {
+		room = DefaultRoom
+	}
+
+	world, exists := s.hub.Rooms()[room]
+	if !exists || !world.Kick(uint32(id)) {
+		http.Error(w, "No such player", http.StatusNotFound)
+		return
+	}
+
+	slog.Info("Player kicked by admin", "player", id, "room", room, "admin", r.RemoteAddr)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// remoteIP returns the host part of the request's remote address
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
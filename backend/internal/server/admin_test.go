@@ -0,0 +1,88 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"goblons/internal/game"
+)
+
+func TestAdminCanListAndKickPlayers(t *testing.T) {
+	const token = "secret"
+	s, url := newTestServer(t, func(config *game.WorldConfig) { config.AdminToken = token })
+	s.hub.Start()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		s.Shutdown(ctx)
+	})
+	base := "http" + strings.TrimSuffix(strings.TrimPrefix(url, "ws"), "/ws")
+
+	// request calls an admin endpoint, with the admin token when withToken is set
+	request := func(method, path string, withToken bool) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(method, base+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if withToken {
+			req.Header.Set("X-Admin-Token", token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+	list := func() []playerListing {
+		t.Helper()
+		var listings []playerListing
+		if err := json.NewDecoder(request(http.MethodGet, "/players", true).Body).Decode(&listings); err != nil {
+			t.Fatal(err)
+		}
+		return listings
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	var listings []playerListing
+	for deadline := time.Now().Add(5 * time.Second); len(listings) == 0 && time.Now().Before(deadline); {
+		listings = list()
+	}
+	if len(listings) != 1 || listings[0].Room != DefaultRoom || listings[0].IP == "" {
+		t.Fatalf("player listing = %+v, want the one connected player", listings)
+	}
+	id := strconv.FormatUint(uint64(listings[0].ID), 10)
+
+	if resp := request(http.MethodGet, "/players", false); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("listing without the token = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+	if resp := request(http.MethodPost, "/kick?id="+id, false); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("kick without the token = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+
+	if resp := request(http.MethodPost, "/kick?id="+id, true); resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("kick = %d, want %d", resp.StatusCode, http.StatusNoContent)
+	}
+	want := game.LookupClientError(game.ErrorKicked).CloseCode
+	if closeErr := readUntilClosed(t, conn); closeErr.Code != want {
+		t.Errorf("kicked client close code = %d, want %d", closeErr.Code, want)
+	}
+	if listings := list(); len(listings) != 0 {
+		t.Errorf("player listing after the kick = %+v, want none", listings)
+	}
+	if resp := request(http.MethodPost, "/kick?id="+id, true); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("kicking a departed player = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
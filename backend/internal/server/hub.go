@@ -0,0 +1,272 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"goblons/internal/game"
+	"log/slog"
+	"maps"
+	"sync"
+	"time"
+	"unicode"
+)
+
+const (
+	DefaultRoom       = "main" // Room used when a client doesn't ask for one
+	MaxRooms          = 16     // Upper bound on concurrently running rooms
+	maxRoomNameLength = 16
+	duelTicketTimeout = time.Minute // How long a duelist has to reconnect into the arena
+)
+
+// duelTicket is a single-use pass into one duel room
+type duelTicket struct {
+	room    string
+	expires time.Time
+}
+
+// Hub owns every running room (world) and routes clients between them
+type Hub struct {
+	mu      sync.Mutex
+	config  game.WorldConfig
+	rooms   map[string]*game.World
+	started bool
+	nextID  int
+
+	// Stats from rooms that have been torn down, so aggregated totals never go backwards
+	retiredSnapshotCount    int64
+	retiredSnapshotSize     int64
+	retiredRejectedUpgrades int64
+
+	// Tickets into duel rooms, by ticket. Issued by worlds while they hold
+	// their own lock, so this has its own mutex that never waits on h.mu.
+	ticketsMu   sync.Mutex
+	duelTickets map[string]duelTicket
+}
+
+// NewHub creates a hub with the default room ready to start
+func NewHub(config game.WorldConfig) *Hub {
+	hub := &Hub{
+		config:      config,
+		rooms:       make(map[string]*game.World),
+		nextID:      1,
+		duelTickets: make(map[string]duelTicket),
+	}
+	hub.createRoom(DefaultRoom)
+	return hub
+}
+
+// Start runs the game loop of every room; rooms created later start immediately
+func (h *Hub) Start() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.started = true
+	for _, world := range h.rooms {
+		go world.Start()
+	}
+}
+
+// Join adds the client to the named room, or to any room with space when name is empty.
+// Duel rooms only admit clients holding a ticket issued when the duel was accepted.
+// Returns the world the client joined, or an error code describing the rejection.
+func (h *Hub) Join(roomName, ticket string, client *game.Client) (*game.World, string, game.ErrorCode) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if roomName != "" {
+		if !validRoomName(roomName) {
+			return nil, "", game.ErrorInvalidRoom
+		}
+		if game.IsDuelRoom(roomName) && !h.redeemDuelTicket(roomName, ticket, time.Now()) {
+			slog.Info("Rejected client without a duel ticket", "room", roomName, "ip", client.IP)
+			return nil, "", game.ErrorInvalidRoom
+		}
+
+		world, exists := h.rooms[roomName]
+		if !exists {
+			if len(h.rooms) >= MaxRooms {
+				return nil, "", game.ErrorServerFull
+			}
+			world = h.createRoom(roomName)
+		}
+
+		if !world.AddClient(client) {
+			return nil, "", game.ErrorServerFull
+		}
+		return world, roomName, ""
+	}
+
+	// Auto-assign: prefer the default room, then any other room with capacity
+	if world := h.rooms[DefaultRoom]; world.AddClient(client) {
+		return world, DefaultRoom, ""
+	}
+	for name, world := range h.rooms {
+		if name != DefaultRoom && !game.IsDuelRoom(name) && world.AddClient(client) {
+			return world, name, ""
+		}
+	}
+
+	if len(h.rooms) >= MaxRooms {
+		return nil, "", game.ErrorServerFull
+	}
+
+	name := h.generateRoomName()
+	world := h.createRoom(name)
+	if !world.AddClient(client) {
+		return nil, "", game.ErrorServerFull
+	}
+	return world, name, ""
+}
+
+// Leave tears down a room once its last client has gone (the default room always stays)
+func (h *Hub) Leave(roomName string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	world, exists := h.rooms[roomName]
+	if !exists || roomName == DefaultRoom || world.ClientCount() > 0 {
+		return
+	}
+
+	count, size := world.GetSnapshotStats()
+	h.retiredSnapshotCount += count
+	h.retiredSnapshotSize += size
+	h.retiredRejectedUpgrades += world.GetRejectedUpgrades()
+
+	world.Stop()
+	delete(h.rooms, roomName)
+	slog.Info("Room closed", "room", roomName, "rooms", len(h.rooms))
+}
+
+// Worlds returns every running room
+func (h *Hub) Worlds() []*game.World {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	worlds := make([]*game.World, 0, len(h.rooms))
+	for _, world := range h.rooms {
+		worlds = append(worlds, world)
+	}
+	return worlds
+}
+
+// Rooms returns every running room by name
+func (h *Hub) Rooms() map[string]*game.World {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return maps.Clone(h.rooms)
+}
+
+// GetSnapshotStats aggregates snapshot statistics across all rooms, past and present
+func (h *Hub) GetSnapshotStats() (count int64, totalSize int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	count, totalSize = h.retiredSnapshotCount, h.retiredSnapshotSize
+	for _, world := range h.rooms {
+		worldCount, worldSize := world.GetSnapshotStats()
+		count += worldCount
+		totalSize += worldSize
+	}
+	return count, totalSize
+}
+
+// GetRejectedUpgrades totals the rejected upgrade requests across all rooms, past and present
+func (h *Hub) GetRejectedUpgrades() int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	total := h.retiredRejectedUpgrades
+	for _, world := range h.rooms {
+		total += world.GetRejectedUpgrades()
+	}
+	return total
+}
+
+// createRoom creates a world for the room and starts it if the hub is running; caller holds h.mu
+func (h *Hub) createRoom(name string) *game.World {
+	config := h.config
+	if game.IsDuelRoom(name) {
+		config = game.DuelConfig(config)
+	}
+	if config.RecordPath != "" && name != DefaultRoom {
+		// Each room records to its own file
+		config.RecordPath = fmt.Sprintf("%s.%s", config.RecordPath, name)
+	}
+
+	world := game.NewWorldWithConfig(config)
+	world.SetDuelTicketIssuer(h.issueDuelTickets)
+	h.rooms[name] = world
+	if h.started {
+		go world.Start()
+	}
+
+	slog.Info("Room opened", "room", name, "rooms", len(h.rooms))
+	return world
+}
+
+// issueDuelTickets reserves a duel room with one ticket per duelist (see game.DuelTicketIssuer)
+func (h *Hub) issueDuelTickets(room string, players int) []string {
+	h.ticketsMu.Lock()
+	defer h.ticketsMu.Unlock()
+
+	now := time.Now()
+	for ticket, issued := range h.duelTickets {
+		if now.After(issued.expires) {
+			delete(h.duelTickets, ticket)
+		}
+	}
+
+	tickets := make([]string, players)
+	for i := range tickets {
+		tickets[i] = newDuelTicket()
+		h.duelTickets[tickets[i]] = duelTicket{room: room, expires: now.Add(duelTicketTimeout)}
+	}
+	return tickets
+}
+
+// redeemDuelTicket uses up a ticket, reporting whether it was valid for the room
+func (h *Hub) redeemDuelTicket(room, ticket string, now time.Time) bool {
+	h.ticketsMu.Lock()
+	defer h.ticketsMu.Unlock()
+
+	issued, exists := h.duelTickets[ticket]
+	if !exists || issued.room != room {
+		return false
+	}
+	delete(h.duelTickets, ticket)
+	return !now.After(issued.expires)
+}
+
+// newDuelTicket returns a random unguessable ticket
+func newDuelTicket() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// generateRoomName picks an unused name for an auto-created room; caller holds h.mu
+func (h *Hub) generateRoomName() string {
+	for {
+		name := fmt.Sprintf("room-%d", h.nextID)
+		h.nextID++
+		if _, exists := h.rooms[name]; !exists {
+			return name
+		}
+	}
+}
+
+// validRoomName accepts short names made of letters, digits and dashes
+func validRoomName(name string) bool {
+	if len(name) == 0 || len(name) > maxRoomNameLength {
+		return false
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' {
+			return false
+		}
+	}
+	return true
+}
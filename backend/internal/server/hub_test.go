@@ -0,0 +1,49 @@
+package server
+
+import (
+	"testing"
+
+	"goblons/internal/game"
+)
+
+func newTestHub() *Hub {
+	config := game.DefaultWorldConfig()
+	config.Bots.Count = 0
+	config.Bots.Dummies = 0
+	return NewHub(config)
+}
+
+func TestDuelRoomRequiresTicket(t *testing.T) {
+	hub := newTestHub()
+	const room = "duel-0000abcd"
+
+	if world, _, code := hub.Join(room, "", game.NewClient(0, nil)); world != nil || code != game.ErrorInvalidRoom {
+		t.Fatalf("join without ticket: world %v, code %q; want rejected with %q", world, code, game.ErrorInvalidRoom)
+	}
+
+	tickets := hub.issueDuelTickets(room, game.DuelPlayers)
+	if _, _, code := hub.Join(room, tickets[0], game.NewClient(0, nil)); code != "" {
+		t.Fatalf("join with ticket rejected: %q", code)
+	}
+	if world, _, _ := hub.Join(room, tickets[0], game.NewClient(0, nil)); world != nil {
+		t.Fatal("ticket was accepted twice")
+	}
+	if world, _, _ := hub.Join("duel-ffffffff", tickets[1], game.NewClient(0, nil)); world != nil {
+		t.Fatal("ticket was accepted for another room")
+	}
+}
+
+func TestDuelAcceptsExactlyTwoPlayers(t *testing.T) {
+	hub := newTestHub()
+	const room = "duel-00001234"
+	tickets := hub.issueDuelTickets(room, game.DuelPlayers+1)
+
+	for i := range game.DuelPlayers {
+		if _, _, code := hub.Join(room, tickets[i], game.NewClient(0, nil)); code != "" {
+			t.Fatalf("duelist %d rejected: %q", i+1, code)
+		}
+	}
+	if world, _, code := hub.Join(room, tickets[game.DuelPlayers], game.NewClient(0, nil)); world != nil || code != game.ErrorServerFull {
+		t.Fatalf("third player: world %v, code %q; want rejected with %q", world, code, game.ErrorServerFull)
+	}
+}
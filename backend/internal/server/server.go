@@ -3,9 +3,14 @@ package server
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
+	"crypto/subtle"
+	_ "embed"
 	"goblons/internal/game"
-	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -14,6 +19,11 @@ import (
 	"github.com/gorilla/websocket"
 )
 
+// fallbackPage is served in place of the frontend when the static directory is missing
+//
+//go:embed fallback.html
+var fallbackPage []byte
+
 var upgrader = websocket.Upgrader{
 	CheckOrigin: func(r *http.Request) bool {
 		return true // Allow connections from any origin
@@ -24,17 +34,33 @@ var upgrader = websocket.Upgrader{
 
 // Server handles HTTP and WebSocket connections
 type Server struct {
-	world         *game.World
-	bytesSent     int64 // Total bytes sent
-	bytesReceived int64 // Total bytes received
-	messagesSent  int64 // Total messages sent
-	messagesRecv  int64 // Total messages received
+	hub           *Hub
+	httpServer    *http.Server
+	bytesSent     int64          // Total bytes sent
+	bytesReceived int64          // Total bytes received
+	messagesSent  int64          // Total messages sent
+	messagesRecv  int64          // Total messages received
+	shuttingDown  atomic.Bool    // Set once Shutdown begins; rejects new upgrades
+	writers       sync.WaitGroup // Tracks client write goroutines so shutdown can drain them
+	joinMu        sync.Mutex     // Orders joins against Shutdown setting shuttingDown
+	adminToken    string         // Token that marks a connection as admin (empty = admin disabled)
+	staticDir     string         // Directory the frontend is served from
+
+	accounts        game.AccountStore     // Where cosmetic unlocks are kept (nil = accounts disabled)
+	sessions        *game.SessionLoadouts // Loadout presets by session token (nil = not kept)
+	rankedCosmetics bool                  // Only allow colors the player's account has unlocked
 }
 
 // NewServer creates a new server instance
-func NewServer() *Server {
+func NewServer(config game.WorldConfig) *Server {
 	server := &Server{
-		world: game.NewWorld(),
+		hub:        NewHub(config),
+		adminToken: config.AdminToken,
+		staticDir:  config.StaticDir,
+
+		accounts:        config.Accounts,
+		sessions:        config.Sessions,
+		rankedCosmetics: config.RankedCosmetics,
 	}
 
 	// Start network monitoring
@@ -45,15 +71,89 @@ func NewServer() *Server {
 
 // Start starts the server on the specified address
 func (s *Server) Start(addr string) error {
-	// Start the game world
-	go s.world.Start()
+	// Start the game worlds
+	s.hub.Start()
 
-	// Set up HTTP routes
-	http.Handle("/", http.FileServer(http.Dir("./static")))
-	http.HandleFunc("/ws", s.handleWebSocket)
+	s.httpServer = &http.Server{
+		Addr:    addr,
+		Handler: s.Handler(),
+	}
 
-	log.Printf("Server starting on %s", addr)
-	return http.ListenAndServe(addr, nil)
+	slog.Info("Server starting", "addr", addr)
+	return s.httpServer.ListenAndServe()
+}
+
+// Handler returns the HTTP routes served by this server
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/", s.staticHandler())
+	mux.HandleFunc("/ws", s.handleWebSocket)
+	mux.HandleFunc("/players", s.requireAdmin(s.handleListPlayers))
+	mux.HandleFunc("/kick", s.requireAdmin(s.handleKick))
+	return mux
+}
+
+// staticHandler serves the frontend from staticDir, or a placeholder page
+// explaining what's wrong when the directory is missing so deployers aren't
+// left guessing at bare 404s
+func (s *Server) staticHandler() http.Handler {
+	info, err := os.Stat(s.staticDir)
+	if err == nil && info.IsDir() {
+		return http.FileServer(http.Dir(s.staticDir))
+	}
+
+	slog.Warn("Static directory not found, serving a placeholder page; build the frontend or pass -static",
+		"dir", s.staticDir, "err", err)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" && r.URL.Path != "/index.html" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write(fallbackPage)
+	})
+}
+
+// Shutdown stops accepting connections, closes every client with a going-away
+// frame, stops the game loop and waits for all of it to finish or ctx to expire
+func (s *Server) Shutdown(ctx context.Context) error {
+	// Once this returns, every client that got in is in a world below and
+	// counted in s.writers, and every later one is turned away
+	s.joinMu.Lock()
+	s.shuttingDown.Store(true)
+	s.joinMu.Unlock()
+	slog.Info("Server shutting down")
+
+	var err error
+	if s.httpServer != nil {
+		err = s.httpServer.Shutdown(ctx)
+	}
+
+	// Hijacked websocket connections are not tracked by http.Server, close them ourselves
+	worlds := s.hub.Worlds()
+	for _, world := range worlds {
+		world.DisconnectAll(game.ErrorServerShutdown)
+		world.Stop()
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		s.writers.Wait()
+		for _, world := range worlds {
+			<-world.Done()
+		}
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		slog.Info("Server shut down cleanly")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return err
 }
 
 // monitorNetworkUsage logs network statistics every 10 seconds
@@ -71,7 +171,7 @@ func (s *Server) monitorNetworkUsage() {
 		currentRecv := atomic.LoadInt64(&s.bytesReceived)
 		currentMsgSent := atomic.LoadInt64(&s.messagesSent)
 		currentMsgRecv := atomic.LoadInt64(&s.messagesRecv)
-		currentSnapshotCount, currentTotalSnapshotSize := s.world.GetSnapshotStats()
+		currentSnapshotCount, currentTotalSnapshotSize := s.hub.GetSnapshotStats()
 
 		sentRate := float64(currentSent-lastSent) / 10.0 / 1000000.0
 		recvRate := float64(currentRecv-lastRecv) / 10.0 / 1000000.0
@@ -85,8 +185,10 @@ func (s *Server) monitorNetworkUsage() {
 			avgSnapshotSize = float64(sizeInPeriod) / float64(snapshotsInPeriod)
 		}
 
-		log.Printf("Network Stats - Sent: %.3f MB/s, Recv: %.3f MB/s, Msg Sent: %.1f/s, Msg Recv: %.1f/s, Avg Snapshot: %.1f KB (%d total)",
-			sentRate, recvRate, msgSentRate, msgRecvRate, avgSnapshotSize/1024.0, currentSnapshotCount)
+		slog.Info("Network stats",
+			"sentMBps", sentRate, "recvMBps", recvRate, "msgSentPerSec", msgSentRate, "msgRecvPerSec", msgRecvRate,
+			"avgSnapshotKB", avgSnapshotSize/1024.0, "snapshots", currentSnapshotCount,
+			"rejectedUpgrades", s.hub.GetRejectedUpgrades())
 
 		lastSent = currentSent
 		lastRecv = currentRecv
@@ -99,9 +201,22 @@ func (s *Server) monitorNetworkUsage() {
 
 // handleWebSocket handles WebSocket connections
 func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	if s.shuttingDown.Load() {
+		http.Error(w, "Server is shutting down", http.StatusServiceUnavailable)
+		return
+	}
+
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
-		log.Printf("WebSocket upgrade error: %v", err)
+		slog.Warn("WebSocket upgrade failed", "err", err)
+		return
+	}
+
+	// Turn away clients built for a different message format before they join
+	query := r.URL.Query()
+	if !game.ProtocolCompatible(query.Get("protocol")) {
+		slog.Info("Rejected client with incompatible protocol", "protocol", query.Get("protocol"), "server", game.ProtocolVersion)
+		rejectConnection(conn, game.ErrorProtocolMismatch)
 		return
 	}
 
@@ -109,33 +224,73 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	client := game.NewClient(0, conn) // ID will be assigned by world
 
 	// Apply any requested cosmetics before joining the world
-	query := r.URL.Query()
+	client.Account = game.LoadAccount(s.accounts, query.Get("account"))
+	if client.Account != nil {
+		client.Loadout = client.Account.Loadout
+	}
+	// This tab's latest preset wins over the account's
+	client.Session = query.Get("session")
+	if preset := s.sessions.Load(client.Session); preset != nil {
+		client.Loadout = preset
+	}
 	if requestedName := game.SanitizePlayerName(query.Get("name")); requestedName != "" {
 		client.Player.Name = requestedName
 	}
 	if requestedColor := game.SanitizePlayerColor(query.Get("color")); requestedColor != "" {
-		client.Player.Color = requestedColor
+		client.ChooseColor(requestedColor, s.rankedCosmetics)
 	}
-
-	// Try to add client (may fail if server is full)
-	if !s.world.AddClient(client) {
-		// Server is full, send error and close connection
-		conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "Server is full"))
-		conn.Close()
+	if requestedFlag := query.Get("flag"); client.Account.HasFlag(requestedFlag) {
+		client.Player.Flag = requestedFlag
+	}
+	client.OffscreenIndicators = query.Get("indicators") == "1"
+	client.IP = remoteIP(r)
+	client.IsAdmin = s.isAdminToken(r.Header.Get("X-Admin-Token"))
+
+	// Join the requested room, or any room with space (may fail if the server is full)
+	world, room, errCode := s.joinRoom(query.Get("room"), query.Get("ticket"), client)
+	if world == nil {
+		rejectConnection(conn, errCode)
 		return
 	}
 
 	// Start client goroutines
-	go s.handleClientReads(client)
+	go s.handleClientReads(client, world, room)
 	go s.handleClientWrites(client)
 }
 
+// joinRoom adds the client to a room and registers its write goroutine, or
+// turns it away once shutdown has begun. Holding joinMu keeps writers.Add from
+// racing the Wait in Shutdown.
+func (s *Server) joinRoom(roomName, ticket string, client *game.Client) (*game.World, string, game.ErrorCode) {
+	s.joinMu.Lock()
+	defer s.joinMu.Unlock()
+
+	if s.shuttingDown.Load() {
+		return nil, "", game.ErrorServerShutdown
+	}
+	world, room, errCode := s.hub.Join(roomName, ticket, client)
+	if world != nil {
+		s.writers.Add(1)
+	}
+	return world, room, errCode
+}
+
+// isAdminToken reports whether token matches the configured admin token
+func (s *Server) isAdminToken(token string) bool {
+	if s.adminToken == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) == 1
+}
+
 // handleClientReads reads messages from the client
-func (s *Server) handleClientReads(client *game.Client) {
+func (s *Server) handleClientReads(client *game.Client, world *game.World, room string) {
 	defer func() {
 		client.Conn.Close()
-		s.world.RemoveClient(client.ID)
+		world.RemoveClient(client.ID)
+		s.hub.Leave(room)
 	}()
+	defer client.RecoverPanic("read")
 
 	// Set read deadline and pong handler for keepalive
 	client.Conn.SetReadDeadline(time.Now().Add(60 * time.Second))
@@ -148,7 +303,7 @@ func (s *Server) handleClientReads(client *game.Client) {
 		_, messageBytes, err := client.Conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-				log.Printf("WebSocket error: %v", err)
+				slog.Warn("WebSocket error", "client", client.ID, "err", err)
 			}
 			break
 		}
@@ -159,12 +314,12 @@ func (s *Server) handleClientReads(client *game.Client) {
 
 		var input game.InputMsg
 		if err := msgpack.Unmarshal(messageBytes, &input); err != nil {
-			log.Printf("Error unmarshaling input: %v", err)
+			slog.Debug("Error unmarshaling input", "client", client.ID, "err", err)
 			continue
 		}
 
 		// Process the input
-		s.world.HandleInput(client.ID, input)
+		world.HandleInput(client.ID, input)
 	}
 }
 
@@ -174,14 +329,20 @@ func (s *Server) handleClientWrites(client *game.Client) {
 	defer func() {
 		ticker.Stop()
 		client.Conn.Close()
+		s.writers.Done()
 	}()
+	defer client.RecoverPanic("write")
 
 	for {
 		select {
 		case message, ok := <-client.Send:
 			client.Conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
 			if !ok {
-				client.Conn.WriteMessage(websocket.CloseMessage, []byte{})
+				closeFrame := []byte{}
+				if client.CloseReason != nil {
+					closeFrame = client.CloseReason.CloseFrame()
+				}
+				client.Conn.WriteMessage(websocket.CloseMessage, closeFrame)
 				return
 			}
 
@@ -191,12 +352,12 @@ func (s *Server) handleClientWrites(client *game.Client) {
 
 			compressedMsg, err := compressMessage(message)
 			if err != nil {
-				log.Printf("Compression error: %v", err)
+				slog.Error("Compression error", "err", err)
 				compressedMsg = message // fallback to uncompressed
 			}
 
 			if err := client.Conn.WriteMessage(websocket.BinaryMessage, compressedMsg); err != nil {
-				log.Printf("Write error: %v", err)
+				slog.Debug("Write error", "client", client.ID, "err", err)
 				return
 			}
 
@@ -209,6 +370,24 @@ func (s *Server) handleClientWrites(client *game.Client) {
 	}
 }
 
+// rejectConnection sends a structured error followed by the matching close frame
+func rejectConnection(conn *websocket.Conn, code game.ErrorCode) {
+	defer conn.Close()
+
+	clientError := game.LookupClientError(code)
+	conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+
+	if data, err := msgpack.Marshal(clientError.ToMsg()); err == nil {
+		if compressedMsg, err := compressMessage(data); err == nil {
+			conn.WriteMessage(websocket.BinaryMessage, compressedMsg)
+		}
+	} else {
+		slog.Error("Error marshaling error message", "err", err)
+	}
+
+	conn.WriteMessage(websocket.CloseMessage, clientError.CloseFrame())
+}
+
 // compressMessage compresses a byte slice using gzip if large enough
 func compressMessage(data []byte) ([]byte, error) {
 	if len(data) < 512 { // Don't compress small messages
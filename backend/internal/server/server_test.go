@@ -0,0 +1,165 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/vmihailenco/msgpack/v5"
+
+	"goblons/internal/game"
+)
+
+// newTestServer serves a server with no bots over httptest and returns its
+// websocket URL
+func newTestServer(t *testing.T, configure func(*game.WorldConfig)) (*Server, string) {
+	t.Helper()
+	config := game.DefaultWorldConfig()
+	config.Bots.Count = 0
+	config.Bots.Dummies = 0
+	if configure != nil {
+		configure(&config)
+	}
+	s := NewServer(config)
+	ts := httptest.NewServer(s.Handler())
+	t.Cleanup(ts.Close)
+	return s, "ws" + strings.TrimPrefix(ts.URL, "http") + "/ws"
+}
+
+// readUntilClosed reads from conn until it fails and returns the close error
+func readUntilClosed(t *testing.T, conn *websocket.Conn) *websocket.CloseError {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			var closeErr *websocket.CloseError
+			if !errors.As(err, &closeErr) {
+				t.Fatalf("connection ended without a close frame: %v", err)
+			}
+			return closeErr
+		}
+	}
+}
+
+func TestShutdownClosesConnectionsCleanly(t *testing.T) {
+	s, url := newTestServer(t, nil)
+	s.hub.Start()
+
+	var conns []*websocket.Conn
+	for range 3 {
+		conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		conns = append(conns, conn)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	for i, conn := range conns {
+		if closeErr := readUntilClosed(t, conn); closeErr.Code != websocket.CloseGoingAway {
+			t.Errorf("client %d close code = %d, want %d", i, closeErr.Code, websocket.CloseGoingAway)
+		}
+	}
+
+	if conn, _, err := websocket.DefaultDialer.Dial(url, nil); err == nil {
+		conn.Close()
+		t.Error("connection accepted after shutdown")
+	}
+}
+
+func TestOnlyClientsOnTheSameProtocolAreWelcomed(t *testing.T) {
+	s, url := newTestServer(t, nil)
+	s.hub.Start()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		s.Shutdown(ctx)
+	})
+
+	conn, _, err := websocket.DefaultDialer.Dial(url+"?protocol=99", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	want := game.LookupClientError(game.ErrorProtocolMismatch).CloseCode
+	if closeErr := readUntilClosed(t, conn); closeErr.Code != want {
+		t.Errorf("mismatched client close code = %d, want %d", closeErr.Code, want)
+	}
+
+	conn, _, err = websocket.DefaultDialer.Dial(url+"?protocol="+strconv.Itoa(game.ProtocolVersion), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("matching client was not welcomed: %v", err)
+		}
+		// Small messages are sent uncompressed behind a 0x00 marker
+		var welcome game.WelcomeMsg
+		if len(data) > 0 && data[0] == 0x00 && msgpack.Unmarshal(data[1:], &welcome) == nil && welcome.Type == game.MsgTypeWelcome {
+			if welcome.Protocol != game.ProtocolVersion {
+				t.Errorf("welcome protocol = %d, want %d", welcome.Protocol, game.ProtocolVersion)
+			}
+			return
+		}
+	}
+}
+
+func TestMissingStaticDirWarnsButTheGameStillConnects(t *testing.T) {
+	var logs bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	missing := filepath.Join(t.TempDir(), "static")
+	s, url := newTestServer(t, func(config *game.WorldConfig) { config.StaticDir = missing })
+	s.hub.Start()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		s.Shutdown(ctx)
+	})
+	if !strings.Contains(logs.String(), "Static directory not found") || !strings.Contains(logs.String(), missing) {
+		t.Errorf("no warning naming the missing directory was logged:\n%s", logs.String())
+	}
+
+	resp, err := http.Get("http" + strings.TrimSuffix(strings.TrimPrefix(url, "ws"), "/ws") + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || !bytes.Equal(page, fallbackPage) {
+		t.Errorf("index served %d with %d bytes, want the %d-byte placeholder page with %d",
+			resp.StatusCode, len(page), len(fallbackPage), http.StatusServiceUnavailable)
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("websocket dial with no static directory: %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, _, err := conn.ReadMessage(); err != nil {
+		t.Errorf("connected client received nothing: %v", err)
+	}
+}
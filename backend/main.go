@@ -1,16 +1,151 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"goblons/internal/game"
 	"goblons/internal/server"
 )
 
 func main() {
-	srv := server.NewServer()
+	config := game.DefaultWorldConfig()
+	flag.Int64Var(&config.Seed, "seed", config.Seed, "random seed for reproducible runs (0 = time-based)")
+	flag.StringVar(&config.AdminToken, "admin-token", config.AdminToken, "token that unlocks admin commands (empty = disabled)")
+	flag.IntVar(&config.TickRate, "tickrate", config.TickRate, "simulation steps per second (speeds are per second, so this only changes precision)")
+	flag.StringVar(&config.StaticDir, "static", config.StaticDir, "directory the frontend is served from")
+	flag.IntVar(&config.MaxCoins, "max-coins", config.MaxCoins, "most coins a player can hold")
+	flag.IntVar(&config.MaxScore, "max-score", config.MaxScore, "highest score a player can reach")
+	flag.StringVar(&config.RecordPath, "record", config.RecordPath, "record every tick's snapshot to this file (off when empty)")
+	flag.Float64Var(&config.PassiveIncomePerSecond, "passive-income", config.PassiveIncomePerSecond, "coins per second paid to living players (0 = off)")
+	flag.IntVar(&config.PassiveIncomeCap, "passive-income-cap", config.PassiveIncomeCap, "maximum passive coins per life")
+	flag.Float64Var(&config.RespawnXPRetention, "respawn-xp-retention", config.RespawnXPRetention, "fraction of XP and score kept on respawn")
+	flag.Float64Var(&config.RespawnCoinRetention, "respawn-coin-retention", config.RespawnCoinRetention, "fraction of coins kept on respawn")
+	flag.DurationVar(&config.SpawnProtection, "spawn-protection", config.SpawnProtection, "invulnerability window after respawning (0 = off)")
+	flag.Float64Var(&config.PredictionCorrectionThreshold, "prediction-threshold", config.PredictionCorrectionThreshold, "distance a client prediction may drift before being corrected (0 = never)")
+	flag.DurationVar(&config.LootOwnershipWindow, "loot-ownership", config.LootOwnershipWindow, "how long dropped loot is reserved for the killer (0 = free-for-all)")
+	flag.IntVar(&config.MaxLevel, "max-level", config.MaxLevel, "level cap; experience past it earns prestige")
+	flag.Float64Var(&config.BorderDamagePerSec, "border-damage", config.BorderDamagePerSec, "damage per second at the world edge (0 = hard wall only)")
+	flag.Float64Var(&config.BorderPushForce, "border-push", config.BorderPushForce, "push-back velocity per second at the world edge (0 = none)")
+	flag.IntVar(&config.MaxBulletsPerPlayer, "max-bullets-per-player", config.MaxBulletsPerPlayer, "live bullets one ship may have (0 = no cap)")
+	flag.IntVar(&config.MaxBullets, "max-bullets", config.MaxBullets, "live bullets across the world (0 = no cap)")
+	flag.BoolVar(&config.FriendlyCollisionDamage, "friendly-collision-damage", config.FriendlyCollisionDamage, "teammates deal collision and ram damage to each other")
+	flag.Float64Var(&config.XPMultiplier, "xp-multiplier", config.XPMultiplier, "XP reward multiplier for bonus events")
+	flag.Float64Var(&config.CoinMultiplier, "coin-multiplier", config.CoinMultiplier, "coin reward multiplier for bonus events")
+	flag.Float64Var(&config.DashImpulse, "dash-impulse", config.DashImpulse, "forward velocity per second added by a dash (0 = dash disabled)")
+	flag.DurationVar(&config.DashCooldown, "dash-cooldown", config.DashCooldown, "minimum time between dashes")
+	flag.BoolVar(&config.UniqueNames, "unique-names", config.UniqueNames, "number duplicate player names, e.g. \"Pirate (2)\"")
+	flag.Float64Var(&config.SpreadHitFactor, "spread-hit-factor", config.SpreadHitFactor, "expected hit chance of each extra spread bullet in displayed DPS (1 = count all)")
+	flag.Float64Var(&config.MinTurnFactor, "min-turn-factor", config.MinTurnFactor, "fraction of turn speed kept at a standstill (0-1)")
+	flag.Float64Var(&config.TurnSpeedExponent, "turn-speed-exponent", config.TurnSpeedExponent, "shape of the speed-to-turn-rate curve (1 = linear)")
+	flag.Float64Var(&config.RecoilStrength, "recoil", config.RecoilStrength, "velocity kick per unit of cannon weight when firing (0 = off)")
+	flag.IntVar(&config.CurrentCount, "currents", config.CurrentCount, "number of wind/current zones that push ships (0 = none)")
+	flag.Float64Var(&config.CurrentStrength, "current-strength", config.CurrentStrength, "drift velocity per second each current adds to ships inside it")
+	flag.IntVar(&config.SpawnCandidates, "spawn-candidates", config.SpawnCandidates, "random spawn points sampled, keeping the one farthest from enemies (1 = plain random)")
+	flag.IntVar(&config.RoundKillTarget, "round-kills", config.RoundKillTarget, "kills that win a round (0 = no kill target)")
+	flag.DurationVar(&config.RoundTimeLimit, "round-time", config.RoundTimeLimit, "round length; the top scorer wins when it runs out (0 = no limit)")
+	flag.DurationVar(&config.IdleTimeout, "idle-timeout", config.IdleTimeout, "disconnect clients that send nothing for this long (0 = never)")
+	flag.DurationVar(&config.AFKTimeout, "afk-timeout", config.AFKTimeout, "disconnect living players who don't steer, aim or fire for this long (0 = never)")
+	flag.BoolVar(&config.RankedCosmetics, "ranked-cosmetics", config.RankedCosmetics, "only allow hull colors a player's account has unlocked")
+	accountsFile := flag.String("accounts-file", "", "JSON file that persists cosmetic unlocks (empty = in memory only)")
+	sideCannonMode := flag.String("side-cannons", string(config.SideCannonMode), "side cannon aiming: broadside, target or aim")
+	flag.Float64Var(&config.SideCannonArc, "side-cannon-arc", config.SideCannonArc, "half-angle in radians side cannons may cover in target and aim modes")
+	flag.BoolVar(&config.FogOfWar, "fog", config.FogOfWar, "hide ships beyond each player's vision range (crow's nest upgrades extend it)")
+	flag.BoolVar(&config.CombatLog, "combat-log", config.CombatLog, "log each ship's shots, hits and damage when it sinks")
+	flag.Float64Var(&config.CoinDropFraction, "coin-drop", config.CoinDropFraction, "fraction of a sunk ship's coins spilled as loot at the wreck (0 = off)")
+	flag.DurationVar(&config.SideCannonRipple, "ripple", config.SideCannonRipple, "delay between side cannons firing in sequence (0 = whole broadside at once)")
+	mode := flag.String("mode", string(config.Mode), "game mode: ffa, battleRoyale or teams")
+	botDifficulty := flag.String("bot-difficulty", string(config.Bots.Difficulty), "bot difficulty: passive, normal or aggressive")
+	botCount := flag.Int("bots", config.Bots.Count, "number of bots to spawn")
+	botRespawn := flag.Duration("bot-respawn", config.Bots.RespawnDelay, "how long a sunk bot waits before respawning")
+	dummies := flag.Int("dummies", config.Bots.Dummies, "stationary target dummies for a practice range (0 = none)")
+	botFill := flag.Int("bot-fill", config.Bots.FillTo, "keep humans plus bots at this many ships, retiring bots as humans join (0 = always run every bot)")
+	logLevel := flag.String("log-level", "info", "minimum log level: debug, info, warn or error")
+	logFormat := flag.String("log-format", "text", "log output format: text or json")
+	flag.Parse()
 
-	log.Println("Starting Goblons multiplayer server...")
-	if err := srv.Start(":8080"); err != nil {
-		log.Fatal("Server failed to start:", err)
+	if err := setupLogging(*logLevel, *logFormat); err != nil {
+		slog.Error("Invalid logging flags", "err", err)
+		os.Exit(2)
 	}
+
+	config.Mode = game.GameMode(*mode)
+	config.SideCannonMode = game.SideCannonMode(*sideCannonMode)
+	config.Bots = game.NewBotConfig(game.BotDifficulty(*botDifficulty))
+	config.Bots.Count = *botCount
+	config.Bots.RespawnDelay = *botRespawn
+	config.Bots.FillTo = *botFill
+	config.Bots.Dummies = *dummies
+
+	if err := config.Validate(); err != nil {
+		slog.Error("Invalid flags", "err", err)
+		os.Exit(2)
+	}
+
+	config.Sessions = game.NewSessionLoadouts()
+	config.Accounts = game.NewMemoryAccountStore()
+	if *accountsFile != "" {
+		accounts, err := game.NewFileAccountStore(*accountsFile)
+		if err != nil {
+			slog.Error("Could not load accounts", "file", *accountsFile, "err", err)
+			os.Exit(1)
+		}
+		config.Accounts = accounts
+	}
+
+	srv := server.NewServer(config)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("Starting Goblons multiplayer server")
+		serveErr <- srv.Start(":8080")
+	}()
+
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Server failed to start", "err", err)
+			os.Exit(1)
+		}
+	case <-ctx.Done():
+		// Give clients a moment to receive their close frames
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			slog.Error("Graceful shutdown failed", "err", err)
+		}
+	}
+}
+
+// setupLogging installs the default structured logger for the given level and format
+func setupLogging(level, format string) error {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return err
+	}
+
+	options := &slog.HandlerOptions{Level: lvl}
+	var handler slog.Handler
+	switch format {
+	case "text":
+		handler = slog.NewTextHandler(os.Stderr, options)
+	case "json":
+		handler = slog.NewJSONHandler(os.Stderr, options)
+	default:
+		return fmt.Errorf("unknown log format %q", format)
+	}
+
+	slog.SetDefault(slog.New(handler))
+	return nil
 }
@@ -1,10 +1,31 @@
 import { decode, encode } from "@msgpack/msgpack";
 import pako from 'pako';
 
-// Game constants (should match backend)
-const WorldWidth = 5000.0;
-const WorldHeight = 5000.0;
-const PRESET_COLORS = ['#FF0040', '#00FF80', '#0080FF', '#FF8000', '#8000FF'];
+// Game constants (defaults until the server sends mapInfo)
+let WorldWidth = 5000.0;
+let WorldHeight = 5000.0;
+// Hull colors offered until the server sends its own list (PresetColors) in the unlocks message
+const PRESET_COLORS = ['#FF6B6B', '#4ECDC4', '#45B7D1', '#96CEB4', '#FFEAA7', '#DDA0DD', '#98D8C8', '#F7DC6F'];
+const FLAG_ICONS = { jollyRoger: '🏴‍☠️', skull: '💀', crown: '👑', anchor: '⚓' };
+const ACCOUNT_TOKEN_KEY = 'goblonsAccount';
+const SESSION_TOKEN_KEY = 'goblonsSession';
+// Message format this client speaks; must match the server's ProtocolVersion
+const PROTOCOL_VERSION = 1;
+const PROTOCOL_MISMATCH_CLOSE_CODE = 4003;
+const LOBBY_CONFIRM_WINDOW = 2000; // ms to press Escape a second time to leave for the lobby
+const DUEL_INVITE_TIMEOUT = 30000; // ms an invite can be answered (matches the server's DuelInviteTimeout)
+// Bullet colors by the server's bullet kind (index = kind, 0 = plain cannon)
+const BULLET_STYLES = [
+  { fill: '#484848ff', stroke: '#2a2a2aff' }, // cannon
+  { fill: '#5a5a5aff', stroke: '#2a2a2aff' }, // turret
+  { fill: '#8a8a8aff', stroke: '#4a4a4aff' }, // machine gun
+  { fill: '#2e2e2eff', stroke: '#101010ff' }, // big shell
+  { fill: '#7fa8d0ff', stroke: '#3d5a78ff' }, // flak
+  { fill: '#6b5a45ff', stroke: '#3a3025ff' }, // scatter
+  { fill: '#ff7a1aff', stroke: '#b03a00ff' }, // incendiary
+  { fill: '#6fbf73ff', stroke: '#2f6b33ff' }, // ricochet
+  { fill: '#c0c8d0ff', stroke: '#5a6270ff' }  // piercing
+];
 const NAME_POOL = ['Pirate', 'Buccaneer', 'Sailor', 'Captain', 'Admiral', 'Navigator', 'Corsair', 'Raider'];
 
 class GameClient {
@@ -12,7 +33,12 @@ class GameClient {
     this.playerConfig = {
       name: sanitizePlayerName(options.playerName),
       color: sanitizeHexColor(options.playerColor),
+      shipClass: options.shipClass || 'sloop',
+      spectateKiller: options.spectateKiller !== false, // Follow the killer's ship while dead
+      flag: options.playerFlag || '',
     };
+    this.unlocks = null; // Colors and flags our account may use, sent by the server
+    this.onUnlocks = null; // Called when the server sends our unlocks
     this.autoConnect = options.autoConnect !== false;
     this.shouldStartGame = options.shouldStartGame || false; // Flag to auto-start game
     this.hasStartedGame = false; // Track if player has already started the game
@@ -55,21 +81,30 @@ class GameClient {
 
     // Action sequencing for deduplication
     this.actionSequence = 0;
+    this.sentActionSequence = 0; // Newest action sent at least once
     this.pendingActions = new Map(); // actionType -> {sequence, timestamp}
     this.actionCooldowns = {
       statUpgrade: 100,     // 150ms between stat upgrades (matches backend)
       toggleAutofire: 400,  // 400ms between autofire toggles (matches backend)
+      toggleAimedAutofire: 400, // 400ms between aimed autofire toggles (matches backend)
+      respecStats: 60000,   // 60s between stat respecs (matches backend)
+      dash: 500,            // Server enforces the real dash cooldown
+      saveLoadout: 1000,    // 1s between loadout saves (matches backend)
+      returnToLobby: 1000   // 1s between leave requests (matches backend)
     };
 
+    // Module picks made this life, in order, for saving as a loadout preset
+    this.lifeModules = [];
+
     // Ship physics properties for client-side prediction
 
     this.shipPhysics = {
       angle: 0,           // Current facing direction (radians)
       velocity: { x: 0, y: 0 },  // Current velocity
-      acceleration: 20000000,   // Forward acceleration (doubled for 30 TPS to match server)
-      deceleration: 0.84,  // Drag/friction factor (adjusted for 30 TPS to match server)
-      turnSpeed: 0.08,      // How fast the ship turns (doubled for 30 TPS to match server)
-      maxSpeed: 4.0        // Maximum speed (already matches server at 30 TPS)
+      acceleration: 20000000,   // Forward acceleration
+      deceleration: 0.84,  // Drag/friction factor (matches server)
+      turnSpeed: 2.4,      // How fast the ship turns in radians per second (matches server)
+      maxSpeed: 120        // Maximum speed in units per second (matches server)
     };
 
     this.camera = { x: 0, y: 0, targetX: 0, targetY: 0 };
@@ -91,10 +126,11 @@ class GameClient {
     this.inputSendInterval = null;
     this.isSendingInput = false; // Prevent concurrent sends
     this.lastInputSendTime = 0; // Track last send time for throttling
+    this.inputSequence = 0; // Sequence number of the last input sent
 
     // UI state for upgrade system
     this.upgradeUI = {
-      selectedUpgradeType: null, // 'side', 'top', 'front', 'rear'
+      selectedUpgradeType: null, // 'side', 'top', 'top2', 'front', 'rear'
       availableUpgrades: {},     // stores available upgrades for each type
       pendingUpgrade: false,     // prevents multiple upgrade selections
       optionPositions: {},       // stores click positions for upgrade options
@@ -112,6 +148,10 @@ class GameClient {
 
     this.controlsLocked = true;
     this.pendingConnectConfig = null;
+    this.inLobby = false; // Left the water for the start screen (not a death)
+    this.lobbyConfirmUntil = 0; // Escape again before this time to leave for the lobby
+    this.pendingDuelInvite = null; // { playerId, expiresAt } answered with Y or N
+    this.sinkDurationMs = 2000; // How long wrecks take to sink (the server sends its own in mapInfo)
 
     // Death screen state
     this.deathScreen = {
@@ -123,6 +163,7 @@ class GameClient {
     };
 
     this.killNotifications = [];
+    this.damageNumbers = []; // Floating numbers from recent hits
 
     this.resizeCanvas();
     this.init();
@@ -294,6 +335,7 @@ class GameClient {
       const updatedColor =
         newConfig.playerColor !== undefined ? sanitizeHexColor(newConfig.playerColor) : this.playerConfig.color;
       this.playerConfig = {
+        ...this.playerConfig,
         name: updatedName,
         color: updatedColor,
       };
@@ -323,6 +365,7 @@ class GameClient {
 
     const protocol = location.protocol === 'https:' ? 'wss:' : 'ws:';
     const params = new URLSearchParams();
+    params.set('protocol', PROTOCOL_VERSION);
 
     if (this.playerConfig.name) {
       params.set('name', this.playerConfig.name);
@@ -330,6 +373,26 @@ class GameClient {
     if (this.playerConfig.color) {
       params.set('color', this.playerConfig.color);
     }
+    if (this.playerConfig.flag) {
+      params.set('flag', this.playerConfig.flag);
+    }
+    const sessionToken = getStoredToken(sessionStorage, SESSION_TOKEN_KEY);
+    if (sessionToken) {
+      params.set('session', sessionToken);
+    }
+    const accountToken = getAccountToken();
+    if (accountToken) {
+      params.set('account', accountToken);
+    }
+    // Forward ?room= from the page URL so links can point at a specific room
+    // (a duel arena we were sent to takes precedence)
+    const room = this.duelRoom || new URLSearchParams(location.search).get('room');
+    if (room) {
+      params.set('room', room);
+    }
+    if (this.duelRoom && this.duelTicket) {
+      params.set('ticket', this.duelTicket);
+    }
 
     let wsUrl = `${protocol}//${location.host}/ws`;
     const query = params.toString();
@@ -390,7 +453,7 @@ class GameClient {
       this.handleMessage(data);
     };
 
-    this.socket.onclose = () => {
+    this.socket.onclose = (event) => {
       console.log('Disconnected from server');
       this.isConnected = false;
       this.socket = null;
@@ -402,6 +465,11 @@ class GameClient {
         this.inputSendInterval = null;
       }
 
+      // An outdated client would be turned away again; wait for a refresh
+      if (event.code === PROTOCOL_MISMATCH_CLOSE_CODE) {
+        return;
+      }
+
       const reconnectConfig = this.pendingConnectConfig;
       this.pendingConnectConfig = null;
       const delay = reconnectConfig ? 150 : 3000;
@@ -421,6 +489,19 @@ class GameClient {
         // Server tells us our player ID
         console.log('Received welcome message, our player ID is:', data.playerId);
         this.myPlayerId = data.playerId;
+        if (data.protocol !== PROTOCOL_VERSION) {
+          console.warn(`Server speaks protocol ${data.protocol}, this client ${PROTOCOL_VERSION}`);
+        }
+        break;
+
+      case 'mapInfo':
+        // Server tells us the world dimensions
+        WorldWidth = data.worldWidth || WorldWidth;
+        WorldHeight = data.worldHeight || WorldHeight;
+        this.rewardMultipliers = { xp: data.xpMultiplier || 1, coins: data.coinMultiplier || 1 };
+        this.turnCurve = { min: data.minTurnFactor || 0, exponent: data.turnSpeedExponent ?? 1 };
+        this.currents = data.currents || [];
+        this.sinkDurationMs = data.sinkDuration || this.sinkDurationMs;
         break;
 
       case 'availableUpgrades':
@@ -435,14 +516,18 @@ class GameClient {
         break;
 
       case 'snapshot':
-        this.gameState.players = data.players || [];
+        this.gameState.players = (data.players || []).map(player => this.localizeTimers(player, player));
         this.gameState.items = data.items || [];
         this.gameState.bullets = data.bullets || [];
+        this.gameState.zone = data.zone || null;
+        this.addDamageNumbers(data.damageEvents);
 
         // Find our player by the ID we received in the welcome message
         if (this.myPlayerId) {
           const serverPlayer = this.gameState.players.find(p => p.id === this.myPlayerId);
           if (serverPlayer) {
+            this.acknowledgeActions(serverPlayer.lastActionSeq || 0);
+
             // Initialize our player if this is the first time we found them
             if (!this.gameState.myPlayer) {
               console.log('Found our player:', serverPlayer);
@@ -465,7 +550,7 @@ class GameClient {
               this.gameState.myPlayer = serverPlayer;
 
               // Check if player just died (transition from alive to dead)
-              if (serverPlayer.state === 1 && prevState === 0 && !this.deathScreen.visible) { // State 1 = Dead, State 0 = Alive
+              if (serverPlayer.state === 1 && prevState === 0 && !this.deathScreen.visible && !this.inLobby) { // State 1 = Dead, State 0 = Alive
                 this.showDeathScreen(serverPlayer);
               }
               // Don't hide death screen when player respawns - let the respawn button control that
@@ -501,6 +586,9 @@ class GameClient {
         break;
 
       case 'deltaSnapshot':
+        this.gameState.zone = data.zone || null;
+        this.addDamageNumbers(data.damageEvents);
+
         // Remove players that disconnected
         if (data.playersRemoved && data.playersRemoved.length > 0) {
           const removedIds = new Set(data.playersRemoved);
@@ -539,7 +627,13 @@ class GameClient {
         if (data.itemsAdded) {
           // Add new items
           for (const item of data.itemsAdded) {
-            this.gameState.items.push(item);
+            // Moved items (pulled by a magnet) are resent with the same ID
+            const index = this.gameState.items.findIndex(existing => existing.id === item.id);
+            if (index >= 0) {
+              this.gameState.items[index] = item;
+            } else {
+              this.gameState.items.push(item);
+            }
           }
         }
         if (data.itemsRemoved) {
@@ -552,6 +646,8 @@ class GameClient {
         if (this.myPlayerId) {
           const serverPlayer = this.gameState.players.find(p => p.id === this.myPlayerId);
           if (serverPlayer) {
+            this.acknowledgeActions(serverPlayer.lastActionSeq || 0);
+
             // Initialize our player if this is the first time we found them
             if (!this.gameState.myPlayer) {
               console.log('Found our player:', serverPlayer);
@@ -574,7 +670,7 @@ class GameClient {
               this.gameState.myPlayer = serverPlayer;
 
               // Check if player just died (transition from alive to dead)
-              if (serverPlayer.state === 1 && prevState === 0 && !this.deathScreen.visible) { // State 1 = Dead, State 0 = Alive
+              if (serverPlayer.state === 1 && prevState === 0 && !this.deathScreen.visible && !this.inLobby) { // State 1 = Dead, State 0 = Alive
                 this.showDeathScreen(serverPlayer);
               }
               // Don't hide death screen when player respawns - let the respawn button control that
@@ -621,6 +717,59 @@ class GameClient {
         this.handleGameEvent(data);
         break;
 
+      case 'emote':
+        // Nearby player sent a signal
+        {
+          const sender = this.gameState.players.find(p => p.id === data.playerId);
+          const senderName = sender && sender.name ? sender.name : 'Someone';
+          this.addNotification(`${senderName}: ${data.emote}`);
+        }
+        break;
+
+      case 'correction':
+        // Server disagrees with our prediction; snap to its authoritative state
+        this.predictedPlayerPos.x = data.x;
+        this.predictedPlayerPos.y = data.y;
+        this.shipPhysics.velocity.x = data.velX;
+        this.shipPhysics.velocity.y = data.velY;
+        this.shipPhysics.angle = data.angle;
+        break;
+
+      case 'chat':
+        this.addNotification(`${data.name || 'Someone'}: ${data.message}`);
+        break;
+
+      case 'duel':
+        this.handleDuelMessage(data);
+        break;
+
+      case 'roundEnd': {
+        // Scores and kills reset on the server right after this
+        const won = data.winnerId && data.winnerId === this.myPlayerId;
+        const reason = data.reason === 'kills' ? `${data.kills} kills` : `${data.score} points`;
+        if (won) {
+          this.addNotification(`You won round ${data.round} with ${reason}!`, 5000);
+        } else if (data.winnerId) {
+          this.addNotification(`${data.winnerName || 'Someone'} won round ${data.round} with ${reason}`, 5000);
+        } else {
+          this.addNotification(`Round ${data.round} over`, 5000);
+        }
+        break;
+      }
+
+      case 'unlocks':
+        this.unlocks = data;
+        if (this.onUnlocks) {
+          this.onUnlocks(data);
+        }
+        break;
+
+      case 'error':
+        // Server is rejecting or disconnecting us; a close frame follows
+        console.warn(`Server error (${data.code}): ${data.message}`);
+        this.addNotification(data.message);
+        break;
+
       case 'resetShipConfig':
         // Server tells us to reset our ship config
         if (this.myPlayerId && data.shipConfig) {
@@ -648,14 +797,109 @@ class GameClient {
         }
         break;
       case 'playerSunk':
-        if (!data.killerId || data.killerId === this.myPlayerId) {
+        {
           const victim = data.victimName && data.victimName.trim() ? data.victimName : 'Enemy';
-          this.addNotification(`${victim} sunk!`);
+          if (!data.killerId || data.killerId === this.myPlayerId) {
+            this.addNotification(`${victim} sunk!`);
+          } else {
+            const killer = data.killerName && data.killerName.trim() ? data.killerName : 'Someone';
+            this.addNotification(`${killer} sunk ${victim}`);
+          }
+        }
+        break;
+      case 'bounty':
+        if (data.victimId === this.myPlayerId) {
+          this.addNotification('You are the bounty target!');
+        } else {
+          this.addNotification(`Bounty on ${data.victimName && data.victimName.trim() ? data.victimName : 'Enemy'}!`);
         }
         break;
+      case 'combatSummary':
+        this.showCombatSummary(data.combat || {});
+        break;
+      case 'returnedToLobby':
+        // Back to the start screen; our progress waits for the next "Set Sail"
+        this.inLobby = true;
+        if (window.goblonsIntro) {
+          window.goblonsIntro.show();
+        }
+        break;
+      case 'dummyReport': {
+        const report = data.dummyReport || {};
+        this.addNotification(`${data.victimName || 'Dummy'}: ${Math.round(report.damage || 0)} damage in ${(report.seconds || 0).toFixed(1)}s (${Math.round(report.dps || 0)} DPS)`, 5000);
+        break;
+      }
+      case 'lobbyBlocked':
+        this.addNotification("Can't leave for the lobby while under fire");
+        break;
+      case 'colorRejected':
+        this.addNotification("That hull color isn't unlocked, keeping your current color");
+        break;
+      case 'assist':
+        this.addNotification(`Assist on ${data.victimName && data.victimName.trim() ? data.victimName : 'Enemy'}!`);
+        break;
       case 'itemCollected':
         // Could add visual effects for item collection
         break;
+      case 'levelUp':
+        if (this.gameState.myPlayer) {
+          // Don't wait for the delta; the upgrade prompt depends on this
+          this.gameState.myPlayer.level = data.level;
+          this.gameState.myPlayer.availableUpgrades = data.availableUpgrades || 0;
+        }
+        this.addNotification(`Level ${data.level}! Choose an upgrade`);
+        break;
+      case 'upgradeApplied':
+        if (this.gameState.myPlayer) {
+          this.gameState.myPlayer.availableUpgrades = data.availableUpgrades || 0;
+        }
+        if (data.upgrade) {
+          const separator = data.upgrade.indexOf(':');
+          this.lifeModules.push({ slot: data.upgrade.slice(0, separator), choice: data.upgrade.slice(separator + 1) });
+        }
+        break;
+      case 'loadoutSaved':
+        this.addNotification('Loadout saved');
+        break;
+      case 'loadoutRejected':
+        this.addNotification('Loadout could not be saved');
+        break;
+    }
+  }
+
+  handleDuelMessage(data) {
+    switch (data.event) {
+      case 'invite':
+        // Answered from the keyboard so the game keeps running meanwhile
+        this.pendingDuelInvite = { playerId: data.playerId, expiresAt: Date.now() + DUEL_INVITE_TIMEOUT };
+        this.addNotification(`${data.name || 'Someone'} challenges you to a duel! Y to accept, N to decline`, DUEL_INVITE_TIMEOUT);
+        break;
+      case 'declined':
+        this.addNotification(`${data.name || 'Your opponent'} declined the duel`);
+        break;
+      case 'start':
+        // Reconnect into the private arena; startGame is sent once connected
+        this.addNotification(`Duel against ${data.name || 'opponent'}!`);
+        this.duelRoom = data.room;
+        this.duelTicket = data.ticket || '';
+        this.connect({}, { force: true });
+        break;
+      case 'end': {
+        const won = data.winnerId === this.myPlayerId;
+        this.addNotification(won ? 'You won the duel!' : `${data.winnerName || 'Your opponent'} won the duel`, 4000);
+        // Head back to the lobby once the result has been read
+        setTimeout(() => {
+          this.duelRoom = null;
+          this.connect({}, { force: true });
+        }, 4000);
+        break;
+      }
+    }
+  }
+
+  sendDuel(type, playerId) {
+    if (this.socket && this.socket.readyState === WebSocket.OPEN) {
+      this.socket.send(encode({ type, duelTarget: playerId }));
     }
   }
 
@@ -703,6 +947,20 @@ class GameClient {
     console.log('Death screen shown:', this.deathScreen);
   }
 
+  // Fill the death screen's combat rows; the server sends these as the ship sinks
+  showCombatSummary(combat) {
+    const deathAccuracy = document.getElementById('deathAccuracy');
+    const deathDamageDealt = document.getElementById('deathDamageDealt');
+    const deathDamageTaken = document.getElementById('deathDamageTaken');
+
+    if (deathAccuracy) {
+      deathAccuracy.textContent = combat.shotsFired ?
+        `${Math.round((combat.accuracy || 0) * 100)}% (${combat.hits || 0}/${combat.shotsFired})` : '-';
+    }
+    if (deathDamageDealt) deathDamageDealt.textContent = Math.round(combat.damageDealt || 0);
+    if (deathDamageTaken) deathDamageTaken.textContent = Math.round(combat.damageTaken || 0);
+  }
+
   handleRespawn() {
     console.log('Respawn requested');
     this.deathScreen.visible = false;
@@ -713,6 +971,9 @@ class GameClient {
       deathScreen.classList.remove('visible');
     }
 
+    // A new life starts a new build
+    this.lifeModules = [];
+
     // Send respawn request to server
     this.input.requestRespawn = true;
     this.sendInput();
@@ -787,28 +1048,98 @@ class GameClient {
 
     let inputChanged = false;
 
-    // Handle stat upgrade keys (1-8) using new action system
+    // Answer a pending duel invite
+    if (this.pendingDuelInvite && ['y', 'Y', 'n', 'N'].includes(e.key)) {
+      const invite = this.pendingDuelInvite;
+      this.pendingDuelInvite = null;
+      if (Date.now() < invite.expiresAt) {
+        this.sendDuel(e.key === 'y' || e.key === 'Y' ? 'duelAccept' : 'duelDecline', invite.playerId);
+      }
+      return;
+    }
+
+    // Handle stat upgrade keys (1-9, 0 and - for the tenth and eleventh stats) using new action system
     // queueAction sends immediately, so no need to set inputChanged
-    if (e.key >= '1' && e.key <= '8') {
-      const keyNumber = parseInt(e.key);
+    if ((e.key >= '0' && e.key <= '9') || e.key === '-') {
       const statKeyMap = {
-        1: 'hullStrength',
-        2: 'autoRepairs',
-        3: 'cannonRange',
-        4: 'cannonDamage',
-        5: 'reloadSpeed',
-        6: 'moveSpeed',
-        7: 'turnSpeed',
-        8: 'bodyDamage'
+        '1': 'hullStrength',
+        '2': 'autoRepairs',
+        '3': 'cannonRange',
+        '4': 'cannonDamage',
+        '5': 'reloadSpeed',
+        '6': 'moveSpeed',
+        '7': 'turnSpeed',
+        '8': 'bodyDamage',
+        '9': 'accuracy',
+        '0': 'magnet',
+        '-': 'vision'
       };
 
-      const statKey = statKeyMap[keyNumber];
+      const statKey = statKeyMap[e.key];
       if (statKey) {
         this.queueAction('statUpgrade', statKey);
       }
       return; // Early return, action already sent
     }
 
+    // Dash forward (server enforces the cooldown)
+    if (e.key === ' ') {
+      e.preventDefault();
+      this.queueAction('dash', '');
+      return;
+    }
+
+    // Save this life's build as the loadout replayed every life (Shift clears it)
+    if (e.key === 'k' || e.key === 'K') {
+      this.queueAction('saveLoadout', e.shiftKey ? '' : JSON.stringify(this.currentBuild()));
+      return;
+    }
+
+    // Leave the water for the lobby, keeping progress (not while under fire)
+    // Asks for a second press instead of a blocking dialog that would freeze the ship
+    if (e.key === 'Escape') {
+      const now = Date.now();
+      if (now < this.lobbyConfirmUntil) {
+        this.lobbyConfirmUntil = 0;
+        this.queueAction('returnToLobby', '');
+      } else {
+        this.lobbyConfirmUntil = now + LOBBY_CONFIRM_WINDOW;
+        this.addNotification('Press Escape again to leave for the lobby (you keep your progress)', LOBBY_CONFIRM_WINDOW);
+      }
+      return;
+    }
+
+    // Refund and reset all stat upgrades
+    if (e.key === 'p' || e.key === 'P') {
+      if (window.confirm('Reset all stat upgrades for a partial coin refund?')) {
+        this.queueAction('respecStats', '');
+      }
+      return;
+    }
+
+    // Open a chat prompt; movement keys are released while it is open
+    if (e.key === 'Enter') {
+      e.preventDefault();
+      this.clearActiveInputs();
+      this.sendInput();
+      const message = window.prompt('Chat (/duel <name> to challenge a player)');
+      const duelMatch = message && message.match(/^\/duel\s+(.+)$/i);
+      if (duelMatch) {
+        const targetName = duelMatch[1].trim().toLowerCase();
+        const target = this.gameState.players.find(p =>
+          p.id !== this.myPlayerId && !p.isBot && (p.name || '').toLowerCase() === targetName);
+        if (target) {
+          this.sendDuel('duelInvite', target.id);
+          this.addNotification(`Challenged ${target.name} to a duel`);
+        } else {
+          this.addNotification(`No player named ${duelMatch[1].trim()}`);
+        }
+      } else if (message) {
+        this.sendChat(message);
+      }
+      return;
+    }
+
     // Handle autofire toggle using new action system
     // queueAction sends immediately, so no need to set inputChanged
     if (e.key === 'r' || e.key === 'R') {
@@ -830,6 +1161,12 @@ class GameClient {
       return; // Early return, action already sent
     }
 
+    // Toggle aimed autofire: weapons only shoot when pointed at an enemy
+    if (e.key === 't' || e.key === 'T') {
+      this.queueAction('toggleAimedAutofire', '');
+      return;
+    }
+
     // Handle movement keys (continuous state)
     if (e.key === 'w' || e.key === 'W' || e.key === 'ArrowUp') {
       if (!this.input.up) {
@@ -1005,7 +1342,7 @@ class GameClient {
       return false;
     }
 
-    const upgradeTypes = ['side', 'top', 'front', 'rear'];
+    const upgradeTypes = ['side', 'top', 'top2', 'front', 'rear'];
     const availableTypes = upgradeTypes.filter((type) => this.hasAvailableUpgrades(type));
     if (availableTypes.length === 0) {
       return false;
@@ -1119,7 +1456,10 @@ class GameClient {
       5: 'reloadSpeed',
       6: 'moveSpeed',
       7: 'turnSpeed',
-      8: 'bodyDamage'
+      8: 'bodyDamage',
+      9: 'accuracy',
+      0: 'magnet',
+      '-': 'vision'
     };
 
     const statNames = {
@@ -1130,7 +1470,10 @@ class GameClient {
       'reloadSpeed': 'Reload Speed',
       'moveSpeed': 'Move Speed',
       'turnSpeed': 'Turn Speed',
-      'bodyDamage': 'Body Damage'
+      'bodyDamage': 'Body Damage',
+      'accuracy': 'Accuracy',
+      'magnet': 'Item Magnet',
+      'vision': "Crow's Nest"
     };
 
     const statKey = statKeyMap[keyNumber];
@@ -1175,6 +1518,31 @@ class GameClient {
   }
 
   // Queue an action with deduplication and cooldown checking
+  // currentBuild describes this life's module picks and stat levels as a
+  // loadout preset; stats are interleaved one level at a time so a replayed
+  // build spreads its coins the way most players do
+  currentBuild() {
+    const levels = {};
+    const upgrades = (this.gameState.myPlayer && this.gameState.myPlayer.statUpgrades) || {};
+    for (const [stat, upgrade] of Object.entries(upgrades)) {
+      levels[stat] = upgrade.level || 0;
+    }
+
+    const stats = [];
+    let remaining = true;
+    while (remaining) {
+      remaining = false;
+      for (const stat of Object.keys(levels).sort()) {
+        if (levels[stat] > 0) {
+          stats.push(stat);
+          levels[stat]--;
+          remaining = true;
+        }
+      }
+    }
+    return { modules: this.lifeModules, stats: stats };
+  }
+
   queueAction(actionType, data = '') {
     const now = Date.now();
 
@@ -1201,8 +1569,9 @@ class GameClient {
       timestamp: now
     });
 
-    // Add to actions queue (clear old ones first)
-    this.input.actions = this.input.actions.filter(a => a.type !== actionType);
+    // Add to actions queue (replacing an unsent one of the same type; sent ones
+    // stay until acknowledged)
+    this.input.actions = this.input.actions.filter(a => a.type !== actionType || a.sequence <= this.sentActionSequence);
     this.input.actions.push(action);
 
     // Immediately send the input with the queued action
@@ -1211,6 +1580,13 @@ class GameClient {
     return true;
   }
 
+  // Drop queued actions the server has processed (lastActionSeq)
+  acknowledgeActions(sequence) {
+    if (this.input.actions.length > 0) {
+      this.input.actions = this.input.actions.filter(a => a.sequence > sequence);
+    }
+  }
+
   sendInput() {
     if (this.controlsLocked) {
       return;
@@ -1226,7 +1602,7 @@ class GameClient {
 
     // For actions, send immediately. For regular movement, throttle to 30 FPS to match server tick rate
     const now = Date.now();
-    const hasActions = this.input.actions.length > 0;
+    const hasActions = this.input.actions.some(a => a.sequence > this.sentActionSequence);
     const timeSinceLastSend = now - this.lastInputSendTime;
 
     if (!hasActions && timeSinceLastSend < 33) {
@@ -1238,11 +1614,18 @@ class GameClient {
     this.lastInputSendTime = now;
 
     try {
+      // Number each input and attach our predicted position so the server can correct us
+      this.inputSequence++;
+      this.input.seq = this.inputSequence;
+      this.input.predictedX = this.predictedPlayerPos.x;
+      this.input.predictedY = this.predictedPlayerPos.y;
+
       // Send the current input state
       this.socket.send(encode(this.input));
 
-      // Clear actions after successful send
-      this.input.actions = [];
+      // Keep actions until the server acknowledges them; it skips repeats, and
+      // an input replaced before its tick would otherwise lose them
+      this.sentActionSequence = this.actionSequence;
     } finally {
       this.isSendingInput = false;
     }
@@ -1320,15 +1703,17 @@ class GameClient {
     const speed = Math.min(Math.sqrt(physics.velocity.x * physics.velocity.x + physics.velocity.y * physics.velocity.y), physics.maxSpeed);
 
     // Scale turn speed based on current speed (matching server logic)
-    let turnFactor = speed / physics.maxSpeed;
+    const turnCurve = this.turnCurve || { min: 0, exponent: 1 };
+    const minTurn = Math.max(0, Math.min(turnCurve.min, 1));
+    const turnFactor = minTurn + (1 - minTurn) * Math.pow(speed / physics.maxSpeed, turnCurve.exponent);
     const scaledTurnSpeed = physics.turnSpeed * turnFactor;
 
     // Handle turning (A/D keys) with speed-based scaling
     if (this.input.left) {
-      physics.angle -= scaledTurnSpeed;
+      physics.angle -= scaledTurnSpeed * deltaTime;
     }
     if (this.input.right) {
-      physics.angle += scaledTurnSpeed;
+      physics.angle += scaledTurnSpeed * deltaTime;
     }
 
     // Apply drag/deceleration
@@ -1343,9 +1728,12 @@ class GameClient {
       physics.velocity.y *= speedRatio;
     }
 
-    // Update predicted position with delta time scaling for 30 TPS
-    const moveX = physics.velocity.x * deltaTime * 30; // Matches server physics
-    const moveY = physics.velocity.y * deltaTime * 30;
+    // Currents push the ship on top of its own velocity, as on the server
+    const current = this.currentAt(this.predictedPlayerPos.x, this.predictedPlayerPos.y);
+
+    // Update predicted position (server velocities are per second)
+    const moveX = (physics.velocity.x + current.x) * deltaTime;
+    const moveY = (physics.velocity.y + current.y) * deltaTime;
 
     this.predictedPlayerPos.x += moveX;
     this.predictedPlayerPos.y += moveY;
@@ -1369,6 +1757,19 @@ class GameClient {
     }
   }
 
+  // getCameraFocus returns the ship the camera follows: our own, or our
+  // killer's while dead (the server centers our snapshots on it too)
+  getCameraFocus() {
+    const me = this.gameState.myPlayer;
+    if (this.playerConfig.spectateKiller && me.state === 1 && me.killedBy) {
+      const killer = this.gameState.players.find(p => p.id === me.killedBy && p.id !== me.id && p.state === 0);
+      if (killer) {
+        return killer;
+      }
+    }
+    return me;
+  }
+
   updateCamera() {
     if (this.gameState.myPlayer) {
       // Store previous camera position to detect changes
@@ -1378,8 +1779,9 @@ class GameClient {
       // Use server position for camera to avoid jitter
       // this.camera.targetX = this.predictedPlayerPos.x - this.screenWidth / 2;
       // this.camera.targetY = this.predictedPlayerPos.y - this.screenHeight / 2;
-      this.camera.targetX = this.gameState.myPlayer.x - this.screenWidth / 2;
-      this.camera.targetY = this.gameState.myPlayer.y - this.screenHeight / 2;
+      const focus = this.getCameraFocus();
+      this.camera.targetX = focus.x - this.screenWidth / 2;
+      this.camera.targetY = focus.y - this.screenHeight / 2;
 
       // Smooth camera movement
       const cameraLerpFactor = 1;
@@ -1400,12 +1802,11 @@ class GameClient {
     const deltaTime = Math.min((currentTime - this.lastBulletUpdate) / 1000, 1 / 30); // Cap deltaTime
     this.lastBulletUpdate = currentTime;
 
-    // Update bullet positions based on velocity (scaled for backend's 30 TPS)
+    // Update bullet positions based on velocity (units per second)
     for (const bullet of this.gameState.bullets) {
       if (bullet.velX !== undefined && bullet.velY !== undefined) {
-        // Backend velocities are tuned for 30 TPS, so scale by 30 for per-second movement
-        bullet.x += bullet.velX * 30 * deltaTime;
-        bullet.y += bullet.velY * 30 * deltaTime;
+        bullet.x += bullet.velX * deltaTime;
+        bullet.y += bullet.velY * deltaTime;
       }
     }
 
@@ -1440,6 +1841,12 @@ class GameClient {
     // Draw map border
     this.drawMapBorder();
 
+    // Draw wind and current zones under everything else
+    this.drawCurrents();
+
+    // Draw battle-royale zone
+    this.drawZone();
+
     // Draw items
     this.gameState.items.forEach(item => {
       this.drawItem(item);
@@ -1455,6 +1862,9 @@ class GameClient {
       this.drawPlayer(player);
     });
 
+    // Damage numbers float above the ships they hit
+    this.drawDamageNumbers();
+
     // Draw UI
     this.drawUI();
 
@@ -1488,6 +1898,124 @@ class GameClient {
     this.ctx.stroke();
   }
 
+  // currentAt returns the summed drift of every current covering a position
+  currentAt(x, y) {
+    const drift = { x: 0, y: 0 };
+    (this.currents || []).forEach(current => {
+      if (x >= current.x && x <= current.x + current.width && y >= current.y && y <= current.y + current.height) {
+        drift.x += current.velX;
+        drift.y += current.velY;
+      }
+    });
+    return drift;
+  }
+
+  addDamageNumbers(events) {
+    if (!events) {
+      return;
+    }
+
+    const now = Date.now();
+    for (const event of events) {
+      this.damageNumbers.push({
+        x: event.x + (Math.random() - 0.5) * 30, // Spread hits on the same ship apart
+        y: event.y,
+        amount: Math.round(event.amount),
+        mine: event.targetId === this.myPlayerId,
+        createdAt: now,
+      });
+    }
+  }
+
+  drawDamageNumbers() {
+    const lifetime = 900;
+    const now = Date.now();
+    this.damageNumbers = this.damageNumbers.filter(number => now - number.createdAt < lifetime);
+    if (this.damageNumbers.length === 0) {
+      return;
+    }
+
+    this.ctx.save();
+    this.ctx.font = 'bold 16px Arial';
+    this.ctx.textAlign = 'center';
+    this.ctx.lineWidth = 3;
+    this.ctx.strokeStyle = 'rgba(15, 15, 35, 0.65)';
+    for (const number of this.damageNumbers) {
+      const progress = (now - number.createdAt) / lifetime;
+      const screenX = number.x - this.camera.x;
+      const screenY = number.y - this.camera.y - 40 - progress * 30; // Drift upward as it fades
+      this.ctx.globalAlpha = 1 - progress;
+      this.ctx.fillStyle = number.mine ? '#FF5555' : '#FFD24A';
+      this.ctx.strokeText(`${number.amount}`, screenX, screenY);
+      this.ctx.fillText(`${number.amount}`, screenX, screenY);
+    }
+    this.ctx.restore();
+  }
+
+  drawCurrents() {
+    if (!this.currents || this.currents.length === 0) {
+      return;
+    }
+
+    const arrowSpacing = 120;
+    const phase = (Date.now() / 40) % arrowSpacing; // Arrows creep along the flow
+    this.ctx.save();
+    this.ctx.fillStyle = 'rgba(80, 160, 220, 0.12)';
+    this.ctx.strokeStyle = 'rgba(40, 110, 180, 0.45)';
+    this.ctx.lineWidth = 2;
+
+    this.currents.forEach(current => {
+      const screenX = current.x - this.camera.x;
+      const screenY = current.y - this.camera.y;
+      if (screenX > this.screenWidth || screenY > this.screenHeight ||
+        screenX + current.width < 0 || screenY + current.height < 0) {
+        return;
+      }
+
+      this.ctx.fillRect(screenX, screenY, current.width, current.height);
+
+      // Draw a grid of arrows pointing downstream, clipped to the zone
+      const angle = Math.atan2(current.velY, current.velX);
+      const offsetX = Math.cos(angle) * phase;
+      const offsetY = Math.sin(angle) * phase;
+      this.ctx.save();
+      this.ctx.beginPath();
+      this.ctx.rect(screenX, screenY, current.width, current.height);
+      this.ctx.clip();
+      this.ctx.beginPath();
+      for (let x = arrowSpacing / 2 - arrowSpacing; x < current.width + arrowSpacing; x += arrowSpacing) {
+        for (let y = arrowSpacing / 2 - arrowSpacing; y < current.height + arrowSpacing; y += arrowSpacing) {
+          const cx = screenX + x + offsetX;
+          const cy = screenY + y + offsetY;
+          const tipX = cx + Math.cos(angle) * 18;
+          const tipY = cy + Math.sin(angle) * 18;
+          this.ctx.moveTo(cx - Math.cos(angle) * 18, cy - Math.sin(angle) * 18);
+          this.ctx.lineTo(tipX, tipY);
+          this.ctx.moveTo(tipX + Math.cos(angle + 2.5) * 10, tipY + Math.sin(angle + 2.5) * 10);
+          this.ctx.lineTo(tipX, tipY);
+          this.ctx.lineTo(tipX + Math.cos(angle - 2.5) * 10, tipY + Math.sin(angle - 2.5) * 10);
+        }
+      }
+      this.ctx.stroke();
+      this.ctx.restore();
+    });
+
+    this.ctx.restore();
+  }
+
+  drawZone() {
+    const zone = this.gameState.zone;
+    if (!zone) {
+      return;
+    }
+
+    this.ctx.strokeStyle = 'rgba(200, 40, 120, 0.8)';
+    this.ctx.lineWidth = 5;
+    this.ctx.beginPath();
+    this.ctx.arc(zone.x - this.camera.x, zone.y - this.camera.y, zone.radius, 0, Math.PI * 2);
+    this.ctx.stroke();
+  }
+
   drawMapBorder() {
     // Convert world coordinates to screen coordinates (CSS pixel space)
     const borderLeft = 0 - this.camera.x;
@@ -1530,8 +2058,12 @@ class GameClient {
   }
 
   drawPlayer(player) {
+    if (player.state !== 0 && !player.sinking) {
+      return; // Skip rendering players that are not alive (wrecks are drawn while sinking)
+    }
     if (player.state !== 0) {
-      return; // Skip rendering players that are not alive
+      this.drawSinkingPlayer(player);
+      return;
     }
     const ctx = this.ctx;
     const screenX = player.x - this.camera.x;
@@ -1556,6 +2088,29 @@ class GameClient {
     ctx.translate(screenX, screenY);
     ctx.rotate(angle);
 
+    // Spawn protection bubble
+    if (player.protected) {
+      ctx.save();
+      ctx.strokeStyle = 'rgba(120, 200, 255, 0.7)';
+      ctx.lineWidth = 3;
+      ctx.beginPath();
+      ctx.arc(0, 0, shaftLength / 2 + bowLength + 10, 0, Math.PI * 2);
+      ctx.stroke();
+      ctx.restore();
+    }
+
+    // Bounty target marker, visible to everyone hunting them
+    if (player.isBounty) {
+      ctx.save();
+      ctx.strokeStyle = 'rgba(255, 200, 0, 0.8)';
+      ctx.lineWidth = 3;
+      ctx.setLineDash([10, 6]);
+      ctx.beginPath();
+      ctx.arc(0, 0, shaftLength / 2 + bowLength + 16, 0, Math.PI * 2);
+      ctx.stroke();
+      ctx.restore();
+    }
+
     // --- Draw cannons and turrets first (under the ship) ---
     ctx.fillStyle = '#666';
     ctx.strokeStyle = '#333';
@@ -1572,7 +2127,7 @@ class GameClient {
           const timeSinceFire = Date.now() - new Date(cannon.recoilTime).getTime();
           const recoilDuration = 400; // 200ms recoil animation
 
-          if (timeSinceFire < recoilDuration) {
+          if (timeSinceFire >= 0 && timeSinceFire < recoilDuration) {
             const progress = timeSinceFire / recoilDuration;
             // Ease-out animation: starts fast, slows down
             const easeOut = 1 - Math.pow(1 - progress, 3);
@@ -1704,7 +2259,7 @@ class GameClient {
             const timeSinceFire = Date.now() - new Date(cannon.recoilTime).getTime();
             const recoilDuration = 400; // 200ms recoil animation
 
-            if (timeSinceFire < recoilDuration) {
+            if (timeSinceFire >= 0 && timeSinceFire < recoilDuration) {
               const progress = timeSinceFire / recoilDuration;
               // Ease-out animation: starts fast, slows down
               const easeOut = 1 - Math.pow(1 - progress, 3);
@@ -1812,7 +2367,7 @@ class GameClient {
           const timeSinceFire = Date.now() - latestRecoilTime;
           const recoilDuration = 200; // 200ms recoil animation
 
-          if (timeSinceFire < recoilDuration) {
+          if (timeSinceFire >= 0 && timeSinceFire < recoilDuration) {
             const progress = timeSinceFire / recoilDuration;
             // Ease-out animation: starts fast, slows down
             const easeOut = 1 - Math.pow(1 - progress, 3);
@@ -1907,13 +2462,21 @@ class GameClient {
     }
 
     // Draw player name with upgrade level prefix above the ship
-    const displayName = (player.name && player.name.trim()) ? player.name.trim() : `Player ${player.id}`;
+    const baseName = (player.name && player.name.trim()) ? player.name.trim() : `Player ${player.id}`;
+    const displayName = FLAG_ICONS[player.flag] ? `${FLAG_ICONS[player.flag]} ${baseName}` : baseName;
     const labelY = screenY - (shaftWidth / 2) - 30;
 
     this.ctx.save();
     this.ctx.lineWidth = 3;
     this.ctx.strokeStyle = 'rgba(15, 15, 35, 0.65)';
-    this.ctx.fillStyle = player.id === this.myPlayerId ? '#FFFFFF' : '#D7D7D7';
+    const myTeam = this.gameState.myPlayer ? this.gameState.myPlayer.team : '';
+    if (player.id === this.myPlayerId) {
+      this.ctx.fillStyle = '#FFFFFF';
+    } else if (myTeam && player.team === myTeam) {
+      this.ctx.fillStyle = '#8CFF8C'; // Teammate
+    } else {
+      this.ctx.fillStyle = '#D7D7D7';
+    }
 
     // Measure both text elements to center them together
     this.ctx.font = 'bold 22px Arial';
@@ -1944,6 +2507,24 @@ class GameClient {
     this.drawHealthBar(player, screenX, screenY);
   }
 
+  // Draws a sunk ship shrinking and fading out over the server's sink window
+  drawSinkingPlayer(player) {
+    const progress = Math.min(1, Math.max(0, (Date.now() - (player.sinkStartedAt || 0)) / this.sinkDurationMs));
+    if (progress >= 1) return;
+
+    const screenX = player.x - this.camera.x;
+    const screenY = player.y - this.camera.y;
+    const scale = 1 - progress * 0.4;
+
+    this.ctx.save();
+    this.ctx.globalAlpha = 1 - progress;
+    this.ctx.translate(screenX, screenY);
+    this.ctx.scale(scale, scale);
+    this.ctx.translate(-screenX, -screenY);
+    this.drawPlayer({ ...player, state: 0, sinking: false, protected: false, health: 0 });
+    this.ctx.restore();
+  }
+
   drawHealthBar(player, screenX, screenY) {
     const ctx = this.ctx;
     const maxHealth = player.maxHealth || 100;
@@ -2021,6 +2602,31 @@ class GameClient {
         size = 14;
         shape = 'diamond';
         break;
+      case 'gold_star':
+        color = '#FFD700'; // Gold
+        size = 16;
+        shape = 'star';
+        break;
+      case 'shield':
+        color = '#7FDBFF'; // Light blue
+        size = 12;
+        shape = 'diamond';
+        break;
+      case 'speed_boost':
+        color = '#2ECC40'; // Green
+        size = 12;
+        shape = 'diamond';
+        break;
+      case 'rapid_reload':
+        color = '#FF4136'; // Red
+        size = 12;
+        shape = 'diamond';
+        break;
+      case 'coin_drop':
+        color = '#FFC125'; // Coin gold
+        size = 9;
+        shape = 'circle';
+        break;
       // Legacy support for old item types
       case 'coin':
         color = '#FFD700';
@@ -2135,6 +2741,8 @@ class GameClient {
         return '#FFA500'; // Bright orange outline
       case 'blue_diamond':
         return '#87CEEB'; // Light blue outline
+      case 'coin_drop':
+        return '#B8860B'; // Dark gold rim
       default:
         return '#ffffff'; // Default white outline
     }
@@ -2151,6 +2759,8 @@ class GameClient {
         return 2; // Thicker outline for uncommon items
       case 'blue_diamond':
         return 2.5; // Thickest outline for rare items
+      case 'coin_drop':
+        return 2;
       default:
         return 1; // Default thin outline
     }
@@ -2166,23 +2776,59 @@ class GameClient {
       return;
     }
 
+    this.drawBulletTrail(bullet, screenX, screenY);
+
     this.ctx.save();
     this.ctx.translate(screenX, screenY);
 
-    // Draw bullet as a bright orange/yellow circle
+    // Color the bullet by the kind of weapon that fired it
+    const style = BULLET_STYLES[bullet.kind || 0] || BULLET_STYLES[0];
     this.ctx.beginPath();
     this.ctx.arc(0, 0, bullet.radius, 0, Math.PI * 2);
-    this.ctx.fillStyle = '#484848ff'; // Gold color for bullets
+    this.ctx.fillStyle = style.fill;
     this.ctx.fill();
 
-    // Add a bright outline
-    this.ctx.strokeStyle = '#2a2a2aff'; // Orange outline
+    // Add an outline
+    this.ctx.strokeStyle = style.stroke;
     this.ctx.lineWidth = 1;
     this.ctx.stroke();
 
     this.ctx.restore();
   }
 
+  // drawBulletTrail draws a fading streak back along the bullet's flight, never
+  // past the point it was fired from, so bullets entering view mid-flight
+  // don't appear to pop out of nowhere
+  drawBulletTrail(bullet, screenX, screenY) {
+    const speed = Math.hypot(bullet.velX || 0, bullet.velY || 0);
+    if (speed === 0 || bullet.originX === undefined) {
+      return;
+    }
+
+    const maxTrailLength = 60;
+    const traveled = Math.hypot(bullet.x - bullet.originX, bullet.y - bullet.originY);
+    const trailLength = Math.min(maxTrailLength, traveled);
+    if (trailLength < 1) {
+      return;
+    }
+
+    const tailX = screenX - (bullet.velX / speed) * trailLength;
+    const tailY = screenY - (bullet.velY / speed) * trailLength;
+    const gradient = this.ctx.createLinearGradient(tailX, tailY, screenX, screenY);
+    gradient.addColorStop(0, 'rgba(72, 72, 72, 0)');
+    gradient.addColorStop(1, 'rgba(72, 72, 72, 0.5)');
+
+    this.ctx.save();
+    this.ctx.strokeStyle = gradient;
+    this.ctx.lineWidth = bullet.radius;
+    this.ctx.lineCap = 'round';
+    this.ctx.beginPath();
+    this.ctx.moveTo(tailX, tailY);
+    this.ctx.lineTo(screenX, screenY);
+    this.ctx.stroke();
+    this.ctx.restore();
+  }
+
   drawUI() {
     // Draw stat upgrade panel (moved to top left)
     this.drawStatUpgradePanel();
@@ -2202,7 +2848,36 @@ class GameClient {
     // Draw autofire status (bottom left)
     this.drawAutofireStatus();
 
+    // Dash cooldown (above autofire status)
+    this.drawDashStatus();
+    this.drawReloadStatus();
+
     this.drawKillNotifications();
+
+    // Announce bonus XP/coin events (top center)
+    this.drawRewardEvent();
+  }
+
+  drawRewardEvent() {
+    if (!this.rewardMultipliers) return;
+
+    const { xp, coins } = this.rewardMultipliers;
+    const parts = [];
+    if (xp !== 1) parts.push(`${xp}x XP`);
+    if (coins !== 1) parts.push(`${coins}x Coins`);
+    if (parts.length === 0) return;
+
+    this.ctx.save();
+    this.ctx.fillStyle = '#FFD700';
+    this.ctx.strokeStyle = 'rgba(0, 0, 0, 0.6)';
+    this.ctx.lineWidth = 3;
+    this.ctx.font = 'bold 18px Arial';
+    this.ctx.textAlign = 'center';
+    this.ctx.textBaseline = 'top';
+    const text = `${parts.join(' \u2022 ')} active!`;
+    this.ctx.strokeText(text, this.screenWidth / 2, 12);
+    this.ctx.fillText(text, this.screenWidth / 2, 12);
+    this.ctx.restore();
   }
 
   drawKillNotifications() {
@@ -2314,13 +2989,17 @@ class GameClient {
         'reloadSpeed': 'Reload Speed',
         'moveSpeed': 'Move Speed',
         'turnSpeed': 'Turn Speed',
-        'bodyDamage': 'Body Damage'
+        'bodyDamage': 'Body Damage',
+        'accuracy': 'Accuracy',
+        'magnet': 'Item Magnet',
+        'vision': "Crow's Nest"
       };
 
       let yOffset = coinsBarY + coinsBarHeight; // Start after coins bar
       const statOrder = [
         'hullStrength', 'autoRepairs', 'cannonRange', 'cannonDamage',
-        'reloadSpeed', 'moveSpeed', 'turnSpeed', 'bodyDamage'
+        'reloadSpeed', 'moveSpeed', 'turnSpeed', 'bodyDamage',
+        'accuracy', 'magnet', 'vision'
       ];
 
       statOrder.forEach((statKey, index) => {
@@ -2329,7 +3008,7 @@ class GameClient {
           const level = statUpgrade.level || 0;
           const maxLevel = 15;
           const cost = statUpgrade.currentCost || 10;
-          const keyNumber = index + 1;
+          const keyNumber = index < 10 ? (index + 1) % 10 : '-'; // The tenth stat is bound to 0, the eleventh to -
 
           // Individual upgrade bar with padding
           const barX = panelX;
@@ -2447,11 +3126,11 @@ class GameClient {
 
       const rank = index + 1;
       const name = player.name || `Player ${player.id}`;
-      const score = player.score || 0;
+      const score = player.kills ? `${player.kills}⚔ ${player.score || 0}` : `${player.score || 0}`;
 
       this.ctx.fillText(`${rank}. ${name}`, x + 10, y + 45 + index * 25);
       this.ctx.textAlign = 'right';
-      this.ctx.fillText(`${score}`, x + leaderboardWidth - 10, y + 45 + index * 25);
+      this.ctx.fillText(score, x + leaderboardWidth - 10, y + 45 + index * 25);
       this.ctx.textAlign = 'left';
     });
   }
@@ -2588,7 +3267,7 @@ class GameClient {
 
     // Collect available upgrade types
     const availableTypes = [];
-    const upgradeTypes = ['side', 'top', 'front', 'rear'];
+    const upgradeTypes = ['side', 'top', 'top2', 'front', 'rear'];
 
     for (const type of upgradeTypes) {
       if (this.hasAvailableUpgrades(type)) {
@@ -2802,13 +3481,25 @@ class GameClient {
     }
   }
 
+  sendChat(message) {
+    if (this.socket && this.socket.readyState === WebSocket.OPEN) {
+      this.socket.send(encode({
+        type: 'chat',
+        chatMessage: message.slice(0, 120)
+      }));
+    }
+  }
+
   sendStartGame() {
     if (this.socket && this.socket.readyState === WebSocket.OPEN) {
       this.socket.send(encode({
         type: 'startGame',
-        startGame: true
+        startGame: true,
+        shipClass: this.playerConfig.shipClass,
+        spectateKiller: this.playerConfig.spectateKiller
       }));
       this.hasStartedGame = true; // Mark that player has started the game
+      this.inLobby = false;
       console.log('Sent startGame message to server');
     } else {
       console.log('Cannot send startGame: socket not ready');
@@ -2833,6 +3524,65 @@ class GameClient {
     this.input.toggleAutofire = false;
   }
 
+  drawDashStatus() {
+    const player = this.gameState.myPlayer;
+    if (!player || player.state !== 0) return;
+
+    const remainingMs = (player.dashReadyAt || 0) - Date.now();
+    const text = remainingMs > 0 ? `Dash ${(remainingMs / 1000).toFixed(1)}s` : 'Dash ready (Space)';
+
+    this.ctx.save();
+    this.ctx.fillStyle = remainingMs > 0 ? 'rgba(255, 255, 255, 0.6)' : '#FFFFFF';
+    this.ctx.font = 'bold 13px Arial';
+    this.ctx.textAlign = 'left';
+    this.ctx.textBaseline = 'bottom';
+    this.ctx.fillText(text, 20, this.screenHeight - 52);
+    this.ctx.restore();
+  }
+
+  // Draws one reload bar per armed module, with the shots left for modules
+  // with magazines; cannons missing from the snapshot haven't fired yet and
+  // count as loaded
+  drawReloadStatus() {
+    const player = this.gameState.myPlayer;
+    if (!player || player.state !== 0 || !player.shipConfig) return;
+
+    const modules = [
+      ['Side', player.shipConfig.sideUpgrade],
+      ['Front', player.shipConfig.frontUpgrade],
+      ['Top', player.shipConfig.topUpgrade],
+    ];
+    const barWidth = 80;
+    let y = this.screenHeight - 120;
+
+    this.ctx.save();
+    this.ctx.font = 'bold 11px Arial';
+    this.ctx.textAlign = 'left';
+    this.ctx.textBaseline = 'middle';
+    for (const [label, module] of modules) {
+      const weapons = [...(module?.cannons || []), ...(module?.turrets || [])];
+      if (weapons.length === 0) continue;
+
+      const progress = Math.min(...weapons.map(w => w.reloadProgress ?? 1));
+      this.ctx.fillStyle = 'rgba(255, 255, 255, 0.8)';
+      this.ctx.fillText(label, 20, y);
+      this.ctx.fillStyle = 'rgba(0, 0, 0, 0.4)';
+      this.ctx.fillRect(60, y - 4, barWidth, 8);
+      this.ctx.fillStyle = progress >= 1 ? '#4CAF50' : '#FFC107';
+      this.ctx.fillRect(60, y - 4, barWidth * progress, 8);
+
+      // Shots left across the module's magazines, for burst weapons
+      const magazines = weapons.flatMap(w => [w, ...(w.cannons || [])]).filter(c => c.ammo !== undefined);
+      if (magazines.length > 0) {
+        const ammo = magazines.reduce((sum, c) => sum + c.ammo, 0);
+        this.ctx.fillStyle = ammo > 0 ? 'rgba(255, 255, 255, 0.8)' : '#FF7043';
+        this.ctx.fillText(`${ammo}`, 66 + barWidth, y);
+      }
+      y -= 14;
+    }
+    this.ctx.restore();
+  }
+
   drawAutofireStatus() {
     if (!this.gameState.myPlayer) return;
 
@@ -2844,7 +3594,7 @@ class GameClient {
 
     // Position at bottom left of screen
     const padding = 20;
-    const textWidth = 140;
+    const textWidth = 170;
     const textHeight = 24;
     const boxX = padding;
     const boxY = this.screenHeight - padding - textHeight;
@@ -2869,7 +3619,8 @@ class GameClient {
     this.ctx.font = 'bold 14px Arial';
     this.ctx.textAlign = 'center';
     this.ctx.textBaseline = 'middle';
-    this.ctx.fillText(`Autofire: ${autofireEnabled ? 'ON' : 'OFF'} (R)`, textX, textY);
+    const mode = player.aimedAutofire ? 'AIMED' : 'ON';
+    this.ctx.fillText(`Autofire: ${autofireEnabled ? mode : 'OFF'} (R/T)`, textX, textY);
 
     this.ctx.restore();
   }
@@ -2888,6 +3639,8 @@ class GameClient {
     if (deltaPlayer.state !== undefined) merged.state = deltaPlayer.state;
     if (deltaPlayer.name !== undefined) merged.name = deltaPlayer.name;
     if (deltaPlayer.color !== undefined) merged.color = deltaPlayer.color;
+    if (deltaPlayer.flag !== undefined) merged.flag = deltaPlayer.flag;
+    if (deltaPlayer.team !== undefined) merged.team = deltaPlayer.team;
     if (deltaPlayer.health !== undefined) merged.health = deltaPlayer.health;
     if (deltaPlayer.maxHealth !== undefined) merged.maxHealth = deltaPlayer.maxHealth;
     if (deltaPlayer.level !== undefined) merged.level = deltaPlayer.level;
@@ -2897,12 +3650,32 @@ class GameClient {
     if (deltaPlayer.coins !== undefined) merged.coins = deltaPlayer.coins;
     if (deltaPlayer.statUpgrades !== undefined) merged.statUpgrades = deltaPlayer.statUpgrades;
     if (deltaPlayer.autofireEnabled !== undefined) merged.autofireEnabled = deltaPlayer.autofireEnabled;
+    if (deltaPlayer.aimedAutofire !== undefined) merged.aimedAutofire = deltaPlayer.aimedAutofire;
     if (deltaPlayer.debugInfo !== undefined) merged.debugInfo = deltaPlayer.debugInfo;
     if (deltaPlayer.scoreAtDeath !== undefined) merged.scoreAtDeath = deltaPlayer.scoreAtDeath;
     if (deltaPlayer.survivalTime !== undefined) merged.survivalTime = deltaPlayer.survivalTime;
+    if (deltaPlayer.killedBy !== undefined) merged.killedBy = deltaPlayer.killedBy;
     if (deltaPlayer.killedByName !== undefined) merged.killedByName = deltaPlayer.killedByName;
+    if (deltaPlayer.burning !== undefined) merged.burning = deltaPlayer.burning;
+    if (deltaPlayer.tractorTargetId !== undefined) merged.tractorTargetId = deltaPlayer.tractorTargetId;
+    if (deltaPlayer.protected !== undefined) merged.protected = deltaPlayer.protected;
+    if (deltaPlayer.lastInputSeq !== undefined) merged.lastInputSeq = deltaPlayer.lastInputSeq;
+    if (deltaPlayer.lastActionSeq !== undefined) merged.lastActionSeq = deltaPlayer.lastActionSeq;
+    if (deltaPlayer.prestige !== undefined) merged.prestige = deltaPlayer.prestige;
+    if (deltaPlayer.kills !== undefined) merged.kills = deltaPlayer.kills;
+    if (deltaPlayer.isBounty !== undefined) merged.isBounty = deltaPlayer.isBounty;
+    if (deltaPlayer.activeBuffs !== undefined) merged.activeBuffs = deltaPlayer.activeBuffs;
+    if (deltaPlayer.sinking !== undefined) merged.sinking = deltaPlayer.sinking;
 
-    return merged;
+    return this.localizeTimers(merged, deltaPlayer);
+  }
+
+  // Turns the server's sink and dash timers, which count from the moment the
+  // snapshot was taken, into local clock times so clock skew doesn't matter
+  localizeTimers(player, serverPlayer) {
+    if (serverPlayer.sinkElapsed !== undefined) player.sinkStartedAt = Date.now() - serverPlayer.sinkElapsed;
+    if (serverPlayer.dashCooldown !== undefined) player.dashReadyAt = Date.now() + serverPlayer.dashCooldown;
+    return player;
   }
 
   // Merges a ship config delta into an existing ship config
@@ -2958,6 +3731,8 @@ class GameClient {
       state: deltaPlayer.state || 0,
       name: deltaPlayer.name || `Player ${deltaPlayer.id}`,
       color: deltaPlayer.color || '#FF6B6B',
+      flag: deltaPlayer.flag || '',
+      team: deltaPlayer.team || '',
       isBot: false, // Delta players are never bots
       health: deltaPlayer.health || 100,
       maxHealth: deltaPlayer.maxHealth || 100,
@@ -2968,10 +3743,24 @@ class GameClient {
       coins: deltaPlayer.coins || 0,
       statUpgrades: deltaPlayer.statUpgrades || {},
       autofireEnabled: deltaPlayer.autofireEnabled || false,
+      aimedAutofire: deltaPlayer.aimedAutofire || false,
       debugInfo: deltaPlayer.debugInfo || {},
       scoreAtDeath: deltaPlayer.scoreAtDeath || 0,
       survivalTime: deltaPlayer.survivalTime || 0,
-      killedByName: deltaPlayer.killedByName || ''
+      killedBy: deltaPlayer.killedBy || 0,
+      killedByName: deltaPlayer.killedByName || '',
+      burning: deltaPlayer.burning || false,
+      tractorTargetId: deltaPlayer.tractorTargetId || 0,
+      protected: deltaPlayer.protected || false,
+      lastInputSeq: deltaPlayer.lastInputSeq || 0,
+      lastActionSeq: deltaPlayer.lastActionSeq || 0,
+      prestige: deltaPlayer.prestige || 0,
+      kills: deltaPlayer.kills || 0,
+      isBounty: deltaPlayer.isBounty || false,
+      activeBuffs: deltaPlayer.activeBuffs || [],
+      sinking: deltaPlayer.sinking || false,
+      sinkStartedAt: Date.now() - (deltaPlayer.sinkElapsed || 0),
+      dashReadyAt: Date.now() + (deltaPlayer.dashCooldown || 0)
     };
   }
 }
@@ -2992,6 +3781,29 @@ function sanitizeHexColor(color) {
   return match ? `#${match[1].toUpperCase()}` : '';
 }
 
+// getAccountToken returns the private token that keys our cosmetic unlocks,
+// generating and storing one on first use
+function getAccountToken() {
+  return getStoredToken(localStorage, ACCOUNT_TOKEN_KEY);
+}
+
+// getStoredToken returns a random token kept under key in storage (localStorage
+// for the account, sessionStorage for this tab's loadout), creating it on first use
+function getStoredToken(storage, key) {
+  try {
+    let token = storage.getItem(key);
+    if (!token) {
+      const bytes = crypto.getRandomValues(new Uint8Array(16));
+      token = Array.from(bytes, (b) => b.toString(16).padStart(2, '0')).join('');
+      storage.setItem(key, token);
+    }
+    return token;
+  } catch (err) {
+    // Storage can be unavailable (e.g. private browsing); play without it
+    return '';
+  }
+}
+
 function generateRandomName() {
   const base = NAME_POOL[Math.floor(Math.random() * NAME_POOL.length)];
   const suffix = Math.floor(100 + Math.random() * 900);
@@ -3014,6 +3826,9 @@ class StartScreen {
     this.form = document.getElementById('startForm');
     this.nameInput = document.getElementById('playerName');
     this.colorInput = document.getElementById('playerColor');
+    this.classInput = document.getElementById('shipClass');
+    this.flagInput = document.getElementById('shipFlag');
+    this.flagRow = document.getElementById('flagRow');
     this.colorDisplay = document.getElementById('colorDisplay');
     this.swatchContainer = document.getElementById('presetColors');
     this.playButton = this.form ? this.form.querySelector('.play-button') : null;
@@ -3022,6 +3837,7 @@ class StartScreen {
 
     if (this.client) {
       this.client.setControlsLocked(true);
+      this.client.onUnlocks = (unlocks) => this.applyUnlocks(unlocks);
     }
 
     this.populateSwatches();
@@ -3036,13 +3852,13 @@ class StartScreen {
     this.registerEvents();
   }
 
-  populateSwatches() {
+  populateSwatches(colors = PRESET_COLORS) {
     if (!this.swatchContainer) {
       return;
     }
 
     this.swatchContainer.innerHTML = '';
-    PRESET_COLORS.forEach((hex) => {
+    colors.forEach((hex) => {
       const sanitized = sanitizeHexColor(hex);
       const button = document.createElement('button');
       button.type = 'button';
@@ -3057,6 +3873,38 @@ class StartScreen {
     });
   }
 
+  // applyUnlocks offers the account's colors and flags. In ranked mode only
+  // unlocked colors are accepted, so the free color picker is hidden.
+  applyUnlocks(unlocks) {
+    const colors = (unlocks.colors || []).map(sanitizeHexColor).filter(Boolean);
+    this.populateSwatches(colors.length > 0 ? colors : PRESET_COLORS);
+
+    const currentColor = this.colorInput ? sanitizeHexColor(this.colorInput.value) : '';
+    if (unlocks.ranked && colors.length > 0 && !colors.includes(currentColor)) {
+      this.applyColor(colors[0]);
+    } else {
+      this.applyColor(currentColor);
+    }
+    if (this.colorDisplay) {
+      this.colorDisplay.style.display = unlocks.ranked ? 'none' : '';
+    }
+
+    if (this.flagInput) {
+      const current = this.flagInput.value;
+      this.flagInput.innerHTML = '<option value="">None</option>';
+      (unlocks.flags || []).forEach((flag) => {
+        const option = document.createElement('option');
+        option.value = flag;
+        option.textContent = `${FLAG_ICONS[flag] || ''} ${flag}`.trim();
+        this.flagInput.appendChild(option);
+      });
+      this.flagInput.value = (unlocks.flags || []).includes(current) ? current : '';
+    }
+    if (this.flagRow) {
+      this.flagRow.style.display = (unlocks.flags || []).length > 0 ? '' : 'none';
+    }
+  }
+
   registerEvents() {
     if (this.form) {
       this.form.addEventListener('submit', (event) => {
@@ -3107,6 +3955,25 @@ class StartScreen {
     return sanitized;
   }
 
+  // Bring the start screen back after the player leaves for the lobby, so they
+  // can change their profile and set sail again
+  show() {
+    if (!this.element) {
+      return;
+    }
+
+    this.hasLaunched = false;
+    this.element.classList.remove('hidden');
+    document.body.classList.remove('has-launched');
+    if (this.playButton) {
+      this.playButton.disabled = false;
+      this.playButton.textContent = 'Set Sail';
+    }
+    if (this.client) {
+      this.client.setControlsLocked(true);
+    }
+  }
+
   launchGame() {
     if (this.hasLaunched) {
       return;
@@ -3114,6 +3981,8 @@ class StartScreen {
 
     const chosenName = this.applyName(this.nameInput ? this.nameInput.value : '');
     const chosenColor = this.applyColor(this.colorInput ? this.colorInput.value : PRESET_COLORS[2]);
+    const chosenClass = this.classInput ? this.classInput.value : 'sloop';
+    const chosenFlag = this.flagInput ? this.flagInput.value : '';
 
     if (this.playButton) {
       this.playButton.disabled = true;
@@ -3135,13 +4004,18 @@ class StartScreen {
       // Update profile locally
       this.client.playerConfig.name = chosenName;
       this.client.playerConfig.color = chosenColor;
+      this.client.playerConfig.shipClass = chosenClass;
+      this.client.playerConfig.flag = chosenFlag;
 
       // Send profile update to server
       if (this.client.socket && this.client.socket.readyState === WebSocket.OPEN) {
         this.client.socket.send(encode({
           type: 'profile',
           playerName: chosenName,
-          playerColor: chosenColor
+          playerColor: chosenColor,
+          shipClass: chosenClass,
+          playerFlag: chosenFlag,
+          spectateKiller: this.client.playerConfig.spectateKiller
         }));
       }
 
@@ -3160,6 +4034,8 @@ class StartScreen {
       window.goblonsClient = new GameClient({
         playerName: chosenName,
         playerColor: chosenColor,
+        shipClass: chosenClass,
+        playerFlag: chosenFlag,
         shouldStartGame: true // Flag to send startGame after connecting
       });
     }